@@ -0,0 +1,445 @@
+// Package archive reads and writes portable chain archives, which hold a sequence of blocks (with
+// their batches and transactions) exactly as they were signed.
+//
+// An archive starts with a header (ARCHIVE_MAGIC, a version and the compression used), followed by
+// one record per block. Each record is a serialized block_pb2.Block, optionally compressed, and
+// prefixed with its length as a varint. The records are followed by a JSON index record, and the
+// archive ends with a footer giving the offset of the index and repeating ARCHIVE_MAGIC.
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/block_pb2"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// ARCHIVE_MAGIC identifies an archive. It appears at the start and the end of the archive.
+const ARCHIVE_MAGIC = "SAWTARCH"
+
+// ARCHIVE_VERSION is the version of the archive format written by this package.
+const ARCHIVE_VERSION = 1
+
+// HEADER_LENGTH is the length of the archive header: the magic, the version and the compression.
+const HEADER_LENGTH = len(ARCHIVE_MAGIC) + 2
+
+// FOOTER_LENGTH is the length of the archive footer: the index offset and the magic.
+const FOOTER_LENGTH = 8 + len(ARCHIVE_MAGIC)
+
+// MAX_RECORD_LENGTH is the maximum length of a record accepted when reading an archive.
+const MAX_RECORD_LENGTH = 1 << 30
+
+// Compression is the compression applied to the block records of an archive.
+type Compression byte
+
+const (
+	COMPRESSION_NONE	Compression	= 0
+	COMPRESSION_GZIP	Compression	= 1
+)
+
+// IndexEntry locates a block within an archive.
+type IndexEntry struct {
+	BlockNum	uint64	`json:"block_num"`
+	BlockId		string	`json:"block_id"`
+	// Offset is the offset of the block's record from the start of the archive.
+	Offset		int64	`json:"offset"`
+	// Length is the length of the serialized block, before any compression.
+	Length		int64	`json:"length"`
+}
+
+// Index describes the contents of an archive. Blocks are listed in the order they were written.
+type Index struct {
+	Version			int				`json:"version"`
+	Compression		Compression		`json:"compression"`
+	Blocks			[]IndexEntry	`json:"blocks"`
+	Batches			int				`json:"batches"`
+	Transactions	int				`json:"transactions"`
+}
+
+// Writer writes an archive to an io.Writer.
+type Writer struct {
+	writer		io.Writer
+	offset		int64
+	index		Index
+	closed		bool
+}
+
+// NewWriter writes the archive header and returns a Writer.
+func NewWriter(writer io.Writer, compression Compression) (*Writer, error) {
+	if compression != COMPRESSION_NONE && compression != COMPRESSION_GZIP {
+		return nil, fmt.Errorf("Unknown compression: %d", compression)
+	}
+
+	self := &Writer{
+		writer: writer,
+		index: Index{Version: ARCHIVE_VERSION, Compression: compression, Blocks: []IndexEntry{}},
+	}
+
+	header := append([]byte(ARCHIVE_MAGIC), ARCHIVE_VERSION, byte(compression))
+	err := self.write(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return self, nil
+}
+
+// WriteBlock appends a block to the archive. The block is archived exactly as it was signed (see
+// types.Block.ToProto).
+func (self *Writer) WriteBlock(block *types.Block) error {
+	if self.closed {
+		return fmt.Errorf("Archive is closed")
+	}
+
+	blockProto, err := block.ToProto()
+	if err != nil {
+		return err
+	}
+	data, err := proto.Marshal(blockProto)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize block %d: %s", block.Header.BlockNum, err)
+	}
+
+	length := int64(len(data))
+	if self.index.Compression == COMPRESSION_GZIP {
+		var buffer bytes.Buffer
+		gzipWriter := gzip.NewWriter(&buffer)
+		_, err = gzipWriter.Write(data)
+		if err == nil {
+			err = gzipWriter.Close()
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to compress block %d: %s", block.Header.BlockNum, err)
+		}
+		data = buffer.Bytes()
+	}
+
+	entry := IndexEntry{BlockNum: block.Header.BlockNum, BlockId: block.HeaderSignature, Offset: self.offset, Length: length}
+	err = self.writeRecord(data)
+	if err != nil {
+		return err
+	}
+
+	self.index.Blocks = append(self.index.Blocks, entry)
+	self.index.Batches += len(block.Batches)
+	for _, batch := range block.Batches {
+		self.index.Transactions += len(batch.Transactions)
+	}
+
+	return nil
+}
+
+// Index returns the index of the blocks written so far.
+func (self *Writer) Index() *Index {
+	index := self.index
+	index.Blocks = append([]IndexEntry{}, self.index.Blocks...)
+	return &index
+}
+
+// Close writes the index and footer, completing the archive. It does not close the underlying
+// io.Writer.
+func (self *Writer) Close() error {
+	if self.closed {
+		return nil
+	}
+	self.closed = true
+
+	data, err := json.Marshal(&self.index)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize archive index: %s", err)
+	}
+
+	indexOffset := self.offset
+	err = self.writeRecord(data)
+	if err != nil {
+		return err
+	}
+
+	footer := binary.BigEndian.AppendUint64(nil, uint64(indexOffset))
+	return self.write(append(footer, ARCHIVE_MAGIC...))
+}
+
+// writeRecord writes data prefixed with its length.
+func (self *Writer) writeRecord(data []byte) error {
+	record := binary.AppendUvarint(nil, uint64(len(data)))
+	return self.write(append(record, data...))
+}
+
+// write writes data and advances the offset.
+func (self *Writer) write(data []byte) error {
+	n, err := self.writer.Write(data)
+	self.offset += int64(n)
+	if err != nil {
+		return fmt.Errorf("Failed to write archive: %s", err)
+	}
+
+	return nil
+}
+
+// Reader reads an archive.
+type Reader struct {
+	reader		io.ReaderAt
+	size		int64
+	closer		io.Closer
+	index		Index
+
+	blockNums	map[uint64]int
+	blockIds	map[string]int
+}
+
+// NewReader reads the archive header and index from an io.ReaderAt of the given size.
+func NewReader(reader io.ReaderAt, size int64) (*Reader, error) {
+	if size < int64(HEADER_LENGTH + FOOTER_LENGTH) {
+		return nil, fmt.Errorf("Not an archive (too short)")
+	}
+
+	header := make([]byte, HEADER_LENGTH)
+	_, err := reader.ReadAt(header, 0)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read archive header: %s", err)
+	}
+	if string(header[:len(ARCHIVE_MAGIC)]) != ARCHIVE_MAGIC {
+		return nil, fmt.Errorf("Not an archive (bad magic)")
+	}
+	if header[len(ARCHIVE_MAGIC)] != ARCHIVE_VERSION {
+		return nil, fmt.Errorf("Unsupported archive version: %d", header[len(ARCHIVE_MAGIC)])
+	}
+
+	footer := make([]byte, FOOTER_LENGTH)
+	_, err = reader.ReadAt(footer, size - int64(FOOTER_LENGTH))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read archive footer: %s", err)
+	}
+	if string(footer[8:]) != ARCHIVE_MAGIC {
+		return nil, fmt.Errorf("Archive is incomplete (bad footer)")
+	}
+
+	self := &Reader{
+		reader: reader,
+		size: size,
+		blockNums: make(map[uint64]int),
+		blockIds: make(map[string]int),
+	}
+
+	indexOffset := int64(binary.BigEndian.Uint64(footer[:8]))
+	data, err := self.readRecord(indexOffset)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read archive index: %s", err)
+	}
+	err = json.Unmarshal(data, &self.index)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode archive index: %s", err)
+	}
+	if Compression(header[len(ARCHIVE_MAGIC) + 1]) != self.index.Compression {
+		return nil, fmt.Errorf("Archive header and index disagree on compression")
+	}
+
+	for i, entry := range self.index.Blocks {
+		self.blockNums[entry.BlockNum] = i
+		self.blockIds[entry.BlockId] = i
+	}
+
+	return self, nil
+}
+
+// Open opens the archive file at path. The Reader must be closed when no longer needed.
+func Open(path string) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	self, err := NewReader(file, info.Size())
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	self.closer = file
+
+	return self, nil
+}
+
+// Close closes the archive file, if the Reader was created by Open.
+func (self *Reader) Close() error {
+	if self.closer != nil {
+		return self.closer.Close()
+	}
+
+	return nil
+}
+
+// Index returns the index of the archive.
+func (self *Reader) Index() *Index {
+	return &self.index
+}
+
+// ReadBlockProto reads the block at an index entry, exactly as it was archived.
+func (self *Reader) ReadBlockProto(entry *IndexEntry) (*block_pb2.Block, error) {
+	data, err := self.readRecord(entry.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read block %d: %s", entry.BlockNum, err)
+	}
+
+	if entry.Length < 0 || entry.Length > MAX_RECORD_LENGTH {
+		return nil, fmt.Errorf("Block %d has invalid length %d", entry.BlockNum, entry.Length)
+	}
+
+	if self.index.Compression == COMPRESSION_GZIP {
+		gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decompress block %d: %s", entry.BlockNum, err)
+		}
+		// Read one byte more than expected, so a longer block is detected without reading it all
+		data, err = ioutil.ReadAll(io.LimitReader(gzipReader, entry.Length + 1))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decompress block %d: %s", entry.BlockNum, err)
+		}
+	}
+	if int64(len(data)) != entry.Length {
+		return nil, fmt.Errorf("Block %d has length %d, but the archive index records %d", entry.BlockNum, len(data), entry.Length)
+	}
+
+	var blockProto block_pb2.Block
+	err = proto.Unmarshal(data, &blockProto)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode block %d: %s", entry.BlockNum, err)
+	}
+
+	return &blockProto, nil
+}
+
+// ReadBlock reads the block at an index entry, checking that it is the block the index describes.
+func (self *Reader) ReadBlock(entry *IndexEntry) (*types.Block, error) {
+	blockProto, err := self.ReadBlockProto(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := types.BlockFromProto(blockProto)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode block %d: %s", entry.BlockNum, err)
+	}
+	if block.HeaderSignature != entry.BlockId || block.Header.BlockNum != entry.BlockNum {
+		return nil, fmt.Errorf("Archive index does not match block %d (%s)", block.Header.BlockNum, block.HeaderSignature)
+	}
+
+	return block, nil
+}
+
+// GetBlock reads the block with the given id, returning false if it is not in the archive.
+func (self *Reader) GetBlock(blockId string) (*types.Block, bool, error) {
+	i, ok := self.blockIds[blockId]
+	if !ok {
+		return nil, false, nil
+	}
+
+	block, err := self.ReadBlock(&self.index.Blocks[i])
+	if err != nil {
+		return nil, false, err
+	}
+
+	return block, true, nil
+}
+
+// GetBlockByNum reads the block with the given number, returning false if it is not in the archive.
+func (self *Reader) GetBlockByNum(blockNum uint64) (*types.Block, bool, error) {
+	i, ok := self.blockNums[blockNum]
+	if !ok {
+		return nil, false, nil
+	}
+
+	block, err := self.ReadBlock(&self.index.Blocks[i])
+	if err != nil {
+		return nil, false, err
+	}
+
+	return block, true, nil
+}
+
+// Blocks returns an iterator over the blocks of the archive, in the order they were written.
+func (self *Reader) Blocks() types.Iterator[*types.Block] {
+	return &blockIterator{reader: self, next: 0}
+}
+
+// readRecord reads the length-prefixed record at offset.
+func (self *Reader) readRecord(offset int64) ([]byte, error) {
+	if offset < int64(HEADER_LENGTH) || offset >= self.size - int64(FOOTER_LENGTH) {
+		return nil, fmt.Errorf("Record offset %d is out of range", offset)
+	}
+
+	available := self.size - int64(FOOTER_LENGTH) - offset
+	section := bufio.NewReader(io.NewSectionReader(self.reader, offset, available))
+	length, err := binary.ReadUvarint(section)
+	if err != nil {
+		return nil, err
+	}
+	if length > MAX_RECORD_LENGTH {
+		return nil, fmt.Errorf("Record length %d is too large", length)
+	}
+
+	// Check the length against the rest of the archive before allocating the record
+	remaining := available - int64(len(binary.AppendUvarint(nil, length)))
+	if int64(length) > remaining {
+		return nil, fmt.Errorf("Record length %d exceeds the %d bytes remaining in the archive", length, remaining)
+	}
+
+	data := make([]byte, length)
+	_, err = io.ReadFull(section, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// blockIterator implements types.Iterator for Reader.Blocks.
+type blockIterator struct {
+	reader		*Reader
+	next		int
+
+	current		*types.Block
+	err			error
+}
+
+func (self *blockIterator) Next() bool {
+	self.current = nil
+	if self.err != nil || self.next >= len(self.reader.index.Blocks) {
+		return false
+	}
+
+	self.current, self.err = self.reader.ReadBlock(&self.reader.index.Blocks[self.next])
+	self.next++
+
+	return self.err == nil
+}
+
+func (self *blockIterator) Current() (*types.Block, error) {
+	if self.current == nil {
+		return nil, fmt.Errorf("No current value in iterator...")
+	}
+
+	return self.current, nil
+}
+
+func (self *blockIterator) Error() error {
+	return self.err
+}
+
+func (self *blockIterator) Close() {
+	self.next = len(self.reader.index.Blocks)
+}
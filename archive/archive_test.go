@@ -0,0 +1,136 @@
+package archive_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/taekion-org/sawtooth-client-sdk-go/archive"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/conformance"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+func TestArchive(t *testing.T) {
+	fixture := conformance.NewFixture()
+	clientTransport := conformance.NewRestTransport(t, conformance.NewValidator(fixture))
+
+	for _, compression := range []archive.Compression{archive.COMPRESSION_NONE, archive.COMPRESSION_GZIP} {
+		var buffer bytes.Buffer
+		index, err := archive.Export(clientTransport, &buffer, &archive.ExportOptions{Fetch: 5, Compression: compression})
+		if err != nil {
+			t.Fatalf("Export(%d): %s", compression, err)
+		}
+		if len(index.Blocks) != conformance.FIXTURE_BLOCKS || index.Transactions != len(fixture.TransactionsAt(conformance.FIXTURE_BLOCKS - 1)) {
+			t.Fatalf("Export(%d): unexpected index %+v", compression, index)
+		}
+
+		reader, err := archive.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+		if err != nil {
+			t.Fatalf("NewReader(%d): %s", compression, err)
+		}
+
+		result, err := archive.Verify(reader)
+		if err != nil {
+			t.Fatalf("Verify(%d): %s", compression, err)
+		}
+		if result.Blocks != conformance.FIXTURE_BLOCKS || result.Head != fixture.Head().HeaderSignature {
+			t.Fatalf("Verify(%d): unexpected result %+v", compression, result)
+		}
+
+		// Blocks are archived exactly as they were signed
+		for i, expected := range fixture.Blocks {
+			block, err := reader.ReadBlockProto(&reader.Index().Blocks[i])
+			if err != nil {
+				t.Fatalf("ReadBlockProto(%d): %s", i, err)
+			}
+			if !proto.Equal(block, expected) {
+				t.Fatalf("ReadBlockProto(%d): archived block differs", i)
+			}
+		}
+		blocks, err := types.Collect(reader.Blocks())
+		if err != nil {
+			t.Fatalf("Blocks(%d): %s", compression, err)
+		}
+		if len(blocks) != len(fixture.Blocks) {
+			t.Fatalf("Blocks(%d): got %d blocks, expected %d", compression, len(blocks), len(fixture.Blocks))
+		}
+		for i, block := range blocks {
+			expected, _ := types.BlockFromProto(fixture.Blocks[i])
+			if !reflect.DeepEqual(block, expected) {
+				t.Fatalf("Blocks(%d): block %d differs", compression, i)
+			}
+		}
+
+		block, ok, err := reader.GetBlock(fixture.Blocks[3].HeaderSignature)
+		if err != nil || !ok || block.Header.BlockNum != 3 {
+			t.Fatalf("GetBlock(3): got %v, %v, %v", block, ok, err)
+		}
+		_, ok, err = reader.GetBlockByNum(conformance.FIXTURE_BLOCKS)
+		if err != nil || ok {
+			t.Fatalf("GetBlockByNum(missing): got %v, %v", ok, err)
+		}
+
+		// Blocks must have the length recorded in the index
+		entry := reader.Index().Blocks[0]
+		for _, length := range []int64{entry.Length - 1, entry.Length + 1, -1} {
+			_, err = reader.ReadBlockProto(&archive.IndexEntry{BlockNum: entry.BlockNum, BlockId: entry.BlockId, Offset: entry.Offset, Length: length})
+			if err == nil {
+				t.Fatalf("ReadBlockProto(%d, length %d): expected an error", compression, length)
+			}
+		}
+
+		// A record cannot extend past the end of the archive
+		oversized := append([]byte(nil), buffer.Bytes()...)
+		binary.PutUvarint(oversized[entry.Offset:], archive.MAX_RECORD_LENGTH)
+		oversizedReader, err := archive.NewReader(bytes.NewReader(oversized), int64(len(oversized)))
+		if err != nil {
+			t.Fatalf("NewReader(%d, oversized): %s", compression, err)
+		}
+		_, err = oversizedReader.ReadBlockProto(&entry)
+		if err == nil || !strings.Contains(err.Error(), "remaining") {
+			t.Fatalf("ReadBlockProto(%d, oversized): expected an error, got: %v", compression, err)
+		}
+
+		// Any corruption must be detected
+		corrupt := append([]byte(nil), buffer.Bytes()...)
+		corrupt[reader.Index().Blocks[conformance.FIXTURE_BLOCKS / 2].Offset + 40] ^= 0xff
+		corruptReader, err := archive.NewReader(bytes.NewReader(corrupt), int64(len(corrupt)))
+		if err == nil {
+			_, err = archive.Verify(corruptReader)
+		}
+		if err == nil {
+			t.Fatalf("Verify(%d, corrupt): expected an error", compression)
+		}
+	}
+
+	// A partial archive starts from a checkpoint
+	var buffer bytes.Buffer
+	_, err := archive.Export(clientTransport, &buffer, &archive.ExportOptions{FirstBlockNum: 4, Head: fixture.Blocks[9].HeaderSignature})
+	if err != nil {
+		t.Fatalf("Export(4-9): %s", err)
+	}
+	reader, err := archive.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatalf("NewReader(4-9): %s", err)
+	}
+	result, err := archive.Verify(reader)
+	if err != nil {
+		t.Fatalf("Verify(4-9): %s", err)
+	}
+	if result.FirstBlockNum != 4 || result.LastBlockNum != 9 || result.Blocks != 6 {
+		t.Fatalf("Verify(4-9): unexpected result %+v", result)
+	}
+
+	// Importing resubmits every batch (which the fixture validator reports as committed)
+	submitted, err := archive.Import(reader, clientTransport, &archive.ImportOptions{Wait: 1})
+	if err != nil {
+		t.Fatalf("Import(4-9): %s", err)
+	}
+	expected := len(fixture.BatchesAt(9)) - len(fixture.BatchesAt(3))
+	if submitted != expected {
+		t.Fatalf("Import(4-9): submitted %d batches, expected %d", submitted, expected)
+	}
+}
@@ -0,0 +1,192 @@
+package archive
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// ExportOptions holds optional settings for Export.
+type ExportOptions struct {
+	// FirstBlockNum is the number of the first block to export.
+	FirstBlockNum	uint64
+	// Head is the id of the last block to export. If empty, the current chain head is used.
+	Head			string
+	// Fetch is the page size used to read blocks (0 uses the transport default).
+	Fetch			int
+	// Compression is the compression applied to the archived blocks.
+	Compression		Compression
+	// Progress is called (if set) after each block has been written.
+	Progress		func(block *types.Block)
+}
+
+// Export writes the blocks from FirstBlockNum up to the head to an archive, oldest first. All
+// blocks are read from the same fork, even if the chain head moves during the export.
+func Export(clientTransport transport.SawtoothClientTransport, writer io.Writer, options *ExportOptions) (*Index, error) {
+	var exportOptions ExportOptions
+	if options != nil {
+		exportOptions = *options
+	}
+
+	headId := exportOptions.Head
+	if headId == "" {
+		var err error
+		headId, err = transport.CurrentHead(clientTransport)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	head, err := clientTransport.GetBlock(headId)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := types.NewBlockRangeCursor(exportOptions.FirstBlockNum, head.Header.BlockNum, exportOptions.Fetch, true)
+	if err != nil {
+		return nil, err
+	}
+	cursor.Head = headId
+
+	archiveWriter, err := NewWriter(writer, exportOptions.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	iterator := clientTransport.GetBlockIteratorFromCursor(cursor)
+	defer iterator.Close()
+
+	for iterator.Next() {
+		block, err := iterator.Current()
+		if err != nil {
+			return nil, err
+		}
+
+		err = archiveWriter.WriteBlock(block)
+		if err != nil {
+			return nil, err
+		}
+
+		if exportOptions.Progress != nil {
+			exportOptions.Progress(block)
+		}
+	}
+
+	err = iterator.Error()
+	if err != nil {
+		return nil, err
+	}
+
+	err = archiveWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return archiveWriter.Index(), nil
+}
+
+// Verify reads every block of the archive and checks it as transport.VerifyChain would: blocks
+// must be consecutive and correctly linked, with valid signatures and no duplicate transactions.
+// The first block is treated as a trusted checkpoint, unless it is the genesis block.
+func Verify(reader *Reader) (*transport.ChainVerificationResult, error) {
+	verifier := transport.NewChainVerifier()
+
+	iterator := reader.Blocks()
+	defer iterator.Close()
+
+	for iterator.Next() {
+		block, err := iterator.Current()
+		if err != nil {
+			return verifier.Result(), err
+		}
+
+		if verifier.Last() == nil && block.Header.BlockNum > 0 {
+			err = verifier.AddCheckpoint(block)
+		} else {
+			err = verifier.AddBlock(block)
+		}
+		if err != nil {
+			return verifier.Result(), err
+		}
+	}
+
+	err := iterator.Error()
+	if err != nil {
+		return verifier.Result(), err
+	}
+	if verifier.Last() == nil {
+		return verifier.Result(), fmt.Errorf("Archive is empty")
+	}
+
+	return verifier.Result(), nil
+}
+
+// ImportOptions holds optional settings for Import.
+type ImportOptions struct {
+	// Wait is the time (in seconds) to wait for the batches of each block to be committed before
+	// submitting the next block's. If zero, batches are submitted without waiting.
+	Wait			int
+	// Progress is called (if set) after the batches of each block have been submitted.
+	Progress		func(block *types.Block)
+}
+
+// Import submits the batches of each archived block, in order, to the validator behind the
+// transport (for example to seed a test network). It returns the number of batches submitted.
+//
+// The batches are submitted exactly as they were signed, so the target network must accept the
+// original signers. When the target has its own genesis block, export from block 1.
+func Import(reader *Reader, clientTransport transport.SawtoothClientTransport, options *ImportOptions) (int, error) {
+	var importOptions ImportOptions
+	if options != nil {
+		importOptions = *options
+	}
+
+	submitted := 0
+
+	for i := range reader.Index().Blocks {
+		entry := &reader.Index().Blocks[i]
+		blockProto, err := reader.ReadBlockProto(entry)
+		if err != nil {
+			return submitted, err
+		}
+		if len(blockProto.Batches) == 0 {
+			continue
+		}
+
+		err = clientTransport.SubmitBatchList(&batch_pb2.BatchList{Batches: blockProto.Batches})
+		if err != nil {
+			return submitted, fmt.Errorf("Failed to submit the batches of block %d: %s", entry.BlockNum, err)
+		}
+		submitted += len(blockProto.Batches)
+
+		if importOptions.Wait > 0 {
+			batchIds := make([]string, len(blockProto.Batches))
+			for i, batch := range blockProto.Batches {
+				batchIds[i] = batch.HeaderSignature
+			}
+
+			statuses, err := clientTransport.GetBatchStatusMultiple(batchIds, importOptions.Wait)
+			if err != nil {
+				return submitted, err
+			}
+			for _, batchId := range batchIds {
+				if statuses[batchId] != types.BATCH_STATUS_COMMITTED {
+					return submitted, fmt.Errorf("Batch %s of block %d was not committed (status: %s)", batchId, entry.BlockNum, statuses[batchId])
+				}
+			}
+		}
+
+		if importOptions.Progress != nil {
+			block, err := types.BlockFromProto(blockProto)
+			if err != nil {
+				return submitted, err
+			}
+			importOptions.Progress(block)
+		}
+	}
+
+	return submitted, nil
+}
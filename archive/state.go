@@ -0,0 +1,261 @@
+package archive
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_state_pb2"
+	"github.com/taekion-org/sawtooth-client-sdk-go/merkle"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// STATE_SNAPSHOT_MAGIC identifies a state snapshot.
+//
+// A state snapshot starts with STATE_SNAPSHOT_MAGIC and a version, followed by a JSON header
+// record and one record per state entry, in ascending address order. Each entry record is a
+// serialized client_state_pb2.ClientStateListResponse_Entry prefixed with its length as a varint.
+// The snapshot ends with an empty record.
+const STATE_SNAPSHOT_MAGIC = "SAWTSTAT"
+
+// STATE_SNAPSHOT_VERSION is the version of the state snapshot format written by this package.
+const STATE_SNAPSHOT_VERSION = 1
+
+// StateSnapshotHeader identifies the block whose state a snapshot holds.
+type StateSnapshotHeader struct {
+	Head		string	`json:"head"`
+	BlockNum	uint64	`json:"block_num"`
+	StateRoot	string	`json:"state_root"`
+}
+
+// StateSnapshot holds the complete state of the chain at a block.
+type StateSnapshot struct {
+	StateSnapshotHeader
+	// States holds every state entry, in ascending address order, with Head set to the snapshot head.
+	States		[]*types.State
+}
+
+// ExportStateOptions holds optional settings for ExportState.
+type ExportStateOptions struct {
+	// Head is the id of the block whose state is exported. If empty, the current chain head is used.
+	Head		string
+	// Fetch is the page size used to read state (0 uses the transport default).
+	Fetch		int
+}
+
+// ExportState writes a snapshot of the complete state at a block. The state read is checked
+// against the block's state root hash before the snapshot is completed.
+func ExportState(clientTransport transport.SawtoothClientTransport, writer io.Writer, options *ExportStateOptions) (*StateSnapshotHeader, error) {
+	var exportOptions ExportStateOptions
+	if options != nil {
+		exportOptions = *options
+	}
+
+	headId := exportOptions.Head
+	if headId == "" {
+		var err error
+		headId, err = transport.CurrentHead(clientTransport)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	head, err := clientTransport.GetBlock(headId)
+	if err != nil {
+		return nil, err
+	}
+
+	header := &StateSnapshotHeader{Head: head.HeaderSignature, BlockNum: head.Header.BlockNum, StateRoot: head.Header.StateRootHash}
+	data, err := json.Marshal(header)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to serialize state snapshot header: %s", err)
+	}
+
+	bufferedWriter := bufio.NewWriter(writer)
+	_, err = bufferedWriter.Write(append([]byte(STATE_SNAPSHOT_MAGIC), STATE_SNAPSHOT_VERSION))
+	if err == nil {
+		err = writeStateRecord(bufferedWriter, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tree := merkle.NewTree()
+	iterator := clientTransport.GetStateIteratorAtHead("", headId, exportOptions.Fetch, false)
+	defer iterator.Close()
+
+	for iterator.Next() {
+		state, err := iterator.Current()
+		if err != nil {
+			return nil, err
+		}
+
+		err = tree.Set(state.Address, state.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := proto.Marshal(&client_state_pb2.ClientStateListResponse_Entry{Address: state.Address, Data: state.Data})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to serialize state %s: %s", state.Address, err)
+		}
+		err = writeStateRecord(bufferedWriter, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = iterator.Error()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tree.CheckStateRoot(&head.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writeStateRecord(bufferedWriter, nil)
+	if err == nil {
+		err = bufferedWriter.Flush()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to write state snapshot: %s", err)
+	}
+
+	return header, nil
+}
+
+// ReadStateSnapshot reads a state snapshot, checking that its entries hash to its state root.
+func ReadStateSnapshot(reader io.Reader) (*StateSnapshot, error) {
+	bufferedReader := bufio.NewReader(reader)
+
+	magic := make([]byte, len(STATE_SNAPSHOT_MAGIC) + 1)
+	_, err := io.ReadFull(bufferedReader, magic)
+	if err != nil || string(magic[:len(STATE_SNAPSHOT_MAGIC)]) != STATE_SNAPSHOT_MAGIC {
+		return nil, fmt.Errorf("Not a state snapshot (bad magic)")
+	}
+	if magic[len(STATE_SNAPSHOT_MAGIC)] != STATE_SNAPSHOT_VERSION {
+		return nil, fmt.Errorf("Unsupported state snapshot version: %d", magic[len(STATE_SNAPSHOT_MAGIC)])
+	}
+
+	data, err := readStateRecord(bufferedReader)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read state snapshot header: %s", err)
+	}
+
+	snapshot := &StateSnapshot{States: []*types.State{}}
+	err = json.Unmarshal(data, &snapshot.StateSnapshotHeader)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode state snapshot header: %s", err)
+	}
+
+	tree := merkle.NewTree()
+	for {
+		data, err := readStateRecord(bufferedReader)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read state snapshot: %s", err)
+		}
+		if len(data) == 0 {
+			break
+		}
+
+		var entry client_state_pb2.ClientStateListResponse_Entry
+		err = proto.Unmarshal(data, &entry)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode state snapshot entry: %s", err)
+		}
+
+		count := len(snapshot.States)
+		if count > 0 && entry.Address <= snapshot.States[count - 1].Address {
+			return nil, fmt.Errorf("State snapshot entries are out of order at %s", entry.Address)
+		}
+
+		err = tree.Set(entry.Address, entry.Data)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.States = append(snapshot.States, &types.State{Address: entry.Address, Data: entry.Data, Head: snapshot.Head})
+	}
+
+	err = tree.CheckStateRoot(&types.BlockHeader{BlockNum: snapshot.BlockNum, StateRootHash: snapshot.StateRoot})
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// OpenStateSnapshot reads the state snapshot file at path (see ReadStateSnapshot).
+func OpenStateSnapshot(path string) (*StateSnapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadStateSnapshot(file)
+}
+
+// Get returns the state at an address, and false if the address is not set.
+func (self *StateSnapshot) Get(address string) (*types.State, bool) {
+	i := sort.Search(len(self.States), func(i int) bool {
+		return self.States[i].Address >= address
+	})
+	if i < len(self.States) && self.States[i].Address == address {
+		return self.States[i], true
+	}
+
+	return nil, false
+}
+
+// List returns the states whose address starts with prefix, in ascending address order.
+func (self *StateSnapshot) List(prefix string) []*types.State {
+	first := sort.Search(len(self.States), func(i int) bool {
+		return self.States[i].Address >= prefix
+	})
+
+	last := first
+	for last < len(self.States) && strings.HasPrefix(self.States[last].Address, prefix) {
+		last++
+	}
+
+	return self.States[first:last]
+}
+
+// writeStateRecord writes data prefixed with its length.
+func writeStateRecord(writer io.Writer, data []byte) error {
+	record := binary.AppendUvarint(nil, uint64(len(data)))
+	_, err := writer.Write(append(record, data...))
+	if err != nil {
+		return fmt.Errorf("Failed to write state snapshot: %s", err)
+	}
+
+	return nil
+}
+
+// readStateRecord reads a length-prefixed record.
+func readStateRecord(reader *bufio.Reader) ([]byte, error) {
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, err
+	}
+	if length > MAX_RECORD_LENGTH {
+		return nil, fmt.Errorf("Record length %d is too large", length)
+	}
+
+	data := make([]byte, length)
+	_, err = io.ReadFull(reader, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,742 @@
+package archive
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/errors"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// headerSignaturePattern matches a well-formed block, batch or transaction id.
+var headerSignaturePattern = regexp.MustCompile("^[0-9a-f]{128}$")
+
+// addressPattern matches a well-formed state address.
+var addressPattern = regexp.MustCompile(fmt.Sprintf("^[0-9a-f]{%d}$", types.ADDRESS_LENGTH))
+
+// addressPrefixPattern matches a well-formed state address prefix, made of whole two-character tokens.
+var addressPrefixPattern = regexp.MustCompile(fmt.Sprintf("^([0-9a-f]{2}){0,%d}$", types.ADDRESS_LENGTH / 2))
+
+func init() {
+	transport.RegisterSawtoothClientTransport(transport.TRANSPORT_ARCHIVE, func(url *url.URL) (transport.SawtoothClientTransport, error) {
+		return NewSawtoothClientTransportArchiveFromUrl(url)
+	})
+}
+
+// SawtoothClientTransportArchive implements transport.SawtoothClientTransport over a chain archive,
+// and optionally a state snapshot, so that clients can query historic data without a validator.
+//
+// The last archived block is treated as the chain head, and every archived batch is reported as
+// committed. State can only be read at the block of the snapshot, which is also used when no head
+// is given. The transport is read-only: SubmitBatchList fails with errors.TRANSPORT_READ_ONLY.
+type SawtoothClientTransportArchive struct {
+	reader			*Reader
+	snapshot		*StateSnapshot
+
+	// batchIds and transactionIds list the ids in each archived block, by index entry.
+	batchIds		[][]string
+	transactionIds	[][]string
+
+	batches			map[string]archiveLocation
+	transactions	map[string]archiveLocation
+	stateRoots		map[string]bool
+}
+
+// archiveLocation locates a batch or transaction within the archive.
+type archiveLocation struct {
+	entry		int
+	batch		int
+	transaction	int
+}
+
+// NewSawtoothClientTransportArchive returns a transport serving the blocks of an archive, and the
+// state of a snapshot (which may be nil). The archived blocks must be consecutive, and the
+// snapshot must be of an archived block.
+func NewSawtoothClientTransportArchive(reader *Reader, snapshot *StateSnapshot) (*SawtoothClientTransportArchive, error) {
+	entries := reader.Index().Blocks
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("Archive is empty")
+	}
+
+	self := &SawtoothClientTransportArchive{
+		reader: reader,
+		snapshot: snapshot,
+		batchIds: make([][]string, len(entries)),
+		transactionIds: make([][]string, len(entries)),
+		batches: make(map[string]archiveLocation),
+		transactions: make(map[string]archiveLocation),
+		stateRoots: make(map[string]bool),
+	}
+
+	snapshotFound := false
+	for i := range entries {
+		if entries[i].BlockNum != entries[0].BlockNum + uint64(i) {
+			return nil, fmt.Errorf("Archive blocks are not consecutive at block %d", entries[i].BlockNum)
+		}
+
+		block, err := reader.ReadBlock(&entries[i])
+		if err != nil {
+			return nil, err
+		}
+
+		for b, batch := range block.Batches {
+			self.batchIds[i] = append(self.batchIds[i], batch.HeaderSignature)
+			self.batches[batch.HeaderSignature] = archiveLocation{entry: i, batch: b}
+
+			for t, transaction := range batch.Transactions {
+				self.transactionIds[i] = append(self.transactionIds[i], transaction.HeaderSignature)
+				self.transactions[transaction.HeaderSignature] = archiveLocation{entry: i, batch: b, transaction: t}
+			}
+		}
+		self.stateRoots[block.Header.StateRootHash] = true
+
+		if snapshot != nil && block.HeaderSignature == snapshot.Head {
+			if block.Header.StateRootHash != snapshot.StateRoot {
+				return nil, fmt.Errorf("State snapshot root %s does not match block %d", snapshot.StateRoot, block.Header.BlockNum)
+			}
+			snapshotFound = true
+		}
+	}
+
+	if snapshot != nil && !snapshotFound {
+		return nil, fmt.Errorf("State snapshot head %s is not in the archive", snapshot.Head)
+	}
+
+	return self, nil
+}
+
+// OpenSawtoothClientTransportArchive opens the archive file at path, and the state snapshot file
+// at snapshotPath (if not empty), and returns a transport serving them. The transport must be
+// closed when no longer needed.
+func OpenSawtoothClientTransportArchive(path string, snapshotPath string) (*SawtoothClientTransportArchive, error) {
+	var snapshot *StateSnapshot
+	if snapshotPath != "" {
+		var err error
+		snapshot, err = OpenStateSnapshot(snapshotPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reader, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	self, err := NewSawtoothClientTransportArchive(reader, snapshot)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+
+	return self, nil
+}
+
+// NewSawtoothClientTransportArchiveFromUrl opens the archive given by a URL of the form
+// file:///path/to/archive?snapshot=/path/to/snapshot (the snapshot is optional). This is the
+// constructor used for transport.TRANSPORT_ARCHIVE.
+func NewSawtoothClientTransportArchiveFromUrl(url *url.URL) (*SawtoothClientTransportArchive, error) {
+	if url.Scheme != "" && url.Scheme != "file" {
+		return nil, fmt.Errorf("Unsupported archive URL scheme: %s", url.Scheme)
+	}
+	if url.Path == "" {
+		return nil, fmt.Errorf("Archive URL has no path")
+	}
+
+	return OpenSawtoothClientTransportArchive(url.Path, url.Query().Get("snapshot"))
+}
+
+// Close closes the archive file, if the transport was opened from a file.
+func (self *SawtoothClientTransportArchive) Close() error {
+	return self.reader.Close()
+}
+
+// Snapshot returns the state snapshot served by the transport, or nil if there is none.
+func (self *SawtoothClientTransportArchive) Snapshot() *StateSnapshot {
+	return self.snapshot
+}
+
+// GetBatch returns the archived batch represented by batchId.
+func (self *SawtoothClientTransportArchive) GetBatch(batchId string) (*types.Batch, error) {
+	if !headerSignaturePattern.MatchString(batchId) {
+		return nil, newError(errors.INVALID_RESOURCE_ID, "Invalid batch id: %s", batchId)
+	}
+
+	location, ok := self.batches[batchId]
+	if !ok {
+		return nil, newError(errors.BATCH_NOT_FOUND, "Batch not found: %s", batchId)
+	}
+
+	block, err := self.readBlock(location.entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &block.Batches[location.batch], nil
+}
+
+// GetBatchIterator returns a types.BatchIterator that can iterate over all archived batches.
+func (self *SawtoothClientTransportArchive) GetBatchIterator(fetch int, reverse bool) types.BatchIterator {
+	return self.GetBatchIteratorFromCursor(types.NewCursor(types.CURSOR_BATCHES, fetch, reverse))
+}
+
+// GetBatchIteratorFromCursor returns a types.BatchIterator that resumes the iteration recorded by cursor.
+func (self *SawtoothClientTransportArchive) GetBatchIteratorFromCursor(cursor *types.Cursor) types.BatchIterator {
+	head, err := self.resolveListHead(cursor, types.CURSOR_BATCHES)
+	if err != nil {
+		return newIteratorWithError[*types.Batch](err)
+	}
+
+	var locations []archiveLocation
+	var keys []string
+	for entry := head; entry >= 0; entry-- {
+		for b, batchId := range self.batchIds[entry] {
+			locations = append(locations, archiveLocation{entry: entry, batch: b})
+			keys = append(keys, batchId)
+		}
+	}
+
+	blocks := self.newBlockCache()
+	return newArchiveIterator(cursor, self.entryId(head), keys, func(i int) (*types.Batch, error) {
+		block, err := blocks.get(locations[i].entry)
+		if err != nil {
+			return nil, err
+		}
+		return &block.Batches[locations[i].batch], nil
+	})
+}
+
+// GetBatchStatus returns COMMITTED for an archived batch, and UNKNOWN otherwise. wait is ignored.
+func (self *SawtoothClientTransportArchive) GetBatchStatus(batchId string, wait int) (types.BatchStatus, error) {
+	statuses, err := self.GetBatchStatusMultiple([]string{batchId}, wait)
+	if err != nil {
+		return "", err
+	}
+
+	return statuses[batchId], nil
+}
+
+// GetBatchStatusMultiple returns the status of each batch, as GetBatchStatus does.
+func (self *SawtoothClientTransportArchive) GetBatchStatusMultiple(batchIds []string, wait int) (map[string]types.BatchStatus, error) {
+	statuses := make(map[string]types.BatchStatus, len(batchIds))
+	for _, batchId := range batchIds {
+		if !headerSignaturePattern.MatchString(batchId) {
+			return nil, newError(errors.INVALID_RESOURCE_ID, "Invalid batch id: %s", batchId)
+		}
+
+		_, ok := self.batches[batchId]
+		if ok {
+			statuses[batchId] = types.BATCH_STATUS_COMMITTED
+		} else {
+			statuses[batchId] = types.BATCH_STATUS_UNKNOWN
+		}
+	}
+
+	return statuses, nil
+}
+
+// SubmitBatchList always fails, as an archive is read-only.
+func (self *SawtoothClientTransportArchive) SubmitBatchList(batchList *batch_pb2.BatchList) error {
+	return newError(errors.TRANSPORT_READ_ONLY, "Cannot submit batches to a chain archive")
+}
+
+// GetBlock returns the archived block represented by blockId.
+func (self *SawtoothClientTransportArchive) GetBlock(blockId string) (*types.Block, error) {
+	if !headerSignaturePattern.MatchString(blockId) {
+		return nil, newError(errors.INVALID_RESOURCE_ID, "Invalid block id: %s", blockId)
+	}
+
+	block, ok, err := self.reader.GetBlock(blockId)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, newError(errors.BLOCK_NOT_FOUND, "Block not found: %s", blockId)
+	}
+
+	return block, nil
+}
+
+// GetBlockIterator returns a types.BlockIterator that can iterate over all archived blocks.
+func (self *SawtoothClientTransportArchive) GetBlockIterator(fetch int, reverse bool) types.BlockIterator {
+	return self.GetBlockIteratorFromCursor(types.NewCursor(types.CURSOR_BLOCKS, fetch, reverse))
+}
+
+// GetBlockIteratorFromCursor returns a types.BlockIterator that resumes the iteration recorded by cursor.
+func (self *SawtoothClientTransportArchive) GetBlockIteratorFromCursor(cursor *types.Cursor) types.BlockIterator {
+	clamped, err := types.ClampBlockCursor(cursor, func() types.BlockIterator {
+		return self.GetBlockIterator(1, false)
+	})
+	if err != nil {
+		return newIteratorWithError[*types.Block](err)
+	}
+	if clamped == nil {
+		// Nothing to list yet, but the cursor can still be resumed once blocks are archived
+		return &archiveIterator[*types.Block]{cursor: *cursor}
+	}
+
+	head, err := self.resolveListHead(clamped, types.CURSOR_BLOCKS)
+	if err != nil {
+		return newIteratorWithError[*types.Block](err)
+	}
+
+	entries := self.reader.Index().Blocks
+	keys := make([]string, 0, head + 1)
+	for entry := head; entry >= 0; entry-- {
+		keys = append(keys, types.BlockPagingStart(entries[entry].BlockNum))
+	}
+
+	iterator := newArchiveIterator(clamped, self.entryId(head), keys, func(i int) (*types.Block, error) {
+		return self.readBlock(head - i)
+	})
+	iterator.stop = clamped.PastStopBlock
+
+	return iterator
+}
+
+// GetBlockRangeIterator returns a types.BlockIterator over the blocks numbered first to last
+// (inclusive), newest first unless reverse is true.
+func (self *SawtoothClientTransportArchive) GetBlockRangeIterator(first uint64, last uint64, fetch int, reverse bool) types.BlockIterator {
+	cursor, err := types.NewBlockRangeCursor(first, last, fetch, reverse)
+	if err != nil {
+		return newIteratorWithError[*types.Block](err)
+	}
+
+	return self.GetBlockIteratorFromCursor(cursor)
+}
+
+// GetBlockIteratorSince returns a types.BlockIterator over the blocks following blockId, oldest
+// first, up to the last archived block.
+func (self *SawtoothClientTransportArchive) GetBlockIteratorSince(blockId string, fetch int) types.BlockIterator {
+	block, err := self.GetBlock(blockId)
+	if err != nil {
+		return newIteratorWithError[*types.Block](err)
+	}
+
+	return self.GetBlockIteratorFromCursor(types.NewBlockSinceCursor(block.Header.BlockNum, fetch))
+}
+
+// GetTransaction returns the archived transaction represented by transactionId.
+func (self *SawtoothClientTransportArchive) GetTransaction(transactionId string) (*types.Transaction, error) {
+	if !headerSignaturePattern.MatchString(transactionId) {
+		return nil, newError(errors.INVALID_RESOURCE_ID, "Invalid transaction id: %s", transactionId)
+	}
+
+	location, ok := self.transactions[transactionId]
+	if !ok {
+		return nil, newError(errors.TRANSACTION_NOT_FOUND, "Transaction not found: %s", transactionId)
+	}
+
+	block, err := self.readBlock(location.entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &block.Batches[location.batch].Transactions[location.transaction], nil
+}
+
+// GetTransactionIterator returns a types.TransactionIterator that can iterate over all archived transactions.
+func (self *SawtoothClientTransportArchive) GetTransactionIterator(fetch int, reverse bool) types.TransactionIterator {
+	return self.GetTransactionIteratorFromCursor(types.NewCursor(types.CURSOR_TRANSACTIONS, fetch, reverse))
+}
+
+// GetTransactionIteratorFromCursor returns a types.TransactionIterator that resumes the iteration recorded by cursor.
+func (self *SawtoothClientTransportArchive) GetTransactionIteratorFromCursor(cursor *types.Cursor) types.TransactionIterator {
+	head, err := self.resolveListHead(cursor, types.CURSOR_TRANSACTIONS)
+	if err != nil {
+		return newIteratorWithError[*types.Transaction](err)
+	}
+
+	var keys []string
+	for entry := head; entry >= 0; entry-- {
+		keys = append(keys, self.transactionIds[entry]...)
+	}
+
+	blocks := self.newBlockCache()
+	return newArchiveIterator(cursor, self.entryId(head), keys, func(i int) (*types.Transaction, error) {
+		location := self.transactions[keys[i]]
+		block, err := blocks.get(location.entry)
+		if err != nil {
+			return nil, err
+		}
+		return &block.Batches[location.batch].Transactions[location.transaction], nil
+	})
+}
+
+// GetTransactionReceipt always fails, as archives do not hold transaction receipts.
+func (self *SawtoothClientTransportArchive) GetTransactionReceipt(transactionId string) (*types.TransactionReceipt, error) {
+	receipts, err := self.GetTransactionReceipts([]string{transactionId})
+	if err != nil {
+		return nil, err
+	}
+
+	return receipts[transactionId], nil
+}
+
+// GetTransactionReceipts always fails, as archives do not hold transaction receipts.
+func (self *SawtoothClientTransportArchive) GetTransactionReceipts(transactionIds []string) (map[string]*types.TransactionReceipt, error) {
+	for _, transactionId := range transactionIds {
+		if !headerSignaturePattern.MatchString(transactionId) {
+			return nil, newError(errors.INVALID_RESOURCE_ID, "Invalid transaction id: %s", transactionId)
+		}
+	}
+
+	return nil, newError(errors.RECEIPT_UNAVAILABLE, "Chain archives do not hold transaction receipts")
+}
+
+// GetState returns the state at address, as of the state snapshot.
+func (self *SawtoothClientTransportArchive) GetState(address string) (*types.State, error) {
+	return self.GetStateAtHead(address, "")
+}
+
+// GetStateAtHead returns the state at address, as of the block head (which must be the block of
+// the state snapshot).
+func (self *SawtoothClientTransportArchive) GetStateAtHead(address string, head string) (*types.State, error) {
+	snapshot, err := self.resolveState(head, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return getState(snapshot, address)
+}
+
+// GetStateAtRoot returns the state at address, as of the state root hash stateRoot (which must
+// be the root of the state snapshot).
+func (self *SawtoothClientTransportArchive) GetStateAtRoot(address string, stateRoot string) (*types.State, error) {
+	snapshot, err := self.resolveState("", stateRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	return getState(snapshot, address)
+}
+
+// GetStates returns the state at each address, as of the state snapshot. Addresses which are not
+// set map to nil.
+func (self *SawtoothClientTransportArchive) GetStates(addresses []string) (map[string]*types.State, error) {
+	return self.GetStatesAtHead(addresses, "")
+}
+
+// GetStatesAtHead returns the state at each address, as of the block head (which must be the
+// block of the state snapshot). Addresses which are not set map to nil.
+func (self *SawtoothClientTransportArchive) GetStatesAtHead(addresses []string, head string) (map[string]*types.State, error) {
+	snapshot, err := self.resolveState(head, "")
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]*types.State, len(addresses))
+	for _, address := range addresses {
+		state, err := getState(snapshot, address)
+		if errors.HasErrorCode(err, errors.STATE_NOT_FOUND) {
+			states[address] = nil
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		states[address] = state
+	}
+
+	return states, nil
+}
+
+// GetStateIterator returns a types.StateIterator over the state entries whose address starts with
+// addressPrefix, as of the state snapshot.
+func (self *SawtoothClientTransportArchive) GetStateIterator(addressPrefix string, fetch int, reverse bool) types.StateIterator {
+	return self.GetStateIteratorAtHead(addressPrefix, "", fetch, reverse)
+}
+
+// GetStateIteratorAtHead returns a types.StateIterator over the state entries whose address
+// starts with addressPrefix, as of the block head (which must be the block of the state snapshot).
+func (self *SawtoothClientTransportArchive) GetStateIteratorAtHead(addressPrefix string, head string, fetch int, reverse bool) types.StateIterator {
+	cursor := types.NewCursor(types.CURSOR_STATE, fetch, reverse)
+	cursor.Address = addressPrefix
+	cursor.Head = head
+
+	return self.GetStateIteratorFromCursor(cursor)
+}
+
+// GetStateIteratorAtRoot returns a types.StateIterator over the state entries whose address
+// starts with addressPrefix, as of the state root hash stateRoot (which must be the root of the
+// state snapshot).
+func (self *SawtoothClientTransportArchive) GetStateIteratorAtRoot(addressPrefix string, stateRoot string, fetch int, reverse bool) types.StateIterator {
+	cursor := types.NewCursor(types.CURSOR_STATE, fetch, reverse)
+	cursor.Address = addressPrefix
+	cursor.StateRoot = stateRoot
+
+	return self.GetStateIteratorFromCursor(cursor)
+}
+
+// GetStateIteratorFromCursor returns a types.StateIterator that resumes the iteration recorded by cursor.
+func (self *SawtoothClientTransportArchive) GetStateIteratorFromCursor(cursor *types.Cursor) types.StateIterator {
+	err := cursor.Check(types.CURSOR_STATE)
+	if err != nil {
+		return newIteratorWithError[*types.State](err)
+	}
+	if !addressPrefixPattern.MatchString(cursor.Address) {
+		return newIteratorWithError[*types.State](newError(errors.INVALID_STATE_ADDRESS, "Invalid state address prefix: %s", cursor.Address))
+	}
+
+	snapshot, err := self.resolveState(cursor.Head, cursor.StateRoot)
+	if err != nil {
+		return newIteratorWithError[*types.State](err)
+	}
+
+	states := snapshot.List(cursor.Address)
+	keys := make([]string, len(states))
+	for i, state := range states {
+		keys[i] = state.Address
+	}
+
+	// The head is only recorded for reads by head, so a cursor resumes a state root read as such
+	head := ""
+	if cursor.StateRoot == "" {
+		head = snapshot.Head
+	}
+
+	return newArchiveIterator(cursor, head, keys, func(i int) (*types.State, error) {
+		return states[i], nil
+	})
+}
+
+// readBlock reads the block at an index entry.
+func (self *SawtoothClientTransportArchive) readBlock(entry int) (*types.Block, error) {
+	return self.reader.ReadBlock(&self.reader.Index().Blocks[entry])
+}
+
+// entryId returns the id of the block at an index entry.
+func (self *SawtoothClientTransportArchive) entryId(entry int) string {
+	return self.reader.Index().Blocks[entry].BlockId
+}
+
+// resolveListHead checks a cursor for a listing, and returns the index entry of the head it reads
+// from: the cursor's head, or the last archived block.
+func (self *SawtoothClientTransportArchive) resolveListHead(cursor *types.Cursor, kind types.CursorKind) (int, error) {
+	err := cursor.Check(kind)
+	if err != nil {
+		return 0, err
+	}
+
+	if cursor.Head == "" {
+		return len(self.reader.Index().Blocks) - 1, nil
+	}
+
+	entry, ok := self.reader.blockIds[cursor.Head]
+	if !ok {
+		return 0, newError(errors.INVALID_HEAD, "Head not found: %s", cursor.Head)
+	}
+
+	return entry, nil
+}
+
+// resolveState returns the state snapshot, checking that it is the state at head or stateRoot
+// (if given).
+func (self *SawtoothClientTransportArchive) resolveState(head string, stateRoot string) (*StateSnapshot, error) {
+	if head != "" {
+		_, ok := self.reader.blockIds[head]
+		if !ok {
+			return nil, newError(errors.INVALID_HEAD, "Head not found: %s", head)
+		}
+	}
+	if stateRoot != "" && !self.stateRoots[stateRoot] && (self.snapshot == nil || stateRoot != self.snapshot.StateRoot) {
+		return nil, newError(errors.INVALID_HEAD, "State root not found: %s", stateRoot)
+	}
+
+	if self.snapshot == nil {
+		return nil, newError(errors.STATE_UNAVAILABLE, "Archive has no state snapshot")
+	}
+	if head != "" && head != self.snapshot.Head {
+		return nil, newError(errors.STATE_UNAVAILABLE, "State is only available at block %d (%s)", self.snapshot.BlockNum, self.snapshot.Head)
+	}
+	if stateRoot != "" && stateRoot != self.snapshot.StateRoot {
+		return nil, newError(errors.STATE_UNAVAILABLE, "State is only available at root %s", self.snapshot.StateRoot)
+	}
+
+	return self.snapshot, nil
+}
+
+// getState returns the state at address in a snapshot.
+func getState(snapshot *StateSnapshot, address string) (*types.State, error) {
+	if !addressPattern.MatchString(address) {
+		return nil, newError(errors.INVALID_STATE_ADDRESS, "Invalid state address: %s", address)
+	}
+
+	state, ok := snapshot.Get(address)
+	if !ok {
+		return nil, newError(errors.STATE_NOT_FOUND, "State not found: %s", address)
+	}
+
+	return state, nil
+}
+
+// newError returns a SawtoothClientTransportError with the given code and message.
+func newError(errorCode errors.SawtoothTransportErrorCode, format string, args ...interface{}) error {
+	return &errors.SawtoothClientTransportError{ErrorCode: errorCode, ErrorObject: fmt.Errorf(format, args...)}
+}
+
+// blockCache keeps the last block read by an iterator, whose batches or transactions are usually
+// read one after another.
+type blockCache struct {
+	transport	*SawtoothClientTransportArchive
+	entry		int
+	block		*types.Block
+}
+
+// newBlockCache returns an empty blockCache.
+func (self *SawtoothClientTransportArchive) newBlockCache() *blockCache {
+	return &blockCache{transport: self, entry: -1}
+}
+
+// get returns the block at an index entry, reading it unless it was the last block read.
+func (self *blockCache) get(entry int) (*types.Block, error) {
+	if entry != self.entry {
+		block, err := self.transport.readBlock(entry)
+		if err != nil {
+			return nil, err
+		}
+		self.entry, self.block = entry, block
+	}
+
+	return self.block, nil
+}
+
+// archiveIterator implements the transport iterators over a listing of archived values. Each
+// value has a key (the paging token used by the validator), so cursors are interchangeable with
+// those of the other transports.
+type archiveIterator[T any] struct {
+	cursor		types.Cursor
+	keys		[]string
+	load		func(i int) (T, error)
+	// stop, if set, ends the iteration before a value (see types.Cursor.PastStopBlock).
+	stop		func(value T) (bool, error)
+
+	next		int
+	current		T
+	hasCurrent	bool
+	closed		bool
+	err			error
+}
+
+// newArchiveIterator returns an iterator over values 0 to len(keys) - 1, in reverse if the cursor
+// is reversed, starting from the cursor's position.
+func newArchiveIterator[T any](cursor *types.Cursor, head string, keys []string, load func(i int) (T, error)) *archiveIterator[T] {
+	self := &archiveIterator[T]{cursor: *cursor, keys: keys, load: load}
+	if !cursor.Since {
+		self.cursor.Head = head
+	}
+
+	if cursor.Reverse {
+		count := len(keys)
+		self.keys = make([]string, count)
+		for i, key := range keys {
+			self.keys[count - 1 - i] = key
+		}
+		self.load = func(i int) (T, error) {
+			return load(count - 1 - i)
+		}
+	}
+
+	if cursor.Start != "" {
+		self.next = -1
+		for i, key := range self.keys {
+			if strings.EqualFold(key, cursor.Start) {
+				self.next = i
+				break
+			}
+		}
+		if self.next < 0 {
+			self.err = newError(errors.INVALID_PAGING_QUERY, "Invalid paging start: %s", cursor.Start)
+			return self
+		}
+	}
+	self.next += cursor.Skip
+	if self.next > len(self.keys) {
+		self.next = len(self.keys)
+	}
+
+	return self
+}
+
+// newIteratorWithError returns an iterator that yields no values and reports err (if any).
+func newIteratorWithError[T any](err error) *archiveIterator[T] {
+	return &archiveIterator[T]{err: err}
+}
+
+// Next returns true if a next value is available.
+func (self *archiveIterator[T]) Next() bool {
+	var zero T
+	self.current, self.hasCurrent = zero, false
+	if self.err != nil || self.closed || self.next >= len(self.keys) {
+		return false
+	}
+
+	value, err := self.load(self.next)
+	if err != nil {
+		self.err = err
+		return false
+	}
+
+	if self.stop != nil {
+		past, err := self.stop(value)
+		if err != nil {
+			self.err = err
+			return false
+		}
+		if past {
+			self.Close()
+			return false
+		}
+	}
+
+	self.current, self.hasCurrent = value, true
+	self.next++
+
+	return true
+}
+
+// Current returns the "current" value from the iterator.
+func (self *archiveIterator[T]) Current() (T, error) {
+	if !self.hasCurrent {
+		var zero T
+		return zero, fmt.Errorf("No current value in iterator...")
+	}
+
+	return self.current, nil
+}
+
+// Error returns the error that ended the iteration, if any.
+func (self *archiveIterator[T]) Error() error {
+	return self.err
+}
+
+// SetPrefetch has no effect, as archived values are read on demand.
+func (self *archiveIterator[T]) SetPrefetch(depth int) {
+}
+
+// Close ends the iteration.
+func (self *archiveIterator[T]) Close() {
+	self.closed = true
+}
+
+// Cursor returns a cursor recording the iterator's position.
+func (self *archiveIterator[T]) Cursor() (*types.Cursor, error) {
+	if self.err != nil {
+		return nil, self.err
+	}
+
+	cursor := self.cursor
+	if self.next < len(self.keys) {
+		cursor.Start, cursor.Skip = self.keys[self.next], 0
+	} else if len(self.keys) > 0 {
+		cursor.Start, cursor.Skip = self.keys[len(self.keys) - 1], 1
+	}
+
+	return &cursor, nil
+}
+
+var _ transport.SawtoothClientTransport = (*SawtoothClientTransportArchive)(nil)
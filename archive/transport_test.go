@@ -0,0 +1,168 @@
+package archive_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	"github.com/taekion-org/sawtooth-client-sdk-go/archive"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/conformance"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/errors"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// checkErrorCode fails the test unless err is a transport error with the given code.
+func checkErrorCode(t *testing.T, description string, err error, code errors.SawtoothTransportErrorCode) {
+	t.Helper()
+
+	if !errors.HasErrorCode(err, code) {
+		t.Fatalf("%s: expected error code %d, got: %v", description, code, err)
+	}
+}
+
+// checkState fails the test if state does not hold the expected address, data and head.
+func checkState(t *testing.T, description string, state *types.State, err error, address string, data []byte, head string) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("%s(%s): %s", description, address, err)
+	}
+	if state.Address != address || !bytes.Equal(state.Data, data) || state.Head != head {
+		t.Fatalf("%s: got %s=%q at %s, expected %s=%q at %s", description, state.Address, state.Data, state.Head, address, data, head)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	fixture := conformance.NewFixture()
+	clientTransport := conformance.NewRestTransport(t, conformance.NewValidator(fixture))
+
+	head := len(fixture.Blocks) - 1
+	headId := fixture.Head().HeaderSignature
+
+	var buffer, stateBuffer bytes.Buffer
+	_, err := archive.Export(clientTransport, &buffer, &archive.ExportOptions{Compression: archive.COMPRESSION_GZIP})
+	if err != nil {
+		t.Fatalf("Export: %s", err)
+	}
+	header, err := archive.ExportState(clientTransport, &stateBuffer, &archive.ExportStateOptions{Fetch: 3})
+	if err != nil {
+		t.Fatalf("ExportState: %s", err)
+	}
+	if header.Head != headId || header.StateRoot != fixture.StateRoots[head] {
+		t.Fatalf("ExportState: unexpected header %+v", header)
+	}
+
+	reader, err := archive.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatalf("NewReader: %s", err)
+	}
+	snapshot, err := archive.ReadStateSnapshot(bytes.NewReader(stateBuffer.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadStateSnapshot: %s", err)
+	}
+	archiveTransport, err := archive.NewSawtoothClientTransportArchive(reader, snapshot)
+	if err != nil {
+		t.Fatalf("NewSawtoothClientTransportArchive: %s", err)
+	}
+
+	// Blocks, batches and transactions are served as the validator serves them
+	conformance.RunChain(t, fixture, archiveTransport)
+
+	// Cursors can be resumed on another transport
+	iterator := archiveTransport.GetBlockIterator(0, true)
+	for read := 0; read < 5; read++ {
+		if !iterator.Next() {
+			t.Fatalf("GetBlockIterator: ended after %d blocks (%v)", read, iterator.Error())
+		}
+	}
+	cursor, err := iterator.Cursor()
+	if err != nil {
+		t.Fatalf("Cursor: %s", err)
+	}
+	iterator.Close()
+	resumed, err := types.Collect(types.NewIterator(clientTransport.GetBlockIteratorFromCursor(cursor)))
+	if err != nil {
+		t.Fatalf("GetBlockIteratorFromCursor: %s", err)
+	}
+	if len(resumed) != len(fixture.Blocks) - 5 || resumed[0].HeaderSignature != fixture.Blocks[5].HeaderSignature {
+		t.Fatalf("GetBlockIteratorFromCursor: got %d blocks, expected %d from block 5", len(resumed), len(fixture.Blocks) - 5)
+	}
+
+	// State is served at the snapshot head only
+	for _, address := range fixture.AddressesAt(head, "") {
+		state, err := archiveTransport.GetState(address)
+		checkState(t, "GetState", state, err, address, fixture.States[head][address], headId)
+
+		state, err = archiveTransport.GetStateAtRoot(address, fixture.StateRoots[head])
+		checkState(t, "GetStateAtRoot", state, err, address, fixture.States[head][address], headId)
+	}
+	states, err := types.Collect(types.NewIterator(archiveTransport.GetStateIterator(conformance.FIXTURE_NAMESPACE, 0, true)))
+	if err != nil || len(states) != len(fixture.AddressesAt(head, conformance.FIXTURE_NAMESPACE)) {
+		t.Fatalf("GetStateIterator: got %d values (%v)", len(states), err)
+	}
+
+	_, err = archiveTransport.GetState(conformance.FixtureAddress(conformance.FIXTURE_KEYS))
+	checkErrorCode(t, "GetState(absent)", err, errors.STATE_NOT_FOUND)
+	_, err = archiveTransport.GetStateAtHead(conformance.FixtureAddress(0), fixture.Blocks[4].HeaderSignature)
+	checkErrorCode(t, "GetStateAtHead(past)", err, errors.STATE_UNAVAILABLE)
+	_, err = archiveTransport.GetStateAtRoot(conformance.FixtureAddress(0), fixture.StateRoots[4])
+	checkErrorCode(t, "GetStateAtRoot(past)", err, errors.STATE_UNAVAILABLE)
+	_, err = archiveTransport.GetStateAtHead(conformance.FixtureAddress(0), conformance.UnknownId("head"))
+	checkErrorCode(t, "GetStateAtHead(unknown head)", err, errors.INVALID_HEAD)
+
+	// Archived batches are committed, and nothing can be submitted
+	committed := fixture.Head().Batches[0].HeaderSignature
+	statuses, err := archiveTransport.GetBatchStatusMultiple([]string{committed, fixture.PendingBatchId}, 0)
+	if err != nil {
+		t.Fatalf("GetBatchStatusMultiple: %s", err)
+	}
+	if statuses[committed] != types.BATCH_STATUS_COMMITTED || statuses[fixture.PendingBatchId] != types.BATCH_STATUS_UNKNOWN {
+		t.Fatalf("GetBatchStatusMultiple: unexpected statuses %v", statuses)
+	}
+
+	batch := fixture.NewBatch([]*transaction_pb2.Transaction{fixture.NewTransaction(2, []byte("submitted"), "submitted")})
+	err = archiveTransport.SubmitBatchList(&batch_pb2.BatchList{Batches: []*batch_pb2.Batch{batch}})
+	checkErrorCode(t, "SubmitBatchList", err, errors.TRANSPORT_READ_ONLY)
+	_, err = archiveTransport.GetTransactionReceipt(fixture.Head().Batches[0].Transactions[0].HeaderSignature)
+	checkErrorCode(t, "GetTransactionReceipt", err, errors.RECEIPT_UNAVAILABLE)
+
+	// Without a snapshot, no state is available
+	blocksOnly, err := archive.NewSawtoothClientTransportArchive(reader, nil)
+	if err != nil {
+		t.Fatalf("NewSawtoothClientTransportArchive(no snapshot): %s", err)
+	}
+	_, err = blocksOnly.GetState(conformance.FixtureAddress(0))
+	checkErrorCode(t, "GetState(no snapshot)", err, errors.STATE_UNAVAILABLE)
+
+	// The transport can be created from archive files by type
+	directory := t.TempDir()
+	path, statePath := filepath.Join(directory, "chain.arc"), filepath.Join(directory, "state.snap")
+	err = ioutil.WriteFile(path, buffer.Bytes(), 0644)
+	if err == nil {
+		err = ioutil.WriteFile(statePath, stateBuffer.Bytes(), 0644)
+	}
+	if err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	archiveUrl := &url.URL{Scheme: "file", Path: path, RawQuery: url.Values{"snapshot": {statePath}}.Encode()}
+	fileTransport, err := transport.NewSawtoothClientTransport(transport.TRANSPORT_ARCHIVE, archiveUrl)
+	if err != nil {
+		t.Fatalf("NewSawtoothClientTransport(archive): %s", err)
+	}
+	defer fileTransport.(*archive.SawtoothClientTransportArchive).Close()
+
+	block, err := fileTransport.GetBlock(fixture.Blocks[3].HeaderSignature)
+	if err != nil || block.Header.BlockNum != 3 {
+		t.Fatalf("GetBlock(file): got %v (%v)", block, err)
+	}
+	_, err = fileTransport.GetState(conformance.FixtureAddress(0))
+	if err != nil {
+		t.Fatalf("GetState(file): %s", err)
+	}
+}
@@ -0,0 +1,104 @@
+package sawtooth_client_sdk_go
+
+import (
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	"github.com/taekion-org/sawtooth-client-sdk-go/signer"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+	"strings"
+	"time"
+)
+
+// BatcherPublicKey returns the hex encoded public key which signs the batches created by the client.
+func (self *SawtoothClient) BatcherPublicKey() string {
+	return self.batchSigner().PublicKey()
+}
+
+// batchSigner returns the signer for the batches created by the client.
+func (self *SawtoothClient) batchSigner() signer.Signer {
+	if self.BatchSigner != nil {
+		return self.BatchSigner
+	}
+
+	return self.Signer
+}
+
+// DecodeTransaction decodes a serialized transaction signed by another party (e.g. an end user),
+// and checks it with CheckTransaction.
+func (self *SawtoothClient) DecodeTransaction(data []byte) (*transaction_pb2.Transaction, error) {
+	transaction := &transaction_pb2.Transaction{}
+	err := proto.Unmarshal(data, transaction)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding transaction: %s", err)
+	}
+
+	err = self.CheckTransaction(transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// CheckTransaction checks that a transaction is well formed, that its signature and payload hash
+// are valid, and that it is to be batched by this client.
+func (self *SawtoothClient) CheckTransaction(transaction *transaction_pb2.Transaction) error {
+	decoded, err := types.TransactionFromProto(transaction)
+	if err != nil {
+		return fmt.Errorf("Error decoding transaction: %s", err)
+	}
+
+	err = decoded.Verify()
+	if err != nil {
+		return err
+	}
+
+	batcherPublicKey := self.BatcherPublicKey()
+	if !strings.EqualFold(decoded.Header.BatcherPublicKey, batcherPublicKey) {
+		return fmt.Errorf("Transaction %s must be batched by %s, not %s", transaction.HeaderSignature, decoded.Header.BatcherPublicKey, batcherPublicKey)
+	}
+
+	return nil
+}
+
+// SubmitTransactions checks a list of transactions (which may be signed by other parties), wraps
+// them in a single batch signed by this client's batcher, submits it and returns the submission result.
+func (self *SawtoothClient) SubmitTransactions(transactions []*transaction_pb2.Transaction) (*SubmissionResult, error) {
+	for i, transaction := range transactions {
+		err := self.CheckTransaction(transaction)
+		if err != nil {
+			return nil, fmt.Errorf("Error while checking transaction %d: %s", i, err)
+		}
+	}
+
+	return self.submitTransactions(transactions)
+}
+
+// submitTransactions wraps a list of transactions in a single batch and submits it.
+func (self *SawtoothClient) submitTransactions(transactions []*transaction_pb2.Transaction) (*SubmissionResult, error) {
+	batch, err := self.CreateBatch(transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := newSubmissionResult(batch, transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	batches := []*batch_pb2.Batch{batch}
+	batchList, err := self.CreateBatchList(batches)
+	if err != nil {
+		return nil, err
+	}
+
+	err = self.Transport.SubmitBatchList(batchList)
+	if err != nil {
+		return nil, err
+	}
+	result.SubmittedAt = time.Now()
+
+	return result, nil
+}
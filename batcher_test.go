@@ -0,0 +1,156 @@
+package sawtooth_client_sdk_go_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/signing"
+	sawtooth_client_sdk_go "github.com/taekion-org/sawtooth-client-sdk-go"
+	"github.com/taekion-org/sawtooth-client-sdk-go/signer"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/conformance"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+func TestBatcher(t *testing.T) {
+	fixture := conformance.NewFixture()
+	clientTransport := conformance.NewRestTransport(t, conformance.NewValidator(fixture))
+
+	seed := sha256.Sum256([]byte("user"))
+	userSigner := signer.NewMemorySigner(signing.NewSecp256k1PrivateKey(seed[:]))
+	userPublicKey := userSigner.PublicKey()
+	batcherSigner := signer.NewMemorySignerFromSigner(fixture.Signer)
+
+	user := &sawtooth_client_sdk_go.SawtoothClient{Signer: userSigner, Transport: clientTransport, ClientImpl: &fixtureImpl{}}
+	batcher := &sawtooth_client_sdk_go.SawtoothClient{Signer: batcherSigner, Transport: clientTransport, ClientImpl: &fixtureImpl{}}
+
+	// checkBatch checks that a batch is signed by the batcher and has been submitted
+	checkBatch := func(description string, batch *batch_pb2.Batch) {
+		t.Helper()
+
+		decoded, err := types.BatchFromProto(batch)
+		if err == nil {
+			err = decoded.Verify()
+		}
+		if err != nil {
+			t.Fatalf("%s: %s", description, err)
+		}
+		if decoded.Header.SignerPublicKey != fixture.SignerPublicKey {
+			t.Fatalf("%s: batch signed by %s, expected %s", description, decoded.Header.SignerPublicKey, fixture.SignerPublicKey)
+		}
+		for _, transaction := range decoded.Transactions {
+			if transaction.Header.SignerPublicKey != userPublicKey || transaction.Header.BatcherPublicKey != fixture.SignerPublicKey {
+				t.Fatalf("%s: unexpected transaction header %+v", description, transaction.Header)
+			}
+		}
+	}
+	checkSubmitted := func(description string, batchId string) {
+		t.Helper()
+
+		status, err := clientTransport.GetBatchStatus(batchId, 0)
+		if err != nil {
+			t.Fatalf("%s: %s", description, err)
+		}
+		if status != types.BATCH_STATUS_PENDING {
+			t.Fatalf("%s: got %s, expected %s", description, status, types.BATCH_STATUS_PENDING)
+		}
+	}
+
+	// A user signs a transaction for the batcher, which checks, batches and submits it
+	transaction, err := user.CreateTransactionForBatcher(&fixturePayload{Value: "user"}, strings.ToUpper(batcher.BatcherPublicKey()))
+	if err != nil {
+		t.Fatalf("CreateTransactionForBatcher: %s", err)
+	}
+	data, err := proto.Marshal(transaction)
+	if err != nil {
+		t.Fatalf("Marshal: %s", err)
+	}
+
+	received, err := batcher.DecodeTransaction(data)
+	if err != nil {
+		t.Fatalf("DecodeTransaction: %s", err)
+	}
+	batch, err := batcher.CreateBatch([]*transaction_pb2.Transaction{received})
+	if err != nil {
+		t.Fatalf("CreateBatch: %s", err)
+	}
+	checkBatch("CreateBatch", batch)
+
+	result, err := batcher.SubmitTransactions([]*transaction_pb2.Transaction{received})
+	if err != nil {
+		t.Fatalf("SubmitTransactions: %s", err)
+	}
+	checkSubmitted("SubmitTransactions", result.BatchId)
+	if result.BatchSignerPublicKey != fixture.SignerPublicKey || result.Transactions[0].TransactionId != received.HeaderSignature || result.Transactions[0].SignerPublicKey != userPublicKey {
+		t.Fatalf("SubmitTransactions: unexpected result %+v", result)
+	}
+
+	// Only the declared batcher can batch the transaction
+	_, err = user.CreateBatch([]*transaction_pb2.Transaction{transaction})
+	if err == nil {
+		t.Fatalf("CreateBatch(other batcher): expected an error")
+	}
+	batch, err = user.CreateBatchWithSigner([]*transaction_pb2.Transaction{transaction}, batcherSigner)
+	if err != nil {
+		t.Fatalf("CreateBatchWithSigner: %s", err)
+	}
+	checkBatch("CreateBatchWithSigner", batch)
+
+	own, err := user.CreateTransaction(&fixturePayload{Value: "own"})
+	if err != nil {
+		t.Fatalf("CreateTransaction: %s", err)
+	}
+	err = batcher.CheckTransaction(own)
+	if err == nil {
+		t.Fatalf("CheckTransaction(other batcher): expected an error")
+	}
+	_, err = batcher.SubmitTransactions([]*transaction_pb2.Transaction{received, own})
+	if err == nil {
+		t.Fatalf("SubmitTransactions(other batcher): expected an error")
+	}
+
+	// Tampered or malformed transactions are rejected
+	tampered := proto.Clone(received).(*transaction_pb2.Transaction)
+	tampered.Payload = []byte("tampered")
+	err = batcher.CheckTransaction(tampered)
+	if err == nil {
+		t.Fatalf("CheckTransaction(tampered): expected an error")
+	}
+	_, err = batcher.DecodeTransaction([]byte{0xff})
+	if err == nil {
+		t.Fatalf("DecodeTransaction(invalid): expected an error")
+	}
+
+	// A client with a separate batch signer creates transactions for it
+	user.BatchSigner = batcherSigner
+	if user.BatcherPublicKey() != fixture.SignerPublicKey {
+		t.Fatalf("BatcherPublicKey: got %s, expected %s", user.BatcherPublicKey(), fixture.SignerPublicKey)
+	}
+	result, err = user.ExecutePayload(&fixturePayload{Value: "executed"})
+	if err != nil {
+		t.Fatalf("ExecutePayload: %s", err)
+	}
+	checkSubmitted("ExecutePayload", result.BatchId)
+
+	own, err = user.CreateTransaction(&fixturePayload{Value: "own"})
+	if err != nil {
+		t.Fatalf("CreateTransaction(batch signer): %s", err)
+	}
+	batch, err = user.CreateBatch([]*transaction_pb2.Transaction{own})
+	if err != nil {
+		t.Fatalf("CreateBatch(batch signer): %s", err)
+	}
+	checkBatch("CreateBatch(batch signer)", batch)
+
+	// Signing fails once the context is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = user.CreateTransactionForBatcherContext(ctx, &fixturePayload{Value: "cancelled"}, fixture.SignerPublicKey)
+	if err == nil {
+		t.Fatalf("CreateTransactionForBatcherContext(cancelled): expected an error")
+	}
+}
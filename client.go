@@ -4,13 +4,18 @@ package sawtooth_client_sdk_go
 import (
 	"fmt"
 	"github.com/hyperledger/sawtooth-sdk-go/signing"
+	"github.com/taekion-org/sawtooth-client-sdk-go/keystore"
+	"github.com/taekion-org/sawtooth-client-sdk-go/signer"
 	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
 	"net/url"
 )
 
 // SawtoothClient represents the core functionality of a Sawtooth application client.
 type SawtoothClient struct {
-	Signer			*signing.Signer
+	// Signer signs the transactions created by the client.
+	Signer			signer.Signer
+	// BatchSigner signs the batches created by the client. If nil, Signer is used.
+	BatchSigner		signer.Signer
 	Transport		transport.SawtoothClientTransport
 	ClientImpl		SawtoothClientImpl
 }
@@ -21,39 +26,71 @@ type SawtoothClientArgs struct {
 	TransportType	transport.SawtoothClientTransportType
 	PrivateKey		signing.PrivateKey
 	KeyFile			string
+	// KeyPassphrase decrypts KeyFile and BatcherKeyFile (or the default key), if they are encrypted.
+	KeyPassphrase	[]byte
 	Impl			SawtoothClientImpl
+
+	// Signer, if set, is used instead of PrivateKey or KeyFile (e.g. a signer.DaemonSigner, so the
+	// private key is not held by the client).
+	Signer			signer.Signer
+	// BatchSigner, if set, is used instead of BatcherPrivateKey or BatcherKeyFile.
+	BatchSigner		signer.Signer
+
+	// BatcherPrivateKey or BatcherKeyFile set the key used to sign batches, if it differs from
+	// the transaction signer's key.
+	BatcherPrivateKey	signing.PrivateKey
+	BatcherKeyFile		string
+
+	// TransportOptions holds optional transport-specific settings (e.g. authentication).
+	TransportOptions	*transport.SawtoothClientTransportOptions
 }
 
 // NewClient constructs a new instance of the SawtoothClient.
 func NewClient(args *SawtoothClientArgs) (*SawtoothClient, error) {
 	var err error
-	var privateKey signing.PrivateKey
 
-	// Set up our private key for signing.
-	// If we are passed a private key directly, use it.
-	// Otherwise, load one from a file.
-	if args.PrivateKey != nil {
-		privateKey = args.PrivateKey
-	} else {
-		var keyFile string
-		if args.KeyFile == "" {
-			keyFile, err = getDefaultKeyFileName()
+	// Set up our signer.
+	// If we are passed a signer or a private key directly, use it.
+	// Otherwise, load a private key from a file, or the default key (see keystore.LoadDefaultKey).
+	transactionSigner := args.Signer
+	if transactionSigner == nil {
+		privateKey := args.PrivateKey
+		if privateKey == nil {
+			if args.KeyFile == "" {
+				privateKey, err = keystore.LoadDefaultKey(args.KeyPassphrase)
+			} else {
+				privateKey, err = keystore.ReadKeyFile(args.KeyFile, args.KeyPassphrase)
+			}
 			if err != nil {
 				return nil, err
 			}
-		} else {
-			keyFile = args.KeyFile
 		}
 
-		privateKey, err = readPrivateKeyFromFile(keyFile)
+		err = keystore.ValidatePrivateKey(privateKey)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("Invalid private key: %s", err)
 		}
+		transactionSigner = signer.NewMemorySigner(privateKey)
 	}
 
-	// Set up the crypto factory and signer
-	cryptoFactory := signing.NewCryptoFactory(signing.CreateContext("secp256k1"))
-	signer := cryptoFactory.NewSigner(privateKey)
+	// Set up the batch signer, if there is a separate batcher key
+	batchSigner := args.BatchSigner
+	if batchSigner == nil {
+		batcherPrivateKey := args.BatcherPrivateKey
+		if batcherPrivateKey == nil && args.BatcherKeyFile != "" {
+			batcherPrivateKey, err = keystore.ReadKeyFile(args.BatcherKeyFile, args.KeyPassphrase)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if batcherPrivateKey != nil {
+			err = keystore.ValidatePrivateKey(batcherPrivateKey)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid batcher private key: %s", err)
+			}
+			batchSigner = signer.NewMemorySigner(batcherPrivateKey)
+		}
+	}
 
 	// Parse the URL
 	url, err := url.Parse(args.URL)
@@ -62,12 +99,12 @@ func NewClient(args *SawtoothClientArgs) (*SawtoothClient, error) {
 	}
 
 	// Create the transport
-	transport, err := transport.NewSawtoothClientTransport(args.TransportType, url)
+	transport, err := transport.NewSawtoothClientTransportWithOptions(args.TransportType, url, args.TransportOptions)
 	if err != nil {
 		return nil, fmt.Errorf("Error initializing transport: %s", err)
 	}
 
-	client := &SawtoothClient{Signer: signer, ClientImpl: args.Impl, Transport: transport}
+	client := &SawtoothClient{Signer: transactionSigner, BatchSigner: batchSigner, ClientImpl: args.Impl, Transport: transport}
 
 	return client, nil
 }
@@ -0,0 +1,56 @@
+package sawtooth_client_sdk_go_test
+
+import (
+	"fmt"
+
+	sawtooth_client_sdk_go "github.com/taekion-org/sawtooth-client-sdk-go"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/conformance"
+)
+
+// fixturePayload is the payload of a transaction of the conformance fixture family, as encoded
+// and decoded by fixtureImpl.
+type fixturePayload struct {
+	Value			string
+	Nonce			string
+	Inputs			[]string
+	Outputs			[]string
+	Dependencies	[]string
+}
+
+func (self *fixturePayload) GetInputAddresses() []string { return self.Inputs }
+func (self *fixturePayload) GetOutputAddresses() []string { return self.Outputs }
+func (self *fixturePayload) GetDependencies() []string { return self.Dependencies }
+func (self *fixturePayload) GetNonce() string { return self.Nonce }
+
+// fixtureImpl encodes and decodes fixture payloads, failing to decode the payload invalid (if set).
+type fixtureImpl struct {
+	invalid	string
+}
+
+func (self *fixtureImpl) GetFamilyName() string { return conformance.FIXTURE_FAMILY_NAME }
+func (self *fixtureImpl) GetFamilyVersion() string { return "1.0" }
+
+func (self *fixtureImpl) EncodePayload(payload sawtooth_client_sdk_go.SawtoothPayload) ([]byte, error) {
+	return []byte(payload.(*fixturePayload).Value), nil
+}
+
+func (self *fixtureImpl) DecodePayload(data []byte, payload sawtooth_client_sdk_go.SawtoothPayload) error {
+	if string(data) == self.invalid {
+		return fmt.Errorf("Invalid payload: %s", data)
+	}
+	payload.(*fixturePayload).Value = string(data)
+	return nil
+}
+
+func (self *fixtureImpl) EncodeData(data interface{}) ([]byte, error) { return nil, fmt.Errorf("Not implemented") }
+func (self *fixtureImpl) DecodeData(data []byte, value interface{}) error { return fmt.Errorf("Not implemented") }
+
+// reversed returns a reversed copy of values.
+func reversed[T any](values []T) []T {
+	result := make([]T, len(values))
+	for i, value := range values {
+		result[len(values) - 1 - i] = value
+	}
+
+	return result
+}
@@ -0,0 +1,216 @@
+// archive_cli exports chain archives and state snapshots from a Sawtooth validator, and verifies or
+// imports chain archives.
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+
+	flag "github.com/spf13/pflag"
+	"github.com/taekion-org/sawtooth-client-sdk-go/archive"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+const DEFAULT_REST_URL = "http://localhost:8008"
+const DEFAULT_ZMQ_URL = "tcp://localhost:4004"
+const DEFAULT_TRANSPORT = "rest"
+const DEFAULT_PROGRESS_INTERVAL = 1000
+
+const CMD_EXPORT = "export"
+const CMD_EXPORT_STATE = "export-state"
+const CMD_VERIFY = "verify"
+const CMD_IMPORT = "import"
+const CMD_INFO = "info"
+
+// EXIT_INCONSISTENT is the exit status used when an archive fails verification.
+const EXIT_INCONSISTENT = 1
+
+var rest_url *string = flag.String("rest_url", DEFAULT_REST_URL, "Sawtooth REST API URL")
+var zmq_url *string = flag.String("zmq_url", DEFAULT_ZMQ_URL, "Sawtooth ZMQ URL")
+var transportName *string = flag.String("transport", DEFAULT_TRANSPORT, "Sawtooth Transport")
+var archive_url *string = flag.String("archive_url", "", "Chain archive URL (file:///path/to/archive?snapshot=/path/to/snapshot)")
+var first *uint64 = flag.Uint64("first", 0, "Number of the first block to export")
+var head *string = flag.String("head", "", "Id of the last block to export (default: current head)")
+var fetch *int = flag.Int("fetch", 0, "Number of blocks to fetch per request")
+var compress *bool = flag.Bool("compress", false, "Compress exported blocks")
+var wait *int = flag.Int("wait", 0, "Time to wait for the batches of each imported block to commit")
+var progress *uint = flag.Uint("progress", DEFAULT_PROGRESS_INTERVAL, "Report progress every n blocks (0 to disable)")
+
+func main() {
+	flag.Parse()
+
+	if flag.NArg() < 2 {
+		fmt.Printf("Usage: %s export|export-state|verify|import|info [file] {--transport [rest|zmq|archive]} {--first [block_num]} {--head [block_id]} {--compress} {--wait [wait_time]}\n", os.Args[0])
+		os.Exit(0)
+	}
+
+	switch flag.Arg(0) {
+	case CMD_EXPORT:
+		cmdExport(flag.Arg(1))
+	case CMD_EXPORT_STATE:
+		cmdExportState(flag.Arg(1))
+	case CMD_VERIFY:
+		cmdVerify(flag.Arg(1))
+	case CMD_IMPORT:
+		cmdImport(flag.Arg(1))
+	case CMD_INFO:
+		cmdInfo(flag.Arg(1))
+	default:
+		handleError(fmt.Errorf("Error: '%s' is an invalid command", flag.Arg(0)))
+	}
+}
+
+func cmdExport(path string) {
+	clientTransport, err := newTransport()
+	if err != nil {
+		handleError(err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		handleError(err)
+	}
+	defer file.Close()
+
+	options := &archive.ExportOptions{
+		FirstBlockNum: *first,
+		Head: *head,
+		Fetch: *fetch,
+		Progress: progressFunc("Exported"),
+	}
+	if *compress {
+		options.Compression = archive.COMPRESSION_GZIP
+	}
+
+	index, err := archive.Export(clientTransport, file, options)
+	if err != nil {
+		handleError(err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		handleError(err)
+	}
+
+	fmt.Printf("Exported %d blocks, %d batches, %d transactions to %s\n", len(index.Blocks), index.Batches, index.Transactions, path)
+}
+
+func cmdExportState(path string) {
+	clientTransport, err := newTransport()
+	if err != nil {
+		handleError(err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		handleError(err)
+	}
+	defer file.Close()
+
+	header, err := archive.ExportState(clientTransport, file, &archive.ExportStateOptions{Head: *head, Fetch: *fetch})
+	if err != nil {
+		handleError(err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		handleError(err)
+	}
+
+	fmt.Printf("Exported state at block %d (head %s, state root %s) to %s\n", header.BlockNum, header.Head, header.StateRoot, path)
+}
+
+func cmdVerify(path string) {
+	reader, err := archive.Open(path)
+	if err != nil {
+		handleError(err)
+	}
+	defer reader.Close()
+
+	result, err := archive.Verify(reader)
+	if verifyErr, ok := err.(*transport.ChainVerificationError); ok {
+		fmt.Println(verifyErr)
+		os.Exit(EXIT_INCONSISTENT)
+	} else if err != nil {
+		handleError(err)
+	}
+
+	fmt.Printf("Archive OK: blocks %d to %d (head %s)\n", result.FirstBlockNum, result.LastBlockNum, result.Head)
+	fmt.Printf("Verified %d blocks, %d batches, %d transactions\n", result.Blocks, result.Batches, result.Transactions)
+}
+
+func cmdImport(path string) {
+	clientTransport, err := newTransport()
+	if err != nil {
+		handleError(err)
+	}
+
+	reader, err := archive.Open(path)
+	if err != nil {
+		handleError(err)
+	}
+	defer reader.Close()
+
+	submitted, err := archive.Import(reader, clientTransport, &archive.ImportOptions{Wait: *wait, Progress: progressFunc("Imported")})
+	if err != nil {
+		handleError(err)
+	}
+
+	fmt.Printf("Submitted %d batches\n", submitted)
+}
+
+func cmdInfo(path string) {
+	reader, err := archive.Open(path)
+	if err != nil {
+		handleError(err)
+	}
+	defer reader.Close()
+
+	index := reader.Index()
+	fmt.Printf("Version: %d\n", index.Version)
+	fmt.Printf("Compression: %d\n", index.Compression)
+	if len(index.Blocks) > 0 {
+		firstEntry, lastEntry := index.Blocks[0], index.Blocks[len(index.Blocks) - 1]
+		fmt.Printf("Blocks: %d (%d to %d)\n", len(index.Blocks), firstEntry.BlockNum, lastEntry.BlockNum)
+		fmt.Printf("Head: %s\n", lastEntry.BlockId)
+	}
+	fmt.Printf("Batches: %d\n", index.Batches)
+	fmt.Printf("Transactions: %d\n", index.Transactions)
+}
+
+// progressFunc returns a function reporting progress every *progress blocks, or nil if disabled.
+func progressFunc(action string) func(block *types.Block) {
+	if *progress == 0 {
+		return nil
+	}
+
+	return func(block *types.Block) {
+		if block.Header.BlockNum % uint64(*progress) == 0 {
+			fmt.Fprintf(os.Stderr, "%s block %d\n", action, block.Header.BlockNum)
+		}
+	}
+}
+
+// newTransport connects to the validator (or opens the chain archive) with the selected transport.
+func newTransport() (transport.SawtoothClientTransport, error) {
+	transportType := transport.SawtoothClientTransportType(*transportName)
+	transportUrls := map[transport.SawtoothClientTransportType]string{
+		transport.TRANSPORT_REST: *rest_url,
+		transport.TRANSPORT_ZMQ: *zmq_url,
+		transport.TRANSPORT_ARCHIVE: *archive_url,
+	}
+
+	parsedUrl, err := url.Parse(transportUrls[transportType])
+	if err != nil {
+		return nil, err
+	}
+
+	return transport.NewSawtoothClientTransport(transportType, parsedUrl)
+}
+
+func handleError(err error) {
+	fmt.Println(err)
+	os.Exit(-1)
+}
@@ -0,0 +1,86 @@
+// chainverify_cli audits a Sawtooth chain, verifying block linkage and all signatures from the
+// genesis block (or a checkpoint) up to the chain head.
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+
+	flag "github.com/spf13/pflag"
+	_ "github.com/taekion-org/sawtooth-client-sdk-go/archive"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+const DEFAULT_REST_URL = "http://localhost:8008"
+const DEFAULT_ZMQ_URL = "tcp://localhost:4004"
+const DEFAULT_TRANSPORT = "rest"
+const DEFAULT_PROGRESS_INTERVAL = 1000
+
+// EXIT_INCONSISTENT is the exit status used when the chain fails verification.
+const EXIT_INCONSISTENT = 1
+
+func main() {
+	var rest_url *string = flag.String("rest_url", DEFAULT_REST_URL, "Sawtooth REST API URL")
+	var zmq_url *string = flag.String("zmq_url", DEFAULT_ZMQ_URL, "Sawtooth ZMQ URL")
+	var transportName *string = flag.String("transport", DEFAULT_TRANSPORT, "Sawtooth Transport")
+	var archive_url *string = flag.String("archive_url", "", "Chain archive URL (file:///path/to/archive?snapshot=/path/to/snapshot)")
+	var checkpoint *string = flag.String("checkpoint", "", "Trusted block id to start from (default: genesis)")
+	var head *string = flag.String("head", "", "Block id to verify up to (default: current head)")
+	var fetch *int = flag.Int("fetch", 0, "Number of blocks to fetch per request")
+	var progress *uint = flag.Uint("progress", DEFAULT_PROGRESS_INTERVAL, "Report progress every n blocks (0 to disable)")
+	flag.Parse()
+
+	clientTransport, err := newTransport(*transportName, *rest_url, *zmq_url, *archive_url)
+	if err != nil {
+		handleError(err)
+	}
+
+	options := &transport.VerifyChainOptions{
+		Checkpoint: *checkpoint,
+		Head: *head,
+		Fetch: *fetch,
+	}
+	if *progress > 0 {
+		options.Progress = func(block *types.Block) {
+			if block.Header.BlockNum % uint64(*progress) == 0 {
+				fmt.Fprintf(os.Stderr, "Verified block %d\n", block.Header.BlockNum)
+			}
+		}
+	}
+
+	result, err := transport.VerifyChain(clientTransport, options)
+	if verifyErr, ok := err.(*transport.ChainVerificationError); ok {
+		fmt.Println(verifyErr)
+		fmt.Printf("Verified blocks %d to %d before the failure\n", result.FirstBlockNum, result.LastBlockNum)
+		os.Exit(EXIT_INCONSISTENT)
+	} else if err != nil {
+		handleError(err)
+	}
+
+	fmt.Printf("Chain OK: blocks %d to %d (head %s)\n", result.FirstBlockNum, result.LastBlockNum, result.Head)
+	fmt.Printf("Verified %d blocks, %d batches, %d transactions\n", result.Blocks, result.Batches, result.Transactions)
+}
+
+// newTransport connects to the validator (or opens the chain archive) with the named transport.
+func newTransport(name string, rest_url string, zmq_url string, archive_url string) (transport.SawtoothClientTransport, error) {
+	transportType := transport.SawtoothClientTransportType(name)
+	transportUrls := map[transport.SawtoothClientTransportType]string{
+		transport.TRANSPORT_REST: rest_url,
+		transport.TRANSPORT_ZMQ: zmq_url,
+		transport.TRANSPORT_ARCHIVE: archive_url,
+	}
+
+	parsedUrl, err := url.Parse(transportUrls[transportType])
+	if err != nil {
+		return nil, err
+	}
+
+	return transport.NewSawtoothClientTransport(transportType, parsedUrl)
+}
+
+func handleError(err error) {
+	fmt.Println(err)
+	os.Exit(-1)
+}
@@ -10,8 +10,10 @@ import (
 	"time"
 
 	flag "github.com/spf13/pflag"
+	sawtooth_client_sdk_go "github.com/taekion-org/sawtooth-client-sdk-go"
 	"github.com/taekion-org/sawtooth-client-sdk-go/examples/intkey"
 	"github.com/taekion-org/sawtooth-client-sdk-go/transport/errors"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
 )
 
 const DEFAULT_REST_URL = "http://localhost:8008"
@@ -25,6 +27,7 @@ const CMD_SET = "set"
 const CMD_INC = "inc"
 const CMD_DEC = "dec"
 const CMD_STATUS = "status"
+const CMD_HISTORY = "history"
 
 var wait *uint = flag.Uint("wait", DEFAULT_WAIT_TIME, "Time to wait for commit")
 var intkeyClient *intkey.IntkeyClient
@@ -57,7 +60,7 @@ func main() {
 	}
 
 	if flag.NArg() == 0 {
-		fmt.Printf("Usage: %s list|show|set|inc|dec|status [params] {--url [URL]} {--wait [wait_time]} {--transport [rest|zmq]}\n", os.Args[0])
+		fmt.Printf("Usage: %s list|show|set|inc|dec|status|history [params] {--url [URL]} {--wait [wait_time]} {--transport [rest|zmq]}\n", os.Args[0])
 		os.Exit(0)
 	}
 
@@ -74,6 +77,8 @@ func main() {
 		cmdDec()
 	case CMD_STATUS:
 		cmdStatus()
+	case CMD_HISTORY:
+		cmdHistory()
 	default:
 		cmdInvalid()
 	}
@@ -121,8 +126,8 @@ func cmdSet() {
 		handleError(fmt.Errorf("Error: key %s already exists with value %d", key, existingValue))
 	}
 
-	batchId, err := intkeyClient.Set(key, uint(value), *wait)
-	printBatchInfo(batchId)
+	result, err := intkeyClient.Set(key, uint(value), *wait)
+	printBatchInfo(result, err)
 }
 
 func cmdInc() {
@@ -140,8 +145,8 @@ func cmdInc() {
 		handleError(fmt.Errorf("Error: key %s not found", key))
 	}
 
-	batchId, err := intkeyClient.Inc(key, uint(value), *wait)
-	printBatchInfo(batchId)
+	result, err := intkeyClient.Inc(key, uint(value), *wait)
+	printBatchInfo(result, err)
 
 }
 
@@ -160,8 +165,8 @@ func cmdDec() {
 		handleError(fmt.Errorf("Error: key %s not found", key))
 	}
 
-	batchId, err := intkeyClient.Dec(key, uint(value), *wait)
-	printBatchInfo(batchId)
+	result, err := intkeyClient.Dec(key, uint(value), *wait)
+	printBatchInfo(result, err)
 }
 
 func cmdStatus() {
@@ -177,16 +182,40 @@ func cmdStatus() {
 	fmt.Println(status)
 }
 
+func cmdHistory() {
+	key := flag.Arg(1)
+
+	for entry, err := range types.All(intkeyClient.History(key)) {
+		if err != nil {
+			handleError(err)
+		}
+
+		payload := entry.Payload.(*intkey.IntkeyPayload)
+		fmt.Printf("Block %d: %s %s %d [Transaction: %s]\n", entry.BlockNum, payload.Verb, payload.Name, payload.Value, entry.TransactionId)
+	}
+}
+
 func cmdInvalid() {
 	handleError(fmt.Errorf("Error: '%s' is an invalid command", flag.Arg(0)))
 }
 
-func printBatchInfo(batchId string) {
-	status, err := intkeyClient.Status(batchId)
+func printBatchInfo(result *sawtooth_client_sdk_go.SubmissionResult, err error) {
+	if result == nil {
+		handleError(err)
+	}
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	status, err := intkeyClient.Status(result.BatchId)
 	if err != nil {
 		handleError(err)
 	}
-	fmt.Printf("Batch: %s [Status: %s]\n", batchId, status)
+	fmt.Printf("Batch: %s [Status: %s]\n", result.BatchId, status)
+	fmt.Printf("Transaction: %s\n", result.Transactions[0].TransactionId)
+	if result.Committed() {
+		fmt.Printf("Block %d: %s\n", result.BlockNum, result.BlockId)
+	}
 }
 
 func handleError(err error) {
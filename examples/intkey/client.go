@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/taekion-org/sawtooth-client-sdk-go"
 	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
 	"math/rand"
 )
 
@@ -45,6 +46,9 @@ func (self *IntkeyPayload) GetNonce() string {
 	}
 }
 
+// AddDependency adds the id of a transaction that must be committed before this one. Dependencies
+// on transactions submitted together can be declared by name instead (see Apply and
+// sawtooth_client_sdk_go.Workflow).
 func (self *IntkeyPayload) AddDependency(transactionId string) {
 	self.deps = append(self.deps, transactionId)
 }
@@ -103,22 +107,16 @@ func NewIntkeyClientZmq(url string, keyFile string) (*IntkeyClient, error) {
 // List returns the current mapping of keys to values.
 func (self *IntkeyClient) List() (map[string]uint, error) {
 	addressPrefix := GetAddressPrefix()
-	iterator := self.Transport.GetStateIterator(addressPrefix, 10, false)
+	iterator := types.NewIterator(self.Transport.GetStateIterator(addressPrefix, 10, false))
 	result := make(map[string]uint)
 
-	for iterator.Next() {
-		err := iterator.Error()
-		if err != nil {
-			return nil, err
-		}
-
-		current, err := iterator.Current()
+	for state, err := range types.All(iterator) {
 		if err != nil {
 			return nil, err
 		}
 
 		m := make(map[string]uint, 1)
-		err = self.ClientImpl.DecodeData(current.Data, &m)
+		err = self.ClientImpl.DecodeData(state.Data, &m)
 		if err != nil {
 			return nil, err
 		}
@@ -152,41 +150,88 @@ func (self *IntkeyClient) Show(name string) (uint, error) {
 	}
 }
 
+// History returns an iterator over the committed intkey transactions, newest first. If name is not
+// empty, only the transactions for that key are returned.
+func (self *IntkeyClient) History(name string) types.Iterator[*sawtooth_client_sdk_go.TransactionHistoryEntry] {
+	newPayload := func() sawtooth_client_sdk_go.SawtoothPayload {
+		return &IntkeyPayload{}
+	}
+
+	iterator := self.GetTransactionHistory(newPayload, &sawtooth_client_sdk_go.TransactionHistoryOptions{SkipInvalid: true})
+	if name == "" {
+		return iterator
+	}
+
+	return types.Filter(iterator, func(entry *sawtooth_client_sdk_go.TransactionHistoryEntry) bool {
+		return entry.Payload.(*IntkeyPayload).Name == name
+	})
+}
+
 // sendTransaction is a common method used to construct a payload and submit it for processing.
-func (self *IntkeyClient) sendTransaction(verb string, name string, value uint, wait uint) (string, error) {
+// If wait is not zero, it waits up to wait seconds for the transaction to be committed.
+func (self *IntkeyClient) sendTransaction(verb string, name string, value uint, wait uint) (*sawtooth_client_sdk_go.SubmissionResult, error) {
 	payload := IntkeyPayload{
 		Verb: verb,
 		Name: name,
 		Value: value,
 	}
 
-	batchId, err := self.ExecutePayload(&payload)
+	result, err := self.ExecutePayload(&payload)
 	if err != nil {
-		return batchId, err
+		return nil, err
 	}
 
 	if wait > 0 {
-		self.WaitBatch(batchId, int(wait), 1)
+		err = self.WaitCommit(result, int(wait), 1)
+		if err != nil {
+			return result, err
+		}
 	}
 
-	return batchId, nil
+	return result, nil
 }
 
 // Set creates a new key -> value mapping.
-func (self *IntkeyClient) Set(name string, value uint, wait uint) (string, error) {
+func (self *IntkeyClient) Set(name string, value uint, wait uint) (*sawtooth_client_sdk_go.SubmissionResult, error) {
 	return self.sendTransaction(VERB_SET, name, value, wait)
 }
 
 // Inc increments a key's current value by the given parameter.
-func (self *IntkeyClient) Inc(name string, value uint, wait uint) (string, error) {
+func (self *IntkeyClient) Inc(name string, value uint, wait uint) (*sawtooth_client_sdk_go.SubmissionResult, error) {
 	return self.sendTransaction(VERB_INC, name, value, wait)
 }
 
 // Dec decrements a key's current value by the given parameter.
-func (self *IntkeyClient) Dec(name string, value uint, wait uint) (string, error) {
+func (self *IntkeyClient) Dec(name string, value uint, wait uint) (*sawtooth_client_sdk_go.SubmissionResult, error) {
 	return self.sendTransaction(VERB_DEC, name, value, wait)
 }
 
+// Apply submits a sequence of operations as a workflow in a single batch. Each operation depends
+// on the earlier operations on the same key, which are inferred from their addresses. If wait is
+// not zero, it waits up to wait seconds for the batch to be committed.
+func (self *IntkeyClient) Apply(payloads []*IntkeyPayload, wait uint) (*sawtooth_client_sdk_go.SubmissionResult, error) {
+	workflow := self.NewWorkflow(&sawtooth_client_sdk_go.WorkflowOptions{InferDependencies: true})
+	for i, payload := range payloads {
+		err := workflow.Add(fmt.Sprintf("%d", i), payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var results []*sawtooth_client_sdk_go.SubmissionResult
+	var err error
+	if wait > 0 {
+		results, err = workflow.SubmitSync(int(wait), 1)
+	} else {
+		results, err = workflow.Submit()
+	}
+	if results == nil {
+		return nil, err
+	}
+
+	return results[0], err
+}
+
 // Status returns the status of a given batch.
 func (self *IntkeyClient) Status(batchId string) (string, error) {
 	status, err := self.Transport.GetBatchStatus(batchId, 0)
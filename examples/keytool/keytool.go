@@ -0,0 +1,116 @@
+// keytool manages the Sawtooth private keys in a keystore directory (by default
+// ~/.sawtooth/keys): generating, listing, importing and exporting them.
+//
+// Keys are encrypted with the passphrase in KEYTOOL_PASSPHRASE, if it is set. Exported keys are
+// written in the plaintext format of sawtooth keygen, unless --encrypt is given.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	flag "github.com/spf13/pflag"
+	"github.com/taekion-org/sawtooth-client-sdk-go/keystore"
+)
+
+const CMD_GENERATE = "generate"
+const CMD_LIST = "list"
+const CMD_IMPORT = "import"
+const CMD_EXPORT = "export"
+const CMD_REMOVE = "remove"
+
+// PASSPHRASE_VARIABLE is the environment variable holding the keystore passphrase.
+const PASSPHRASE_VARIABLE = "KEYTOOL_PASSPHRASE"
+
+var dir *string = flag.String("dir", "", "Keystore directory (default: $SAWTOOTH_HOME/keys or ~/.sawtooth/keys)")
+var encrypt *bool = flag.Bool("encrypt", false, "Encrypt exported keys with the passphrase")
+
+func main() {
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		fmt.Printf("Usage: %s generate [name] | list | import [name] [file] | export [name] [file] | remove [name] {--dir [path]}\n", os.Args[0])
+		os.Exit(0)
+	}
+
+	store, err := openKeystore()
+	if err != nil {
+		handleError(err)
+	}
+	passphrase := []byte(os.Getenv(PASSPHRASE_VARIABLE))
+
+	switch flag.Arg(0) {
+	case CMD_GENERATE:
+		requireArgs(2)
+		info, err := store.Generate(flag.Arg(1), passphrase)
+		if err != nil {
+			handleError(err)
+		}
+		printKey(info)
+	case CMD_LIST:
+		keys, err := store.List()
+		if err != nil {
+			handleError(err)
+		}
+		for _, info := range keys {
+			printKey(info)
+		}
+	case CMD_IMPORT:
+		requireArgs(3)
+		privateKey, err := keystore.ReadKeyFile(flag.Arg(2), passphrase)
+		if err != nil {
+			handleError(err)
+		}
+		info, err := store.Import(flag.Arg(1), privateKey, passphrase)
+		if err != nil {
+			handleError(err)
+		}
+		printKey(info)
+	case CMD_EXPORT:
+		requireArgs(3)
+		var exportPassphrase []byte
+		if *encrypt {
+			exportPassphrase = passphrase
+		}
+		err := store.Export(flag.Arg(1), passphrase, flag.Arg(2), exportPassphrase)
+		if err != nil {
+			handleError(err)
+		}
+	case CMD_REMOVE:
+		requireArgs(2)
+		err := store.Remove(flag.Arg(1))
+		if err != nil {
+			handleError(err)
+		}
+	default:
+		handleError(fmt.Errorf("Error: '%s' is an invalid command", flag.Arg(0)))
+	}
+}
+
+// openKeystore returns the selected keystore.
+func openKeystore() (*keystore.Keystore, error) {
+	if *dir != "" {
+		return keystore.NewKeystore(*dir), nil
+	}
+
+	return keystore.DefaultKeystore()
+}
+
+func printKey(info *keystore.KeyInfo) {
+	encrypted := ""
+	if info.Encrypted {
+		encrypted = " (encrypted)"
+	}
+	fmt.Printf("%s %s%s\n", info.Name, info.PublicKey, encrypted)
+}
+
+func requireArgs(count int) {
+	if flag.NArg() < count {
+		handleError(fmt.Errorf("Error: '%s' requires %d arguments", flag.Arg(0), count - 1))
+	}
+}
+
+func handleError(err error) {
+	fmt.Println(err)
+	os.Exit(-1)
+}
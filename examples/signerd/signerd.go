@@ -0,0 +1,90 @@
+// signerd is a signing daemon holding a Sawtooth private key, so clients can sign transactions
+// and batches (with signer.DaemonSigner) without the key in their own memory.
+//
+// The daemon listens on a unix socket (only accessible to its user) or, if --listen is given, on
+// a TCP address. If SIGNERD_TOKEN is set in the environment, clients must send it as a bearer
+// token. An encrypted key file is decrypted with the passphrase in SIGNERD_PASSPHRASE. Signing can
+// be restricted to the transactions of some families (--family) or of a batcher (--batcher).
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	flag "github.com/spf13/pflag"
+	"github.com/taekion-org/sawtooth-client-sdk-go/keystore"
+	"github.com/taekion-org/sawtooth-client-sdk-go/signer"
+)
+
+const DEFAULT_SOCKET = "signerd.sock"
+
+// TOKEN_VARIABLE is the environment variable holding the bearer token clients must send.
+const TOKEN_VARIABLE = "SIGNERD_TOKEN"
+
+// PASSPHRASE_VARIABLE is the environment variable holding the passphrase of an encrypted key file.
+const PASSPHRASE_VARIABLE = "SIGNERD_PASSPHRASE"
+
+func main() {
+	var keyFile *string = flag.String("keyfile", "", "Path of the private key file (plaintext or encrypted)")
+	var socketPath *string = flag.String("socket", DEFAULT_SOCKET, "Path of the unix socket to listen on")
+	var listen *string = flag.String("listen", "", "TCP address to listen on instead of the unix socket")
+	var families *[]string = flag.StringSlice("family", nil, "Transaction family allowed to be signed (may be repeated)")
+	var batcher *string = flag.String("batcher", "", "Public key of the only batcher allowed")
+	flag.Parse()
+
+	if *keyFile == "" {
+		fmt.Printf("Usage: %s --keyfile [path] {--socket [path]} {--listen [host:port]}\n", os.Args[0])
+		os.Exit(0)
+	}
+
+	privateKey, err := keystore.ReadKeyFile(*keyFile, []byte(os.Getenv(PASSPHRASE_VARIABLE)))
+	if err != nil {
+		handleError(err)
+	}
+	memorySigner := signer.NewMemorySigner(privateKey)
+
+	var listener net.Listener
+	if *listen != "" {
+		listener, err = net.Listen("tcp", *listen)
+	} else {
+		// Remove a socket left by a previous run, and restrict the new one to our user
+		os.Remove(*socketPath)
+		listener, err = net.Listen("unix", *socketPath)
+		if err == nil {
+			err = os.Chmod(*socketPath, 0600)
+		}
+	}
+	if err != nil {
+		handleError(err)
+	}
+
+	// Close the listener (removing the socket) when interrupted
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		listener.Close()
+	}()
+
+	handler := signer.NewDaemonHandlerWithOptions(memorySigner, &signer.DaemonHandlerOptions{
+		Token: os.Getenv(TOKEN_VARIABLE),
+		FamilyNames: *families,
+		BatcherPublicKey: *batcher,
+	})
+	fmt.Fprintf(os.Stderr, "Serving public key %s on %s\n", memorySigner.PublicKey(), listener.Addr())
+
+	err = http.Serve(listener, handler)
+	if err != nil && !strings.Contains(err.Error(), "use of closed network connection") {
+		handleError(err)
+	}
+}
+
+func handleError(err error) {
+	fmt.Println(err)
+	os.Exit(-1)
+}
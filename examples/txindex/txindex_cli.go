@@ -0,0 +1,165 @@
+// txindex_cli maintains a local index of the transactions on a Sawtooth chain, and queries it by
+// family, signer, address and block number.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	_ "github.com/taekion-org/sawtooth-client-sdk-go/archive"
+	"github.com/taekion-org/sawtooth-client-sdk-go/indexer"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+const DEFAULT_REST_URL = "http://localhost:8008"
+const DEFAULT_ZMQ_URL = "tcp://localhost:4004"
+const DEFAULT_TRANSPORT = "rest"
+const DEFAULT_INDEX = "txindex.journal"
+const DEFAULT_PROGRESS_INTERVAL = 1000
+
+const CMD_SYNC = "sync"
+const CMD_FOLLOW = "follow"
+const CMD_QUERY = "query"
+
+var rest_url *string = flag.String("rest_url", DEFAULT_REST_URL, "Sawtooth REST API URL")
+var zmq_url *string = flag.String("zmq_url", DEFAULT_ZMQ_URL, "Sawtooth ZMQ URL")
+var transportName *string = flag.String("transport", DEFAULT_TRANSPORT, "Sawtooth Transport")
+var archive_url *string = flag.String("archive_url", "", "Chain archive URL (file:///path/to/archive?snapshot=/path/to/snapshot)")
+var indexPath *string = flag.String("index", DEFAULT_INDEX, "Path of the index journal")
+var first *uint64 = flag.Uint64("first", 0, "Number of the first block to index (for a new index)")
+var fetch *int = flag.Int("fetch", 0, "Number of blocks to fetch per request")
+var interval *time.Duration = flag.Duration("interval", indexer.DEFAULT_POLL_INTERVAL, "Time between checks for new blocks")
+var progress *uint = flag.Uint("progress", DEFAULT_PROGRESS_INTERVAL, "Report progress every n blocks (0 to disable)")
+
+var family *string = flag.String("family", "", "Transaction family name")
+var version *string = flag.String("version", "", "Transaction family version")
+var signer *string = flag.String("signer", "", "Signer public key")
+var address *string = flag.String("address", "", "State address (or namespace) read or written")
+var fromBlock *uint64 = flag.Uint64("from", 0, "First block number to search")
+var toBlock *int64 = flag.Int64("to", -1, "Last block number to search (default: all)")
+var limit *int = flag.Int("limit", 0, "Maximum number of transactions to list")
+var reverse *bool = flag.Bool("reverse", false, "List the newest transactions first")
+
+func main() {
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		fmt.Printf("Usage: %s sync|follow|query {--index [path]} {--transport [rest|zmq|archive]} {--family [name]} {--signer [public_key]} {--address [address]}\n", os.Args[0])
+		os.Exit(0)
+	}
+
+	index, err := indexer.OpenIndex(*indexPath)
+	if err != nil {
+		handleError(err)
+	}
+	defer index.Close()
+
+	switch flag.Arg(0) {
+	case CMD_SYNC:
+		cmdSync(index)
+	case CMD_FOLLOW:
+		cmdFollow(index)
+	case CMD_QUERY:
+		cmdQuery(index)
+	default:
+		handleError(fmt.Errorf("Error: '%s' is an invalid command", flag.Arg(0)))
+	}
+}
+
+func cmdSync(index *indexer.Index) {
+	result, err := newIndexer(index, nil).Sync()
+	if err != nil {
+		handleError(err)
+	}
+
+	blocks, transactions := index.Len()
+	fmt.Printf("Added %d blocks, removed %d blocks (head %s)\n", result.Added, result.Removed, result.Head)
+	fmt.Printf("Index holds %d blocks, %d transactions\n", blocks, transactions)
+}
+
+func cmdFollow(index *indexer.Index) {
+	onError := func(err error) {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+	}
+
+	// Run until interrupted
+	err := newIndexer(index, onError).Run(nil)
+	if err != nil {
+		handleError(err)
+	}
+}
+
+func cmdQuery(index *indexer.Index) {
+	query := &indexer.Query{
+		FamilyName: *family,
+		FamilyVersion: *version,
+		Signer: *signer,
+		Address: *address,
+		FirstBlockNum: *fromBlock,
+		Limit: *limit,
+		Reverse: *reverse,
+	}
+	if *toBlock >= 0 {
+		last := uint64(*toBlock)
+		query.LastBlockNum = &last
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	for _, record := range index.Query(query) {
+		err := encoder.Encode(record)
+		if err != nil {
+			handleError(err)
+		}
+	}
+}
+
+// newIndexer returns an Indexer updating index from the selected transport.
+func newIndexer(index *indexer.Index, onError func(err error)) *indexer.Indexer {
+	clientTransport, err := newTransport()
+	if err != nil {
+		handleError(err)
+	}
+
+	options := &indexer.IndexerOptions{
+		FirstBlockNum: *first,
+		Fetch: *fetch,
+		PollInterval: *interval,
+		OnError: onError,
+	}
+	if *progress > 0 {
+		options.Progress = func(block *types.Block) {
+			if block.Header.BlockNum % uint64(*progress) == 0 {
+				fmt.Fprintf(os.Stderr, "Indexed block %d\n", block.Header.BlockNum)
+			}
+		}
+	}
+
+	return indexer.NewIndexerWithOptions(clientTransport, index, options)
+}
+
+// newTransport connects to the validator (or opens the chain archive) with the selected transport.
+func newTransport() (transport.SawtoothClientTransport, error) {
+	transportType := transport.SawtoothClientTransportType(*transportName)
+	transportUrls := map[transport.SawtoothClientTransportType]string{
+		transport.TRANSPORT_REST: *rest_url,
+		transport.TRANSPORT_ZMQ: *zmq_url,
+		transport.TRANSPORT_ARCHIVE: *archive_url,
+	}
+
+	parsedUrl, err := url.Parse(transportUrls[transportType])
+	if err != nil {
+		return nil, err
+	}
+
+	return transport.NewSawtoothClientTransport(transportType, parsedUrl)
+}
+
+func handleError(err error) {
+	fmt.Println(err)
+	os.Exit(-1)
+}
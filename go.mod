@@ -1,12 +1,21 @@
 module github.com/taekion-org/sawtooth-client-sdk-go
 
-go 1.16
+go 1.23
 
 require (
 	github.com/brianolson/cbor_go v1.0.0
+	github.com/btcsuite/btcd v0.21.0-beta
 	github.com/golang/protobuf v1.4.3
 	github.com/hyperledger/sawtooth-sdk-go v0.1.4
 	github.com/pebbe/zmq4 v1.2.7
 	github.com/sirupsen/logrus v1.8.1
 	github.com/spf13/pflag v1.0.5
+	github.com/tyler-smith/go-bip39 v1.1.0
+	golang.org/x/crypto v0.31.0
+)
+
+require (
+	github.com/satori/go.uuid v1.2.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	google.golang.org/protobuf v1.25.0 // indirect
 )
@@ -0,0 +1,94 @@
+package hdkey
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/sawtooth-sdk-go/signing"
+)
+
+// PURPOSE is the BIP-44 purpose used in the derivation paths of a KeyDeriver.
+const PURPOSE = 44
+
+// SAWTOOTH_COIN_TYPE is the coin type used in the derivation paths of a KeyDeriver. Sawtooth has
+// no registered SLIP-44 coin type, so this is "SAWT" in ASCII, which keeps Sawtooth keys apart from
+// the keys of cryptocurrency wallets sharing the seed.
+const SAWTOOTH_COIN_TYPE = 0x53415754
+
+// KeyDeriver derives one key per user of an application from a seed, at the path
+// m/44'/SAWTOOTH_COIN_TYPE'/application'/user' (all hardened, so a leaked user key reveals
+// nothing about the others). Users are numbered from 0; the application must keep the mapping
+// from its users to their numbers.
+type KeyDeriver struct {
+	application	uint32
+	// account is the key of the application, from which user keys are derived in one step.
+	account		*ExtendedKey
+}
+
+// NewKeyDeriver returns a KeyDeriver for an application, numbered from 0, from a seed.
+func NewKeyDeriver(seed []byte, application uint32) (*KeyDeriver, error) {
+	if application >= HARDENED_OFFSET {
+		return nil, fmt.Errorf("Application number %d is too large", application)
+	}
+
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := master.Derive(ApplicationPath(application))
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyDeriver{application: application, account: account}, nil
+}
+
+// NewKeyDeriverFromMnemonic returns a KeyDeriver for an application from a BIP-39 mnemonic and
+// optional passphrase.
+func NewKeyDeriverFromMnemonic(mnemonic string, passphrase string, application uint32) (*KeyDeriver, error) {
+	seed, err := SeedFromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyDeriver(seed, application)
+}
+
+// ApplicationPath returns the derivation path of an application's key.
+func ApplicationPath(application uint32) string {
+	return fmt.Sprintf("m/%d'/%d'/%d'", PURPOSE, SAWTOOTH_COIN_TYPE, application)
+}
+
+// UserPath returns the derivation path of the key of a user of an application.
+func UserPath(application uint32, user uint32) string {
+	return fmt.Sprintf("%s/%d'", ApplicationPath(application), user)
+}
+
+// Path returns the derivation path of the key of a user.
+func (self *KeyDeriver) Path(user uint32) string {
+	return UserPath(self.application, user)
+}
+
+// UserKey returns the private key of a user, for use as SawtoothClientArgs.PrivateKey.
+func (self *KeyDeriver) UserKey(user uint32) (signing.PrivateKey, error) {
+	if user >= HARDENED_OFFSET {
+		return nil, fmt.Errorf("User number %d is too large", user)
+	}
+
+	key, err := self.account.Child(user + HARDENED_OFFSET)
+	if err != nil {
+		return nil, fmt.Errorf("Error deriving key of user %d: %s", user, err)
+	}
+
+	return key.PrivateKey()
+}
+
+// UserPublicKey returns the hex encoded public key of a user.
+func (self *KeyDeriver) UserPublicKey(user uint32) (string, error) {
+	privateKey, err := self.UserKey(user)
+	if err != nil {
+		return "", err
+	}
+
+	return signing.NewSecp256k1Context().GetPublicKey(privateKey).AsHex(), nil
+}
@@ -0,0 +1,211 @@
+// Package hdkey derives Sawtooth signing keys from a single seed, using BIP-39 mnemonics and
+// BIP-32 hierarchical deterministic derivation on secp256k1.
+//
+// Deriving keys from one protected seed replaces the backup of many independent key files: any
+// key can be derived again from the seed (or its mnemonic) and its derivation path. A KeyDeriver
+// derives one key per user of an application.
+package hdkey
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/hyperledger/sawtooth-sdk-go/signing"
+	"github.com/taekion-org/sawtooth-client-sdk-go/keystore"
+)
+
+// HARDENED_OFFSET is added to a child index to select hardened derivation.
+const HARDENED_OFFSET = 0x80000000
+
+// MIN_SEED_LENGTH and MAX_SEED_LENGTH bound the length of a BIP-32 seed, in bytes.
+const MIN_SEED_LENGTH = 16
+const MAX_SEED_LENGTH = 64
+
+// masterKeySecret is the HMAC key used to derive a master key from a seed (from BIP-32).
+var masterKeySecret = []byte("Bitcoin seed")
+
+// ExtendedKey is a BIP-32 extended key: a private or public secp256k1 key with a chain code,
+// from which child keys are derived.
+type ExtendedKey struct {
+	// key is the 32 byte private key, or the 33 byte compressed public key.
+	key			[]byte
+	chainCode	[]byte
+	private		bool
+}
+
+// NewMasterKey returns the BIP-32 master key of a seed (for example, from SeedFromMnemonic).
+func NewMasterKey(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < MIN_SEED_LENGTH || len(seed) > MAX_SEED_LENGTH {
+		return nil, fmt.Errorf("Seed must be between %d and %d bytes long", MIN_SEED_LENGTH, MAX_SEED_LENGTH)
+	}
+
+	mac := hmac.New(sha512.New, masterKeySecret)
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	key := new(big.Int).SetBytes(sum[:32])
+	if key.Sign() == 0 || key.Cmp(btcec.S256().N) >= 0 {
+		return nil, fmt.Errorf("Seed does not produce a valid master key")
+	}
+
+	return &ExtendedKey{key: sum[:32], chainCode: sum[32:], private: true}, nil
+}
+
+// IsPrivate returns true if the extended key holds a private key.
+func (self *ExtendedKey) IsPrivate() bool {
+	return self.private
+}
+
+// Child returns the child key at index, which is hardened if index >= HARDENED_OFFSET. Hardened
+// children can only be derived from private keys. As specified by BIP-32, a small number of
+// indexes have no valid key, in which case an error is returned and the next index should be used.
+func (self *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	curve := btcec.S256()
+	hardened := index >= HARDENED_OFFSET
+
+	var data []byte
+	if hardened {
+		if !self.private {
+			return nil, fmt.Errorf("Cannot derive hardened child %d from a public key", index - HARDENED_OFFSET)
+		}
+		data = append([]byte{0}, self.key...)
+	} else {
+		data = self.publicKeyBytes()
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, self.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(curve.N) >= 0 {
+		return nil, fmt.Errorf("No valid key at index %d", index)
+	}
+
+	child := &ExtendedKey{chainCode: sum[32:], private: self.private}
+	if self.private {
+		key := new(big.Int).Add(tweak, new(big.Int).SetBytes(self.key))
+		key.Mod(key, curve.N)
+		if key.Sign() == 0 {
+			return nil, fmt.Errorf("No valid key at index %d", index)
+		}
+		child.key = key.FillBytes(make([]byte, keystore.PRIVATE_KEY_LENGTH))
+	} else {
+		parent, err := btcec.ParsePubKey(self.key, curve)
+		if err != nil {
+			return nil, err
+		}
+		x, y := curve.ScalarBaseMult(sum[:32])
+		x, y = curve.Add(x, y, parent.X, parent.Y)
+		if x.Sign() == 0 && y.Sign() == 0 {
+			return nil, fmt.Errorf("No valid key at index %d", index)
+		}
+		child.key = (&btcec.PublicKey{Curve: curve, X: x, Y: y}).SerializeCompressed()
+	}
+
+	return child, nil
+}
+
+// Derive returns the key at a derivation path relative to this key, such as "m/44'/0'/1" (a
+// leading "m" is optional). Hardened indexes are marked with ' or h.
+func (self *ExtendedKey) Derive(path string) (*ExtendedKey, error) {
+	indexes, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := self
+	for _, index := range indexes {
+		key, err = key.Child(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
+
+// Public returns the public extended key of this key, which can derive the public keys of
+// non-hardened children without access to the private key.
+func (self *ExtendedKey) Public() *ExtendedKey {
+	if !self.private {
+		return self
+	}
+
+	return &ExtendedKey{key: self.publicKeyBytes(), chainCode: self.chainCode, private: false}
+}
+
+// PrivateKey returns the private key, for use as SawtoothClientArgs.PrivateKey.
+func (self *ExtendedKey) PrivateKey() (signing.PrivateKey, error) {
+	if !self.private {
+		return nil, fmt.Errorf("Extended key is public")
+	}
+
+	return keystore.NewPrivateKey(self.key)
+}
+
+// PublicKey returns the hex encoded (compressed) public key.
+func (self *ExtendedKey) PublicKey() string {
+	return hex.EncodeToString(self.publicKeyBytes())
+}
+
+// publicKeyBytes returns the compressed public key.
+func (self *ExtendedKey) publicKeyBytes() []byte {
+	if !self.private {
+		return self.key
+	}
+
+	_, publicKey := btcec.PrivKeyFromBytes(btcec.S256(), self.key)
+	return publicKey.SerializeCompressed()
+}
+
+// ParsePath parses a derivation path such as "m/44'/0'/1" into child indexes. Hardened indexes
+// (marked with ' or h) have HARDENED_OFFSET added.
+func ParsePath(path string) ([]uint32, error) {
+	elements := strings.Split(strings.TrimSpace(path), "/")
+	if elements[0] == "m" {
+		elements = elements[1:]
+	}
+
+	indexes := make([]uint32, 0, len(elements))
+	for _, element := range elements {
+		hardened := strings.HasSuffix(element, "'") || strings.HasSuffix(element, "h")
+		if hardened {
+			element = element[:len(element) - 1]
+		}
+
+		index, err := strconv.ParseUint(element, 10, 32)
+		if err != nil || index >= HARDENED_OFFSET {
+			return nil, fmt.Errorf("Invalid derivation path: %s", path)
+		}
+		if hardened {
+			index += HARDENED_OFFSET
+		}
+		indexes = append(indexes, uint32(index))
+	}
+
+	return indexes, nil
+}
+
+// DerivePrivateKey returns the private key at a derivation path from the master key of a seed.
+func DerivePrivateKey(seed []byte, path string) (signing.PrivateKey, error) {
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := master.Derive(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return key.PrivateKey()
+}
@@ -0,0 +1,170 @@
+package hdkey_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/sawtooth-sdk-go/signing"
+	"github.com/taekion-org/sawtooth-client-sdk-go/hdkey"
+	"github.com/taekion-org/sawtooth-client-sdk-go/keystore"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+func TestHDKey(t *testing.T) {
+	// BIP-32 test vector 1
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := hdkey.NewMasterKey(seed)
+	if err != nil {
+		t.Fatalf("NewMasterKey: %s", err)
+	}
+	if master.PublicKey() != "0339a36013301597daef41fbe593a02cc513d0b55527ec2df1050e2e8ff49c85c2" {
+		t.Fatalf("NewMasterKey: unexpected public key %s", master.PublicKey())
+	}
+
+	vectors := map[string]string{
+		"m": "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+		"m/0'": "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+		"m/0'/1": "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+		"m/0h/1/2h": "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca",
+		"m/0'/1/2'/2": "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4",
+		"m/0'/1/2'/2/1000000000": "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8",
+	}
+	for path, expected := range vectors {
+		privateKey, err := hdkey.DerivePrivateKey(seed, path)
+		if err != nil {
+			t.Fatalf("DerivePrivateKey(%s): %s", path, err)
+		}
+		if privateKey.AsHex() != expected {
+			t.Fatalf("DerivePrivateKey(%s): got %s, expected %s", path, privateKey.AsHex(), expected)
+		}
+	}
+
+	// Public keys of non-hardened children can be derived from the public key
+	parent, err := master.Derive("m/0'/1")
+	if err != nil {
+		t.Fatalf("Derive: %s", err)
+	}
+	privateChild, err := parent.Child(7)
+	if err != nil {
+		t.Fatalf("Child: %s", err)
+	}
+	publicChild, err := parent.Public().Child(7)
+	if err != nil {
+		t.Fatalf("Child(public): %s", err)
+	}
+	if publicChild.IsPrivate() || publicChild.PublicKey() != privateChild.PublicKey() {
+		t.Fatalf("Child(public): got %s, expected %s", publicChild.PublicKey(), privateChild.PublicKey())
+	}
+	_, err = parent.Public().Child(hdkey.HARDENED_OFFSET)
+	if err == nil {
+		t.Fatalf("Child(public, hardened): expected an error")
+	}
+	_, err = publicChild.PrivateKey()
+	if err == nil {
+		t.Fatalf("PrivateKey(public): expected an error")
+	}
+
+	for _, path := range []string{"", "m/", "m/x", "m/1''", "m/2147483648", "m/-1"} {
+		_, err = hdkey.ParsePath(path)
+		if err == nil {
+			t.Fatalf("ParsePath(%s): expected an error", path)
+		}
+	}
+	_, err = hdkey.NewMasterKey(seed[:8])
+	if err == nil {
+		t.Fatalf("NewMasterKey(short): expected an error")
+	}
+
+	// BIP-39 mnemonics
+	zero := strings.Repeat("abandon ", 11) + "about"
+	err = hdkey.ValidateMnemonic(zero)
+	if err != nil {
+		t.Fatalf("ValidateMnemonic: %s", err)
+	}
+	err = hdkey.ValidateMnemonic(strings.Repeat("abandon ", 12))
+	if err == nil {
+		t.Fatalf("ValidateMnemonic(bad checksum): expected an error")
+	}
+	mnemonic, err := hdkey.NewMnemonic(128)
+	if err != nil || len(strings.Fields(mnemonic)) != 12 || hdkey.ValidateMnemonic(mnemonic) != nil {
+		t.Fatalf("NewMnemonic(128): got %q (%v)", mnemonic, err)
+	}
+	mnemonic, err = hdkey.NewMnemonic(hdkey.DEFAULT_MNEMONIC_BITS)
+	if err != nil || len(strings.Fields(mnemonic)) != 24 {
+		t.Fatalf("NewMnemonic: got %q (%v)", mnemonic, err)
+	}
+	_, err = hdkey.NewMnemonic(100)
+	if err == nil {
+		t.Fatalf("NewMnemonic(100): expected an error")
+	}
+
+	// BIP-39 test vector for zero entropy
+	mnemonicSeed, err := hdkey.SeedFromMnemonic(zero, "TREZOR")
+	if err != nil || hex.EncodeToString(mnemonicSeed) != "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04" {
+		t.Fatalf("SeedFromMnemonic: got %x (%v)", mnemonicSeed, err)
+	}
+	normalizedSeed, err := hdkey.SeedFromMnemonic("  "+strings.ToUpper(strings.Replace(zero, " ", "  ", -1)), "TREZOR")
+	if err != nil || !bytes.Equal(normalizedSeed, mnemonicSeed) {
+		t.Fatalf("SeedFromMnemonic(unnormalized): seeds differ (%v)", err)
+	}
+	otherSeed, err := hdkey.SeedFromMnemonic(zero, "")
+	if err != nil || bytes.Equal(otherSeed, mnemonicSeed) {
+		t.Fatalf("SeedFromMnemonic(no passphrase): expected a different seed (%v)", err)
+	}
+
+	// One key per user of an application
+	deriver, err := hdkey.NewKeyDeriverFromMnemonic(zero, "TREZOR", 3)
+	if err != nil {
+		t.Fatalf("NewKeyDeriverFromMnemonic: %s", err)
+	}
+	otherApplication, err := hdkey.NewKeyDeriver(mnemonicSeed, 4)
+	if err != nil {
+		t.Fatalf("NewKeyDeriver: %s", err)
+	}
+
+	seen := make(map[string]bool)
+	for user := uint32(0); user < 5; user++ {
+		userKey, err := deriver.UserKey(user)
+		if err != nil {
+			t.Fatalf("UserKey(%d): %s", user, err)
+		}
+		expected, err := hdkey.DerivePrivateKey(mnemonicSeed, deriver.Path(user))
+		if err != nil || userKey.AsHex() != expected.AsHex() {
+			t.Fatalf("UserKey(%d): does not match %s (%v)", user, deriver.Path(user), err)
+		}
+		publicKey, err := deriver.UserPublicKey(user)
+		if err != nil || publicKey != keystore.PublicKey(userKey) {
+			t.Fatalf("UserPublicKey(%d): got %s (%v)", user, publicKey, err)
+		}
+
+		otherKey, err := otherApplication.UserKey(user)
+		if err != nil {
+			t.Fatalf("UserKey(%d, other application): %s", user, err)
+		}
+		for _, key := range []string{userKey.AsHex(), otherKey.AsHex()} {
+			if seen[key] {
+				t.Fatalf("UserKey(%d): duplicate key", user)
+			}
+			seen[key] = true
+		}
+	}
+	if deriver.Path(9) != fmt.Sprintf("m/44'/%d'/3'/9'", hdkey.SAWTOOTH_COIN_TYPE) {
+		t.Fatalf("Path: got %s", deriver.Path(9))
+	}
+	_, err = deriver.UserKey(hdkey.HARDENED_OFFSET)
+	if err == nil {
+		t.Fatalf("UserKey(too large): expected an error")
+	}
+
+	// Derived keys create valid signatures
+	userKey, _ := deriver.UserKey(1)
+	message := []byte("derived")
+	signature := signing.NewCryptoFactory(signing.NewSecp256k1Context()).NewSigner(userKey).Sign(message)
+	err = types.VerifySignature(message, hex.EncodeToString(signature), keystore.PublicKey(userKey))
+	if err != nil {
+		t.Fatalf("Sign(derived key): %s", err)
+	}
+}
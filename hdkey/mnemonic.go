@@ -0,0 +1,49 @@
+package hdkey
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DEFAULT_MNEMONIC_BITS is the default entropy of a new mnemonic (24 words).
+const DEFAULT_MNEMONIC_BITS = 256
+
+// NewMnemonic returns a new random BIP-39 mnemonic (in English) with the given entropy, which must
+// be a multiple of 32 between 128 (12 words) and 256 (24 words) bits.
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", fmt.Errorf("Error generating mnemonic: %s", err)
+	}
+
+	return bip39.NewMnemonic(entropy)
+}
+
+// ValidateMnemonic returns an error if mnemonic is not a valid BIP-39 mnemonic (in English), with
+// a valid checksum.
+func ValidateMnemonic(mnemonic string) error {
+	_, err := bip39.EntropyFromMnemonic(normalizeMnemonic(mnemonic))
+	if err != nil {
+		return fmt.Errorf("Invalid mnemonic: %s", err)
+	}
+
+	return nil
+}
+
+// SeedFromMnemonic returns the BIP-39 seed of a mnemonic, protected by an optional passphrase.
+// The mnemonic is validated first.
+func SeedFromMnemonic(mnemonic string, passphrase string) ([]byte, error) {
+	err := ValidateMnemonic(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	return bip39.NewSeed(normalizeMnemonic(mnemonic), passphrase), nil
+}
+
+// normalizeMnemonic lowercases a mnemonic and separates its words with single spaces.
+func normalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+}
@@ -0,0 +1,188 @@
+package sawtooth_client_sdk_go
+
+import (
+	"fmt"
+
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// TransactionHistoryEntry is a committed transaction of the client's family, with its decoded payload.
+type TransactionHistoryEntry struct {
+	// Payload is the transaction payload, decoded by ClientImpl.DecodePayload.
+	Payload			SawtoothPayload
+	Header			types.TransactionHeader
+	TransactionId	string
+	BatchId			string
+	BlockId			string
+	BlockNum		uint64
+}
+
+// TransactionHistoryOptions holds optional settings for GetTransactionHistory.
+type TransactionHistoryOptions struct {
+	// Head is the id of the block the history is read up to. If empty, the current chain head is used.
+	Head			string
+	// FirstBlockNum and LastBlockNum restrict the history to a range of blocks. If LastBlockNum is
+	// nil, the range extends to the head.
+	FirstBlockNum	uint64
+	LastBlockNum	*uint64
+	// Fetch is the page size used to read blocks (0 uses the transport default).
+	Fetch			int
+	// Reverse returns the oldest transactions first.
+	Reverse			bool
+	// SkipInvalid skips transactions whose payload cannot be decoded, instead of ending the
+	// iteration with an error.
+	SkipInvalid		bool
+}
+
+// GetTransactionHistory returns an iterator over the committed transactions of the client's family
+// and version, newest first unless options.Reverse is set. Each payload is decoded into a new value
+// returned by newPayload (e.g. func() SawtoothPayload { return &MyPayload{} }). The history is read
+// from a single fork, even if the chain head moves during the iteration.
+func (self *SawtoothClient) GetTransactionHistory(newPayload func() SawtoothPayload, options *TransactionHistoryOptions) types.Iterator[*TransactionHistoryEntry] {
+	iterator := &transactionHistoryIterator{client: self, newPayload: newPayload}
+	if options != nil {
+		iterator.options = *options
+	}
+
+	iterator.err = iterator.start()
+	return iterator
+}
+
+// transactionHistoryIterator implements types.Iterator for GetTransactionHistory.
+type transactionHistoryIterator struct {
+	client			*SawtoothClient
+	newPayload		func() SawtoothPayload
+	options			TransactionHistoryOptions
+
+	blocks			types.BlockIterator
+	pending			[]*TransactionHistoryEntry
+	current			*TransactionHistoryEntry
+	err				error
+}
+
+// start resolves the head and range of the history, and starts iterating over its blocks.
+func (self *transactionHistoryIterator) start() error {
+	headId := self.options.Head
+	if headId == "" {
+		var err error
+		headId, err = transport.CurrentHead(self.client.Transport)
+		if err != nil {
+			return err
+		}
+	}
+
+	head, err := self.client.Transport.GetBlock(headId)
+	if err != nil {
+		return err
+	}
+
+	lastBlockNum := head.Header.BlockNum
+	if self.options.LastBlockNum != nil && *self.options.LastBlockNum < lastBlockNum {
+		lastBlockNum = *self.options.LastBlockNum
+	}
+	if self.options.FirstBlockNum > lastBlockNum {
+		return nil
+	}
+
+	cursor, err := types.NewBlockRangeCursor(self.options.FirstBlockNum, lastBlockNum, self.options.Fetch, self.options.Reverse)
+	if err != nil {
+		return err
+	}
+	cursor.Head = headId
+
+	self.blocks = self.client.Transport.GetBlockIteratorFromCursor(cursor)
+	return nil
+}
+
+func (self *transactionHistoryIterator) Next() bool {
+	self.current = nil
+
+	for len(self.pending) == 0 {
+		if self.err != nil || self.blocks == nil {
+			return false
+		}
+
+		if !self.blocks.Next() {
+			self.err = self.blocks.Error()
+			self.blocks = nil
+			return false
+		}
+
+		block, err := self.blocks.Current()
+		if err == nil {
+			err = self.addBlock(block)
+		}
+		if err != nil {
+			self.err = err
+			return false
+		}
+	}
+
+	self.current, self.pending = self.pending[0], self.pending[1:]
+	return true
+}
+
+// addBlock decodes the transactions of the client's family in a block, and queues them in the
+// order of the iteration.
+func (self *transactionHistoryIterator) addBlock(block *types.Block) error {
+	familyName := self.client.ClientImpl.GetFamilyName()
+	familyVersion := self.client.ClientImpl.GetFamilyVersion()
+
+	var entries []*TransactionHistoryEntry
+	for _, batch := range block.Batches {
+		for _, transaction := range batch.Transactions {
+			if transaction.Header.FamilyName != familyName || transaction.Header.FamilyVersion != familyVersion {
+				continue
+			}
+
+			payload := self.newPayload()
+			err := self.client.ClientImpl.DecodePayload(transaction.Payload, payload)
+			if err != nil {
+				if self.options.SkipInvalid {
+					continue
+				}
+				return fmt.Errorf("Error decoding payload of transaction %s in block %d: %s", transaction.HeaderSignature, block.Header.BlockNum, err)
+			}
+
+			entries = append(entries, &TransactionHistoryEntry{
+				Payload: payload,
+				Header: transaction.Header,
+				TransactionId: transaction.HeaderSignature,
+				BatchId: batch.HeaderSignature,
+				BlockId: block.HeaderSignature,
+				BlockNum: block.Header.BlockNum,
+			})
+		}
+	}
+
+	// Newest first means the last transaction of each block first
+	if !self.options.Reverse {
+		for i, j := 0, len(entries) - 1; i < j; i, j = i + 1, j - 1 {
+			entries[i], entries[j] = entries[j], entries[i]
+		}
+	}
+
+	self.pending = append(self.pending, entries...)
+	return nil
+}
+
+func (self *transactionHistoryIterator) Current() (*TransactionHistoryEntry, error) {
+	if self.current == nil {
+		return nil, fmt.Errorf("No current value in iterator...")
+	}
+
+	return self.current, nil
+}
+
+func (self *transactionHistoryIterator) Error() error {
+	return self.err
+}
+
+func (self *transactionHistoryIterator) Close() {
+	if self.blocks != nil {
+		self.blocks.Close()
+		self.blocks = nil
+	}
+	self.pending = nil
+}
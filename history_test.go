@@ -0,0 +1,94 @@
+package sawtooth_client_sdk_go_test
+
+import (
+	"fmt"
+	"testing"
+
+	sawtooth_client_sdk_go "github.com/taekion-org/sawtooth-client-sdk-go"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/conformance"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+func TestTransactionHistory(t *testing.T) {
+	fixture := conformance.NewFixture()
+	clientTransport := conformance.NewRestTransport(t, conformance.NewValidator(fixture))
+
+	impl := &fixtureImpl{}
+	client := &sawtooth_client_sdk_go.SawtoothClient{Transport: clientTransport, ClientImpl: impl}
+	newPayload := func() sawtooth_client_sdk_go.SawtoothPayload { return &fixturePayload{} }
+
+	// expected returns the history of blocks first to last, oldest first
+	type entry struct {
+		value		string
+		transaction	string
+		batch		string
+		blockNum	uint64
+	}
+	expected := func(first int, last int) []entry {
+		var entries []entry
+		for blockNum, block := range fixture.Blocks[first:last + 1] {
+			for _, batch := range block.Batches {
+				for _, transaction := range batch.Transactions {
+					entries = append(entries, entry{string(transaction.Payload), transaction.HeaderSignature, batch.HeaderSignature, uint64(first + blockNum)})
+				}
+			}
+		}
+		return entries
+	}
+	check := func(description string, options *sawtooth_client_sdk_go.TransactionHistoryOptions, want []entry) {
+		t.Helper()
+
+		histories, err := types.Collect(client.GetTransactionHistory(newPayload, options))
+		if err != nil {
+			t.Fatalf("%s: %s", description, err)
+		}
+
+		var got []entry
+		for _, history := range histories {
+			if history.Header.FamilyName != conformance.FIXTURE_FAMILY_NAME || history.BlockId != fixture.Blocks[history.BlockNum].HeaderSignature {
+				t.Fatalf("%s: unexpected entry %+v", description, history)
+			}
+			got = append(got, entry{history.Payload.(*fixturePayload).Value, history.TransactionId, history.BatchId, history.BlockNum})
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("%s: got %d entries %v, expected %d %v", description, len(got), got, len(want), want)
+		}
+	}
+
+	head := len(fixture.Blocks) - 1
+	last := uint64(7)
+	check("GetTransactionHistory()", nil, reversed(expected(0, head)))
+	check("GetTransactionHistory(reverse)", &sawtooth_client_sdk_go.TransactionHistoryOptions{Reverse: true, Fetch: 2}, expected(0, head))
+	check("GetTransactionHistory(range)", &sawtooth_client_sdk_go.TransactionHistoryOptions{FirstBlockNum: 3, LastBlockNum: &last}, reversed(expected(3, 7)))
+	check("GetTransactionHistory(head)", &sawtooth_client_sdk_go.TransactionHistoryOptions{Head: fixture.Blocks[5].HeaderSignature, Reverse: true}, expected(0, 5))
+
+	// Transactions of other versions of the family are not included
+	client.ClientImpl = &versionedImpl{fixtureImpl: impl, version: "2.0"}
+	check("GetTransactionHistory(other version)", nil, nil)
+	client.ClientImpl = impl
+
+	// A payload that cannot be decoded ends the iteration, unless it is skipped
+	invalid := expected(4, 4)[0]
+	impl.invalid = invalid.value
+	_, err := types.Collect(client.GetTransactionHistory(newPayload, nil))
+	if err == nil {
+		t.Fatalf("GetTransactionHistory(invalid): expected an error")
+	}
+
+	var want []entry
+	for _, e := range expected(0, head) {
+		if e != invalid {
+			want = append(want, e)
+		}
+	}
+	check("GetTransactionHistory(skip invalid)", &sawtooth_client_sdk_go.TransactionHistoryOptions{Reverse: true, SkipInvalid: true}, want)
+}
+
+// versionedImpl is a fixtureImpl for another version of the family.
+type versionedImpl struct {
+	*fixtureImpl
+	version		string
+}
+
+func (self *versionedImpl) GetFamilyVersion() string { return self.version }
+
@@ -0,0 +1,448 @@
+// Package indexer maintains a local index of the transactions committed to the chain, so they can
+// be queried by family, signer, address and block number, which the Sawtooth APIs do not support.
+//
+// An Index is held in memory and, if opened from a file, persisted to an append-only journal of
+// the blocks added and removed. An Indexer follows the chain through a transport, keeping the
+// index up to date and undoing the blocks of abandoned forks.
+package indexer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// TransactionRecord describes an indexed transaction.
+type TransactionRecord struct {
+	TransactionId	string		`json:"transaction_id"`
+	BatchId			string		`json:"batch_id"`
+	BlockId			string		`json:"block_id"`
+	BlockNum		uint64		`json:"block_num"`
+
+	FamilyName		string		`json:"family_name"`
+	FamilyVersion	string		`json:"family_version"`
+	SignerPublicKey	string		`json:"signer_public_key"`
+	Inputs			[]string	`json:"inputs"`
+	Outputs			[]string	`json:"outputs"`
+}
+
+// IndexedBlock describes a block in the index.
+type IndexedBlock struct {
+	BlockNum		uint64	`json:"block_num"`
+	BlockId			string	`json:"block_id"`
+	PreviousBlockId	string	`json:"previous_block_id"`
+
+	// first is the position of the block's first transaction in Index.records.
+	first			int
+}
+
+// journalEntry is a line of the journal: a block added (with its transactions) or removed.
+type journalEntry struct {
+	Add				*IndexedBlock			`json:"add,omitempty"`
+	Transactions	[]*TransactionRecord	`json:"transactions,omitempty"`
+	Remove			string					`json:"remove,omitempty"`
+}
+
+// Index holds the transactions of a sequence of consecutive blocks. Blocks can only be added to
+// or removed from the tip. An Index is safe for concurrent use.
+type Index struct {
+	mutex			sync.RWMutex
+
+	blocks			[]IndexedBlock
+	records			[]*TransactionRecord
+	transactions	map[string]int
+
+	// Postings list the positions of matching records, in chain order.
+	families		map[string][]int
+	signers			map[string][]int
+	addresses		map[string][]int
+
+	path			string
+	journal			*os.File
+	// journalErr, if set, is the journal failure after which the index refuses further changes:
+	// the journal may no longer match the index, which must be reopened.
+	journalErr		error
+}
+
+// NewIndex returns an empty in-memory index.
+func NewIndex() *Index {
+	return &Index{
+		transactions: make(map[string]int),
+		families: make(map[string][]int),
+		signers: make(map[string][]int),
+		addresses: make(map[string][]int),
+	}
+}
+
+// OpenIndex opens the index journal at path, creating it if it does not exist. Changes to the
+// index are appended to the journal. The Index must be closed when no longer needed.
+func OpenIndex(path string) (*Index, error) {
+	self := NewIndex()
+	self.path = path
+
+	file, err := os.OpenFile(path, os.O_RDWR | os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replay the journal, dropping an incomplete last line left by an interrupted write
+	reader := bufio.NewReader(file)
+	offset := int64(0)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("Failed to read index journal: %s", err)
+		}
+
+		var entry journalEntry
+		err = json.Unmarshal(line, &entry)
+		if err == nil {
+			err = self.apply(&entry)
+		}
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("Invalid index journal entry at offset %d: %s", offset, err)
+		}
+		offset += int64(len(line))
+	}
+
+	err = file.Truncate(offset)
+	if err == nil {
+		_, err = file.Seek(offset, io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("Failed to open index journal: %s", err)
+	}
+	self.journal = file
+
+	return self, nil
+}
+
+// Close closes the journal, if the index was opened from a file.
+func (self *Index) Close() error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if self.journal == nil {
+		return nil
+	}
+
+	err := self.journal.Close()
+	self.journal = nil
+	return err
+}
+
+// AddBlock adds the transactions of a block, which must follow the tip of the index (unless the
+// index is empty).
+func (self *Index) AddBlock(block *types.Block) error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	entry := &journalEntry{
+		Add: &IndexedBlock{BlockNum: block.Header.BlockNum, BlockId: block.HeaderSignature, PreviousBlockId: block.Header.PreviousBlockId},
+		Transactions: []*TransactionRecord{},
+	}
+	for _, batch := range block.Batches {
+		for _, transaction := range batch.Transactions {
+			entry.Transactions = append(entry.Transactions, &TransactionRecord{
+				TransactionId: transaction.HeaderSignature,
+				BatchId: batch.HeaderSignature,
+				BlockId: block.HeaderSignature,
+				BlockNum: block.Header.BlockNum,
+				FamilyName: transaction.Header.FamilyName,
+				FamilyVersion: transaction.Header.FamilyVersion,
+				SignerPublicKey: transaction.Header.SignerPublicKey,
+				Inputs: transaction.Header.Inputs,
+				Outputs: transaction.Header.Outputs,
+			})
+		}
+	}
+
+	err := self.check(entry)
+	if err == nil {
+		err = self.writeJournal(entry)
+	}
+	if err != nil {
+		return err
+	}
+
+	return self.apply(entry)
+}
+
+// RemoveTip removes the last block of the index (for example, when it has been abandoned by a
+// fork), returning false if the index is empty.
+func (self *Index) RemoveTip() (*IndexedBlock, bool, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if len(self.blocks) == 0 {
+		return nil, false, nil
+	}
+
+	tip := self.blocks[len(self.blocks) - 1]
+	entry := &journalEntry{Remove: tip.BlockId}
+	err := self.writeJournal(entry)
+	if err == nil {
+		err = self.apply(entry)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &tip, true, nil
+}
+
+// Tip returns the last block of the index, or false if the index is empty.
+func (self *Index) Tip() (*IndexedBlock, bool) {
+	self.mutex.RLock()
+	defer self.mutex.RUnlock()
+
+	if len(self.blocks) == 0 {
+		return nil, false
+	}
+
+	tip := self.blocks[len(self.blocks) - 1]
+	return &tip, true
+}
+
+// Block returns the indexed block with the given number, or false if it is not in the index.
+func (self *Index) Block(blockNum uint64) (*IndexedBlock, bool) {
+	self.mutex.RLock()
+	defer self.mutex.RUnlock()
+
+	i, ok := self.blockPosition(blockNum)
+	if !ok {
+		return nil, false
+	}
+
+	block := self.blocks[i]
+	return &block, true
+}
+
+// Len returns the number of blocks and transactions in the index.
+func (self *Index) Len() (int, int) {
+	self.mutex.RLock()
+	defer self.mutex.RUnlock()
+
+	return len(self.blocks), len(self.records)
+}
+
+// GetTransaction returns the record of a transaction, or false if it is not in the index.
+func (self *Index) GetTransaction(transactionId string) (*TransactionRecord, bool) {
+	self.mutex.RLock()
+	defer self.mutex.RUnlock()
+
+	position, ok := self.transactions[transactionId]
+	if !ok {
+		return nil, false
+	}
+
+	return self.records[position], true
+}
+
+// Compact rewrites the journal with only the blocks currently in the index, discarding the
+// history of removed blocks.
+func (self *Index) Compact() error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if self.journalErr != nil {
+		return self.journalErr
+	}
+	if self.journal == nil {
+		return nil
+	}
+
+	var buffer bytes.Buffer
+	for i := range self.blocks {
+		block := self.blocks[i]
+		entry := &journalEntry{Add: &block, Transactions: self.records[block.first:self.blockEnd(i)]}
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("Failed to serialize index journal: %s", err)
+		}
+		buffer.Write(append(data, '\n'))
+	}
+
+	temporaryPath := self.path + ".tmp"
+	err := ioutil.WriteFile(temporaryPath, buffer.Bytes(), 0644)
+	if err != nil {
+		return fmt.Errorf("Failed to compact index journal: %s", err)
+	}
+	err = os.Rename(temporaryPath, self.path)
+	if err != nil {
+		return fmt.Errorf("Failed to compact index journal: %s", err)
+	}
+
+	self.journal.Close()
+	self.journal, err = os.OpenFile(self.path, os.O_WRONLY | os.O_APPEND, 0644)
+	if err != nil {
+		self.journalErr = fmt.Errorf("Failed to reopen index journal: %s", err)
+		return self.journalErr
+	}
+
+	return nil
+}
+
+// check returns an error if a journal entry cannot be applied to the index.
+func (self *Index) check(entry *journalEntry) error {
+	if entry.Add != nil {
+		block := entry.Add
+		if len(self.blocks) > 0 {
+			tip := &self.blocks[len(self.blocks) - 1]
+			if block.BlockNum != tip.BlockNum + 1 || block.PreviousBlockId != tip.BlockId {
+				return fmt.Errorf("Block %d (%s) does not follow the index tip at block %d (%s)", block.BlockNum, block.BlockId, tip.BlockNum, tip.BlockId)
+			}
+		}
+
+		return nil
+	}
+
+	if entry.Remove != "" {
+		if len(self.blocks) == 0 || self.blocks[len(self.blocks) - 1].BlockId != entry.Remove {
+			return fmt.Errorf("Block %s is not the index tip", entry.Remove)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("Empty journal entry")
+}
+
+// apply applies a journal entry to the in-memory index.
+func (self *Index) apply(entry *journalEntry) error {
+	err := self.check(entry)
+	if err != nil {
+		return err
+	}
+
+	if entry.Add != nil {
+		block := *entry.Add
+		block.first = len(self.records)
+		self.blocks = append(self.blocks, block)
+		for _, record := range entry.Transactions {
+			position := len(self.records)
+			self.records = append(self.records, record)
+			self.transactions[record.TransactionId] = position
+
+			self.families[record.FamilyName] = append(self.families[record.FamilyName], position)
+			self.signers[record.SignerPublicKey] = append(self.signers[record.SignerPublicKey], position)
+			for _, address := range recordAddresses(record) {
+				self.addresses[address] = append(self.addresses[address], position)
+			}
+		}
+
+		return nil
+	}
+
+	// Otherwise, the entry removes the tip
+	tip := self.blocks[len(self.blocks) - 1]
+	for position := len(self.records) - 1; position >= tip.first; position-- {
+		record := self.records[position]
+		delete(self.transactions, record.TransactionId)
+
+		// The removed records are the last of each of their postings
+		truncatePosting(self.families, record.FamilyName)
+		truncatePosting(self.signers, record.SignerPublicKey)
+		for _, address := range recordAddresses(record) {
+			truncatePosting(self.addresses, address)
+		}
+	}
+	self.records = self.records[:tip.first]
+	self.blocks = self.blocks[:len(self.blocks) - 1]
+
+	return nil
+}
+
+// writeJournal appends an entry to the journal, if there is one, and syncs it. The entry must be
+// written before it is applied, so the index never holds changes the journal has lost. After a
+// failed write, the journal may hold part of the entry, so no further changes are accepted.
+func (self *Index) writeJournal(entry *journalEntry) error {
+	if self.journalErr != nil {
+		return self.journalErr
+	}
+	if self.journal == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize index journal entry: %s", err)
+	}
+
+	_, err = self.journal.Write(append(data, '\n'))
+	if err == nil {
+		err = self.journal.Sync()
+	}
+	if err != nil {
+		self.journalErr = fmt.Errorf("Failed to write index journal: %s", err)
+		return self.journalErr
+	}
+
+	return nil
+}
+
+// blockPosition returns the position in self.blocks of the block with the given number.
+func (self *Index) blockPosition(blockNum uint64) (int, bool) {
+	if len(self.blocks) == 0 || blockNum < self.blocks[0].BlockNum {
+		return 0, false
+	}
+
+	i := int(blockNum - self.blocks[0].BlockNum)
+	if i >= len(self.blocks) {
+		return 0, false
+	}
+
+	return i, true
+}
+
+// blockEnd returns the position in self.records following the last transaction of block i.
+func (self *Index) blockEnd(i int) int {
+	if i + 1 < len(self.blocks) {
+		return self.blocks[i + 1].first
+	}
+
+	return len(self.records)
+}
+
+// recordAddresses returns the distinct addresses (or address prefixes) a transaction declares as
+// inputs or outputs.
+func recordAddresses(record *TransactionRecord) []string {
+	addresses := make([]string, 0, len(record.Inputs) + len(record.Outputs))
+	seen := make(map[string]bool, cap(addresses))
+	for _, address := range append(append([]string{}, record.Inputs...), record.Outputs...) {
+		address = strings.ToLower(address)
+		if !seen[address] {
+			seen[address] = true
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Strings(addresses)
+
+	return addresses
+}
+
+// truncatePosting removes the last position from a posting, deleting the posting if it is empty.
+func truncatePosting(postings map[string][]int, key string) {
+	posting := postings[key]
+	if len(posting) <= 1 {
+		delete(postings, key)
+		return
+	}
+
+	postings[key] = posting[:len(posting) - 1]
+}
@@ -0,0 +1,198 @@
+package indexer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// DEFAULT_POLL_INTERVAL is the default time between checks for new blocks in Indexer.Run.
+const DEFAULT_POLL_INTERVAL = 5 * time.Second
+
+// IndexerOptions holds optional settings for an Indexer.
+type IndexerOptions struct {
+	// FirstBlockNum is the number of the first block indexed, when the index is empty.
+	FirstBlockNum	uint64
+	// Fetch is the page size used to read blocks (0 uses the transport default).
+	Fetch			int
+	// PollInterval is the time between checks for new blocks in Run.
+	PollInterval	time.Duration
+	// Progress is called (if set) after each block has been added to the index.
+	Progress		func(block *types.Block)
+	// OnError is called (if set) when Run fails to update the index, which it then retries.
+	// Otherwise, Run returns the error.
+	OnError			func(err error)
+}
+
+// SyncResult describes the changes made to the index by Indexer.Sync.
+type SyncResult struct {
+	// Added and Removed are the numbers of blocks added to and removed from the index.
+	Added			int
+	Removed			int
+	// Head is the id of the chain head the index was brought up to.
+	Head			string
+}
+
+// Indexer keeps an Index up to date with the chain read through a transport.
+type Indexer struct {
+	transport		transport.SawtoothClientTransport
+	index			*Index
+	options			IndexerOptions
+}
+
+// NewIndexer returns an Indexer which updates index from clientTransport.
+func NewIndexer(clientTransport transport.SawtoothClientTransport, index *Index) *Indexer {
+	return NewIndexerWithOptions(clientTransport, index, nil)
+}
+
+// NewIndexerWithOptions returns an Indexer configured with the given options (which may be nil).
+func NewIndexerWithOptions(clientTransport transport.SawtoothClientTransport, index *Index, options *IndexerOptions) *Indexer {
+	self := &Indexer{transport: clientTransport, index: index, options: IndexerOptions{PollInterval: DEFAULT_POLL_INTERVAL}}
+	if options != nil {
+		self.options = *options
+		if self.options.PollInterval <= 0 {
+			self.options.PollInterval = DEFAULT_POLL_INTERVAL
+		}
+	}
+
+	return self
+}
+
+// Index returns the index maintained by the Indexer.
+func (self *Indexer) Index() *Index {
+	return self.index
+}
+
+// Sync brings the index up to the current chain head. Blocks which are no longer on the chain
+// (because the validator switched to another fork) are removed first, back to the last block the
+// index shares with the chain. All blocks are read from the same fork, even if the chain head
+// moves during the update.
+func (self *Indexer) Sync() (*SyncResult, error) {
+	headId, err := transport.CurrentHead(self.transport)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.SyncTo(headId)
+}
+
+// SyncTo brings the index up to the block headId, as Sync does.
+func (self *Indexer) SyncTo(headId string) (*SyncResult, error) {
+	result := &SyncResult{Head: headId}
+
+	head, err := self.transport.GetBlock(headId)
+	if err != nil {
+		return result, err
+	}
+
+	// Remove the blocks which are not ancestors of the head
+	for {
+		tip, ok := self.index.Tip()
+		if !ok {
+			break
+		}
+
+		if tip.BlockNum <= head.Header.BlockNum {
+			blockId, err := self.blockIdAt(tip.BlockNum, headId)
+			if err != nil {
+				return result, err
+			}
+			if blockId == tip.BlockId {
+				break
+			}
+		}
+
+		_, _, err = self.index.RemoveTip()
+		if err != nil {
+			return result, err
+		}
+		result.Removed++
+	}
+
+	// Add the blocks up to the head
+	firstBlockNum := self.options.FirstBlockNum
+	tip, ok := self.index.Tip()
+	if ok {
+		firstBlockNum = tip.BlockNum + 1
+	}
+	if firstBlockNum > head.Header.BlockNum {
+		return result, nil
+	}
+
+	cursor, err := types.NewBlockRangeCursor(firstBlockNum, head.Header.BlockNum, self.options.Fetch, true)
+	if err != nil {
+		return result, err
+	}
+	cursor.Head = headId
+
+	iterator := self.transport.GetBlockIteratorFromCursor(cursor)
+	defer iterator.Close()
+
+	for iterator.Next() {
+		block, err := iterator.Current()
+		if err != nil {
+			return result, err
+		}
+
+		err = self.index.AddBlock(block)
+		if err != nil {
+			return result, err
+		}
+		result.Added++
+
+		if self.options.Progress != nil {
+			self.options.Progress(block)
+		}
+	}
+
+	return result, iterator.Error()
+}
+
+// Run calls Sync every PollInterval until stop is closed (a nil stop runs forever). If OnError is
+// not set, Run returns the first error.
+func (self *Indexer) Run(stop <-chan struct{}) error {
+	for {
+		_, err := self.Sync()
+		if err != nil {
+			if self.options.OnError == nil {
+				return err
+			}
+			self.options.OnError(err)
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(self.options.PollInterval):
+		}
+	}
+}
+
+// blockIdAt returns the id of the block numbered blockNum on the chain ending at headId.
+func (self *Indexer) blockIdAt(blockNum uint64, headId string) (string, error) {
+	cursor, err := types.NewBlockRangeCursor(blockNum, blockNum, 1, true)
+	if err != nil {
+		return "", err
+	}
+	cursor.Head = headId
+
+	iterator := self.transport.GetBlockIteratorFromCursor(cursor)
+	defer iterator.Close()
+
+	if !iterator.Next() {
+		err := iterator.Error()
+		if err == nil {
+			err = fmt.Errorf("Block %d not found", blockNum)
+		}
+		return "", err
+	}
+
+	block, err := iterator.Current()
+	if err != nil {
+		return "", err
+	}
+
+	return block.HeaderSignature, nil
+}
@@ -0,0 +1,153 @@
+package indexer_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	"github.com/taekion-org/sawtooth-client-sdk-go/indexer"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/conformance"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// reversed returns a reversed copy of values.
+func reversed(values []string) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[len(values) - 1 - i] = value
+	}
+
+	return result
+}
+
+func TestIndexer(t *testing.T) {
+	fixture := conformance.NewFixture()
+	clientTransport := conformance.NewRestTransport(t, conformance.NewValidator(fixture))
+
+	// expectedRecords returns the ids of the transactions in blocks first to last, oldest first
+	expectedRecords := func(first int, last int, keep func(*transaction_pb2.Transaction) bool) []string {
+		ids := []string{}
+		for _, block := range fixture.Blocks[first:last + 1] {
+			for _, batch := range block.Batches {
+				for _, transaction := range batch.Transactions {
+					if keep == nil || keep(transaction) {
+						ids = append(ids, transaction.HeaderSignature)
+					}
+				}
+			}
+		}
+		return ids
+	}
+	checkQuery := func(description string, index *indexer.Index, query *indexer.Query, expected []string) {
+		t.Helper()
+
+		records := index.Query(query)
+		got := make([]string, len(records))
+		for i, record := range records {
+			got[i] = record.TransactionId
+		}
+		if fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Fatalf("%s: got %d transactions %v, expected %d %v", description, len(got), got, len(expected), expected)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "index.journal")
+	index, err := indexer.OpenIndex(path)
+	if err != nil {
+		t.Fatalf("OpenIndex: %s", err)
+	}
+	defer func() { index.Close() }()
+
+	result, err := indexer.NewIndexerWithOptions(clientTransport, index, &indexer.IndexerOptions{Fetch: 4}).Sync()
+	if err != nil {
+		t.Fatalf("Sync: %s", err)
+	}
+	if result.Added != conformance.FIXTURE_BLOCKS || result.Removed != 0 || result.Head != fixture.Head().HeaderSignature {
+		t.Fatalf("Sync: unexpected result %+v", result)
+	}
+
+	head := len(fixture.Blocks) - 1
+	address := conformance.FixtureAddress(3)
+	touches := func(transaction *transaction_pb2.Transaction) bool {
+		return fixture.TransactionAddress(transaction) == address
+	}
+	last := uint64(8)
+
+	checkQuery("Query(signer)", index, &indexer.Query{Signer: fixture.SignerPublicKey}, expectedRecords(0, head, nil))
+	checkQuery("Query(family)", index, &indexer.Query{FamilyName: conformance.FIXTURE_FAMILY_NAME, FamilyVersion: "1.0"}, expectedRecords(0, head, nil))
+	checkQuery("Query(family version)", index, &indexer.Query{FamilyName: conformance.FIXTURE_FAMILY_NAME, FamilyVersion: "2.0"}, []string{})
+	checkQuery("Query(address)", index, &indexer.Query{Address: address}, expectedRecords(0, head, touches))
+	checkQuery("Query(address, blocks)", index, &indexer.Query{Address: address, FirstBlockNum: 3, LastBlockNum: &last}, expectedRecords(3, 8, touches))
+	checkQuery("Query(blocks, reverse, limit)", index, &indexer.Query{FirstBlockNum: 2, LastBlockNum: &last, Reverse: true, Limit: 4}, reversed(expectedRecords(2, 8, nil))[:4])
+	checkQuery("Query(unknown signer)", index, &indexer.Query{Signer: "02" + conformance.UnknownId("nobody")[:64]}, []string{})
+
+	// Addresses match the prefixes (namespaces) declared by a transaction
+	other := indexer.NewIndex()
+	err = other.AddBlock(&types.Block{
+		HeaderSignature: conformance.UnknownId("indexed"),
+		Header: types.BlockHeader{BlockNum: 0, PreviousBlockId: transport.GENESIS_PREVIOUS_BLOCK_ID},
+		Batches: []types.Batch{{
+			HeaderSignature: conformance.UnknownId("batch"),
+			Transactions: []types.Transaction{{
+				HeaderSignature: conformance.UnknownId("transaction"),
+				Header: types.TransactionHeader{FamilyName: "other", SignerPublicKey: "other", Inputs: []string{conformance.FIXTURE_NAMESPACE}},
+			}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("AddBlock: %s", err)
+	}
+	checkQuery("Query(namespace)", other, &indexer.Query{Address: address, Signer: "other"}, []string{conformance.UnknownId("transaction")})
+	checkQuery("Query(other namespace)", other, &indexer.Query{Address: "00" + address[2:]}, []string{})
+
+	// The index is persisted, and resumes from its journal
+	index.Close()
+	index, err = indexer.OpenIndex(path)
+	if err != nil {
+		t.Fatalf("OpenIndex(reopened): %s", err)
+	}
+	checkQuery("Query(reopened)", index, &indexer.Query{Address: address}, expectedRecords(0, head, touches))
+
+	// Blocks abandoned by a fork are removed
+	abandoned := fixture.Blocks[head].Batches[0].Transactions[0].HeaderSignature
+	fixture.Fork(conformance.FIXTURE_BLOCKS - 3, 4)
+	head = len(fixture.Blocks) - 1
+
+	result, err = indexer.NewIndexer(clientTransport, index).Sync()
+	if err != nil {
+		t.Fatalf("Sync(fork): %s", err)
+	}
+	if result.Added != 4 || result.Removed != 3 {
+		t.Fatalf("Sync(fork): unexpected result %+v", result)
+	}
+	_, ok := index.GetTransaction(abandoned)
+	if ok {
+		t.Fatalf("GetTransaction(abandoned): transaction is still indexed")
+	}
+	checkQuery("Query(fork)", index, &indexer.Query{Signer: fixture.SignerPublicKey}, expectedRecords(0, head, nil))
+
+	err = index.Compact()
+	if err != nil {
+		t.Fatalf("Compact: %s", err)
+	}
+	index.Close()
+	index, err = indexer.OpenIndex(path)
+	if err != nil {
+		t.Fatalf("OpenIndex(compacted): %s", err)
+	}
+	blocks, transactions := index.Len()
+	if blocks != len(fixture.Blocks) || transactions != len(expectedRecords(0, head, nil)) {
+		t.Fatalf("OpenIndex(compacted): got %d blocks and %d transactions", blocks, transactions)
+	}
+	checkQuery("Query(compacted)", index, &indexer.Query{Address: address, Reverse: true}, reversed(expectedRecords(0, head, touches)))
+
+	// Run syncs until stopped
+	stop := make(chan struct{})
+	close(stop)
+	err = indexer.NewIndexer(clientTransport, index).Run(stop)
+	if err != nil {
+		t.Fatalf("Run: %s", err)
+	}
+}
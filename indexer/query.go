@@ -0,0 +1,184 @@
+package indexer
+
+import (
+	"sort"
+	"strings"
+)
+
+// Query selects indexed transactions. All of the fields which are set must match.
+type Query struct {
+	FamilyName		string
+	// FamilyVersion is only matched if FamilyName is also set.
+	FamilyVersion	string
+	// Signer is the public key of the transaction signer.
+	Signer			string
+	// Address matches transactions declaring it as an input or output, either in full or by one
+	// of its prefixes (a namespace).
+	Address			string
+
+	// FirstBlockNum and LastBlockNum restrict the query to a range of blocks. If LastBlockNum is
+	// nil, the range extends to the tip of the index.
+	FirstBlockNum	uint64
+	LastBlockNum	*uint64
+
+	// Limit is the maximum number of transactions returned (0 for no limit).
+	Limit			int
+	// Reverse returns the newest transactions first.
+	Reverse			bool
+}
+
+// Query returns the indexed transactions matching query, oldest first unless query.Reverse is set.
+// The records returned must not be modified.
+func (self *Index) Query(query *Query) []*TransactionRecord {
+	self.mutex.RLock()
+	defer self.mutex.RUnlock()
+
+	start, end := self.positionRange(query)
+	if start >= end {
+		return []*TransactionRecord{}
+	}
+
+	// Read the smallest of the postings selected by the query, checking each record against the
+	// rest of the query
+	var positions []int
+	scan := true
+	choose := func(posting []int) {
+		if scan || len(posting) < len(positions) {
+			positions, scan = posting, false
+		}
+	}
+	if query.FamilyName != "" {
+		choose(self.families[query.FamilyName])
+	}
+	if query.Signer != "" {
+		choose(self.signers[query.Signer])
+	}
+	if query.Address != "" {
+		choose(self.addressPositions(strings.ToLower(query.Address)))
+	}
+
+	// Restrict the postings to the block range
+	first, last := start, end
+	if !scan {
+		first = sort.SearchInts(positions, start)
+		last = sort.SearchInts(positions, end)
+	}
+
+	results := []*TransactionRecord{}
+	for i := 0; i < last - first; i++ {
+		n := first + i
+		if query.Reverse {
+			n = last - 1 - i
+		}
+
+		position := n
+		if !scan {
+			position = positions[n]
+		}
+
+		record := self.records[position]
+		if !query.matches(record) {
+			continue
+		}
+
+		results = append(results, record)
+		if query.Limit > 0 && len(results) >= query.Limit {
+			break
+		}
+	}
+
+	return results
+}
+
+// positionRange returns the positions in self.records of the transactions in the query's range of
+// blocks.
+func (self *Index) positionRange(query *Query) (int, int) {
+	if len(self.blocks) == 0 {
+		return 0, 0
+	}
+
+	tipNum := self.blocks[len(self.blocks) - 1].BlockNum
+	lastNum := tipNum
+	if query.LastBlockNum != nil && *query.LastBlockNum < tipNum {
+		lastNum = *query.LastBlockNum
+	}
+	if query.FirstBlockNum > lastNum {
+		return 0, 0
+	}
+
+	start := 0
+	i, ok := self.blockPosition(query.FirstBlockNum)
+	if ok {
+		start = self.blocks[i].first
+	}
+
+	end := 0
+	i, ok = self.blockPosition(lastNum)
+	if ok {
+		end = self.blockEnd(i)
+	}
+
+	return start, end
+}
+
+// addressPositions returns the positions of the transactions declaring address, or a prefix of
+// it, in chain order.
+func (self *Index) addressPositions(address string) []int {
+	var postings [][]int
+	for length := 1; length <= len(address); length++ {
+		posting, ok := self.addresses[address[:length]]
+		if ok {
+			postings = append(postings, posting)
+		}
+	}
+	if len(postings) == 1 {
+		return postings[0]
+	}
+
+	// A transaction may declare several of the prefixes
+	seen := make(map[int]bool)
+	positions := []int{}
+	for _, posting := range postings {
+		for _, position := range posting {
+			if !seen[position] {
+				seen[position] = true
+				positions = append(positions, position)
+			}
+		}
+	}
+	sort.Ints(positions)
+
+	return positions
+}
+
+// matches returns true if record matches every field of the query other than the block range.
+func (self *Query) matches(record *TransactionRecord) bool {
+	if self.FamilyName != "" {
+		if record.FamilyName != self.FamilyName {
+			return false
+		}
+		if self.FamilyVersion != "" && record.FamilyVersion != self.FamilyVersion {
+			return false
+		}
+	}
+
+	if self.Signer != "" && record.SignerPublicKey != self.Signer {
+		return false
+	}
+
+	if self.Address != "" {
+		address := strings.ToLower(self.Address)
+		found := false
+		for _, declared := range recordAddresses(record) {
+			if declared != "" && strings.HasPrefix(address, declared) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,207 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/sawtooth-sdk-go/signing"
+	"golang.org/x/crypto/scrypt"
+)
+
+// ENCRYPTED_KEY_VERSION is the version of the encrypted key file format.
+const ENCRYPTED_KEY_VERSION = 1
+
+// DEFAULT_SCRYPT_N, DEFAULT_SCRYPT_R and DEFAULT_SCRYPT_P are the default scrypt parameters used
+// to derive encryption keys from passphrases.
+const DEFAULT_SCRYPT_N = 1 << 15
+const DEFAULT_SCRYPT_R = 8
+const DEFAULT_SCRYPT_P = 1
+
+// MAX_SCRYPT_N is the largest scrypt cost accepted when decrypting a key file.
+const MAX_SCRYPT_N = 1 << 22
+
+// MAX_SCRYPT_MEMORY is the largest amount of memory (128 * r * (N + p) bytes) scrypt may need
+// when decrypting a key file. It allows MAX_SCRYPT_N with the default r and p.
+const MAX_SCRYPT_MEMORY int64 = 128 * DEFAULT_SCRYPT_R * (MAX_SCRYPT_N + DEFAULT_SCRYPT_P)
+
+const encryptedKeyKdf = "scrypt"
+const encryptedKeyCipher = "aes-256-gcm"
+const scryptSaltLength = 32
+const scryptKeyLength = 32
+
+// ErrPassphraseRequired is returned when an encrypted key is loaded without a passphrase.
+var ErrPassphraseRequired = fmt.Errorf("Private key is encrypted, a passphrase is required")
+
+// ErrIncorrectPassphrase is returned when an encrypted key cannot be decrypted with the passphrase
+// given (or has been tampered with).
+var ErrIncorrectPassphrase = fmt.Errorf("Incorrect passphrase for private key")
+
+// ScryptParams holds the scrypt parameters used to derive an encryption key from a passphrase.
+type ScryptParams struct {
+	N		int		`json:"n"`
+	R		int		`json:"r"`
+	P		int		`json:"p"`
+	Salt	string	`json:"salt,omitempty"`
+}
+
+// encryptedKey is the content of an encrypted key file. The public key is authenticated (as
+// additional data), so it can be read without the passphrase but not modified.
+type encryptedKey struct {
+	Version		int				`json:"version"`
+	PublicKey	string			`json:"public_key"`
+	Kdf			string			`json:"kdf"`
+	KdfParams	ScryptParams	`json:"kdf_params"`
+	Cipher		string			`json:"cipher"`
+	Nonce		string			`json:"nonce"`
+	Ciphertext	string			`json:"ciphertext"`
+}
+
+// EncryptKey encrypts a private key with a passphrase, using the default scrypt parameters.
+func EncryptKey(privateKey signing.PrivateKey, passphrase []byte) ([]byte, error) {
+	return EncryptKeyWithParams(privateKey, passphrase, nil)
+}
+
+// EncryptKeyWithParams encrypts a private key with a passphrase, using the given scrypt
+// parameters (which may be nil; the salt is always random).
+func EncryptKeyWithParams(privateKey signing.PrivateKey, passphrase []byte, params *ScryptParams) ([]byte, error) {
+	err := ValidatePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("Passphrase must not be empty")
+	}
+
+	kdfParams := ScryptParams{N: DEFAULT_SCRYPT_N, R: DEFAULT_SCRYPT_R, P: DEFAULT_SCRYPT_P}
+	if params != nil {
+		kdfParams = ScryptParams{N: params.N, R: params.R, P: params.P}
+	}
+
+	salt := make([]byte, scryptSaltLength)
+	_, err = rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+	kdfParams.Salt = hex.EncodeToString(salt)
+
+	encrypted := &encryptedKey{
+		Version: ENCRYPTED_KEY_VERSION,
+		PublicKey: PublicKey(privateKey),
+		Kdf: encryptedKeyKdf,
+		KdfParams: kdfParams,
+		Cipher: encryptedKeyCipher,
+	}
+
+	aead, err := encrypted.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	encrypted.Nonce = hex.EncodeToString(nonce)
+	encrypted.Ciphertext = hex.EncodeToString(aead.Seal(nil, nonce, privateKey.AsBytes(), []byte(encrypted.PublicKey)))
+
+	return json.MarshalIndent(encrypted, "", "  ")
+}
+
+// DecryptKey decrypts a private key encrypted by EncryptKey.
+func DecryptKey(data []byte, passphrase []byte) (signing.PrivateKey, error) {
+	encrypted, err := parseEncryptedKey(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(passphrase) == 0 {
+		return nil, ErrPassphraseRequired
+	}
+
+	aead, err := encrypted.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := hex.DecodeString(encrypted.Nonce)
+	if err != nil || len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("Invalid encrypted key: malformed nonce")
+	}
+	ciphertext, err := hex.DecodeString(encrypted.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid encrypted key: malformed ciphertext")
+	}
+
+	keyBytes, err := aead.Open(nil, nonce, ciphertext, []byte(encrypted.PublicKey))
+	if err != nil {
+		return nil, ErrIncorrectPassphrase
+	}
+
+	privateKey, err := NewPrivateKey(keyBytes)
+	if err != nil {
+		return nil, err
+	}
+	if PublicKey(privateKey) != encrypted.PublicKey {
+		return nil, fmt.Errorf("Invalid encrypted key: public key does not match the private key")
+	}
+
+	return privateKey, nil
+}
+
+// IsEncryptedKey returns true if data is an encrypted key (rather than a plaintext hex key).
+func IsEncryptedKey(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+// parseEncryptedKey decodes and checks the format of an encrypted key.
+func parseEncryptedKey(data []byte) (*encryptedKey, error) {
+	var encrypted encryptedKey
+	err := json.Unmarshal(data, &encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid encrypted key: %s", err)
+	}
+
+	if encrypted.Version != ENCRYPTED_KEY_VERSION {
+		return nil, fmt.Errorf("Unsupported encrypted key version: %d", encrypted.Version)
+	}
+	if encrypted.Kdf != encryptedKeyKdf || encrypted.Cipher != encryptedKeyCipher {
+		return nil, fmt.Errorf("Unsupported encrypted key algorithms: %s, %s", encrypted.Kdf, encrypted.Cipher)
+	}
+
+	params := encrypted.KdfParams
+	if params.N < 2 || params.N > MAX_SCRYPT_N || params.N & (params.N - 1) != 0 || params.R < 1 || params.P < 1 || params.R * params.P >= 1 << 30 {
+		return nil, fmt.Errorf("Invalid encrypted key: unsupported scrypt parameters")
+	}
+	if int64(params.R) > MAX_SCRYPT_MEMORY / (128 * (int64(params.N) + int64(params.P))) {
+		return nil, fmt.Errorf("Invalid encrypted key: scrypt parameters need too much memory")
+	}
+
+	encrypted.PublicKey = strings.ToLower(encrypted.PublicKey)
+	return &encrypted, nil
+}
+
+// aead returns the cipher for a passphrase.
+func (self *encryptedKey) aead(passphrase []byte) (cipher.AEAD, error) {
+	salt, err := hex.DecodeString(self.KdfParams.Salt)
+	if err != nil || len(salt) == 0 {
+		return nil, fmt.Errorf("Invalid encrypted key: malformed salt")
+	}
+
+	key, err := scrypt.Key(passphrase, salt, self.KdfParams.N, self.KdfParams.R, self.KdfParams.P, scryptKeyLength)
+	if err != nil {
+		return nil, fmt.Errorf("Error deriving encryption key: %s", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,81 @@
+// Package keystore loads, stores and generates the secp256k1 private keys used to sign Sawtooth
+// transactions and batches.
+//
+// Key files are either plaintext, as written by sawtooth keygen (a hex encoded private key in
+// <name>.priv, and its public key in <name>.pub), or encrypted with a passphrase (a JSON document
+// holding the private key encrypted with AES-256-GCM, under a key derived with scrypt). Every key
+// loaded is checked to be a valid secp256k1 private key.
+package keystore
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/hyperledger/sawtooth-sdk-go/signing"
+)
+
+// PRIVATE_KEY_LENGTH is the length of a secp256k1 private key, in bytes.
+const PRIVATE_KEY_LENGTH = 32
+
+// GenerateKey returns a new random secp256k1 private key.
+func GenerateKey() (signing.PrivateKey, error) {
+	privateKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("Error generating private key: %s", err)
+	}
+
+	return NewPrivateKey(privateKey.D.FillBytes(make([]byte, PRIVATE_KEY_LENGTH)))
+}
+
+// NewPrivateKey returns the secp256k1 private key with the given bytes, checking it is valid.
+func NewPrivateKey(keyBytes []byte) (signing.PrivateKey, error) {
+	privateKey := signing.NewSecp256k1PrivateKey(append([]byte(nil), keyBytes...))
+	err := ValidatePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return privateKey, nil
+}
+
+// ParsePrivateKey returns the secp256k1 private key encoded in hex (as written by sawtooth
+// keygen), checking it is valid. Surrounding whitespace is ignored.
+func ParsePrivateKey(hexKey string) (signing.PrivateKey, error) {
+	keyBytes, err := hex.DecodeString(strings.TrimSpace(hexKey))
+	if err != nil {
+		return nil, fmt.Errorf("Private key is not hex encoded")
+	}
+
+	return NewPrivateKey(keyBytes)
+}
+
+// ValidatePrivateKey returns an error if privateKey is not a valid secp256k1 private key: 32
+// bytes, encoding a number between 1 and the order of the curve.
+func ValidatePrivateKey(privateKey signing.PrivateKey) error {
+	if privateKey == nil {
+		return fmt.Errorf("Private key is missing")
+	}
+	if privateKey.GetAlgorithmName() != "secp256k1" {
+		return fmt.Errorf("Private key algorithm is %s, expected secp256k1", privateKey.GetAlgorithmName())
+	}
+
+	keyBytes := privateKey.AsBytes()
+	if len(keyBytes) != PRIVATE_KEY_LENGTH {
+		return fmt.Errorf("Private key is %d bytes long, expected %d", len(keyBytes), PRIVATE_KEY_LENGTH)
+	}
+
+	d := new(big.Int).SetBytes(keyBytes)
+	if d.Sign() == 0 || d.Cmp(btcec.S256().N) >= 0 {
+		return fmt.Errorf("Private key is out of range for secp256k1")
+	}
+
+	return nil
+}
+
+// PublicKey returns the hex encoded (compressed) public key of a secp256k1 private key.
+func PublicKey(privateKey signing.PrivateKey) string {
+	return signing.NewSecp256k1Context().GetPublicKey(privateKey).AsHex()
+}
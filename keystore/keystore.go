@@ -0,0 +1,375 @@
+package keystore
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/sawtooth-sdk-go/signing"
+)
+
+// PRIVATE_KEY_EXTENSION and PUBLIC_KEY_EXTENSION are the extensions of key files, as used by
+// sawtooth keygen.
+const PRIVATE_KEY_EXTENSION = ".priv"
+const PUBLIC_KEY_EXTENSION = ".pub"
+
+// PRIVATE_KEY_VARIABLE is the environment variable read by LoadDefaultKey, holding a private key
+// (plaintext hex, or encrypted).
+const PRIVATE_KEY_VARIABLE = "SAWTOOTH_PRIVATE_KEY"
+
+// KEY_FILE_VARIABLE is the environment variable read by LoadDefaultKey, holding the path of a key
+// file.
+const KEY_FILE_VARIABLE = "SAWTOOTH_KEY_FILE"
+
+// HOME_VARIABLE is the environment variable overriding the Sawtooth home directory, whose keys
+// directory is the default keystore.
+const HOME_VARIABLE = "SAWTOOTH_HOME"
+
+// ReadKey reads a private key (plaintext hex or encrypted) from a reader. The passphrase is only
+// used if the key is encrypted.
+func ReadKey(reader io.Reader, passphrase []byte) (signing.PrivateKey, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read private key: %s", err)
+	}
+
+	return DecodeKey(data, passphrase)
+}
+
+// DecodeKey decodes a private key, either plaintext hex or encrypted. The passphrase is only used
+// if the key is encrypted.
+func DecodeKey(data []byte, passphrase []byte) (signing.PrivateKey, error) {
+	if IsEncryptedKey(data) {
+		return DecryptKey(data, passphrase)
+	}
+
+	return ParsePrivateKey(string(data))
+}
+
+// ReadKeyFile reads a private key (plaintext hex or encrypted) from a file.
+func ReadKeyFile(path string, passphrase []byte) (signing.PrivateKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read private key from file (%s) with error: %s", path, err)
+	}
+
+	privateKey, err := DecodeKey(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("Could not load private key from file (%s): %w", path, err)
+	}
+
+	return privateKey, nil
+}
+
+// ReadKeyFromFd reads a private key (plaintext hex or encrypted) from an open file descriptor
+// (for example, one passed by a parent process), and closes it.
+func ReadKeyFromFd(fd uintptr, passphrase []byte) (signing.PrivateKey, error) {
+	file := os.NewFile(fd, fmt.Sprintf("fd%d", fd))
+	if file == nil {
+		return nil, fmt.Errorf("Invalid file descriptor: %d", fd)
+	}
+	defer file.Close()
+
+	return ReadKey(file, passphrase)
+}
+
+// LoadKeyFromEnv loads a private key (plaintext hex or encrypted) from an environment variable.
+func LoadKeyFromEnv(variable string, passphrase []byte) (signing.PrivateKey, error) {
+	value, ok := os.LookupEnv(variable)
+	if !ok {
+		return nil, fmt.Errorf("Environment variable %s is not set", variable)
+	}
+
+	privateKey, err := DecodeKey([]byte(value), passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("Could not load private key from %s: %w", variable, err)
+	}
+
+	return privateKey, nil
+}
+
+// WriteKeyFile writes a private key to path, encrypted if a passphrase is given and otherwise in
+// the plaintext format of sawtooth keygen. The public key is written next to it, with the
+// extension .pub (replacing .priv). Existing files are not overwritten.
+func WriteKeyFile(path string, privateKey signing.PrivateKey, passphrase []byte) error {
+	return writeKeyFile(path, privateKey, passphrase, nil)
+}
+
+// writeKeyFile implements WriteKeyFile, with the given scrypt parameters for encryption.
+func writeKeyFile(path string, privateKey signing.PrivateKey, passphrase []byte, params *ScryptParams) error {
+	err := ValidatePrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+
+	data := []byte(privateKey.AsHex() + "\n")
+	if len(passphrase) > 0 {
+		data, err = EncryptKeyWithParams(privateKey, passphrase, params)
+		if err != nil {
+			return err
+		}
+	}
+
+	publicKeyPath := strings.TrimSuffix(path, PRIVATE_KEY_EXTENSION) + PUBLIC_KEY_EXTENSION
+	for _, existing := range []string{path, publicKeyPath} {
+		_, err = os.Stat(existing)
+		if err == nil {
+			return fmt.Errorf("Key file %s already exists", existing)
+		}
+	}
+
+	err = writeNewFile(path, data, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = writeNewFile(publicKeyPath, []byte(PublicKey(privateKey) + "\n"), 0644)
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	return nil
+}
+
+// writeNewFile writes data to a file which must not already exist.
+func writeNewFile(path string, data []byte, mode os.FileMode) error {
+	file, err := os.OpenFile(path, os.O_WRONLY | os.O_CREATE | os.O_EXCL, mode)
+	if err != nil {
+		return fmt.Errorf("Could not create key file: %s", err)
+	}
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("Could not write key file (%s): %s", path, err)
+	}
+
+	return nil
+}
+
+// KeyInfo describes a key in a Keystore.
+type KeyInfo struct {
+	Name		string
+	PublicKey	string
+	Encrypted	bool
+	// Path is the path of the private key file.
+	Path		string
+}
+
+// Keystore is a directory of key files named <name>.priv, as used by sawtooth keygen.
+type Keystore struct {
+	// Dir is the directory holding the key files.
+	Dir				string
+	// ScryptParams overrides the default scrypt parameters used to encrypt new keys.
+	ScryptParams	*ScryptParams
+}
+
+// NewKeystore returns a Keystore for the key files in dir.
+func NewKeystore(dir string) *Keystore {
+	return &Keystore{Dir: dir}
+}
+
+// DefaultKeystore returns the Keystore used by the Sawtooth tools: $SAWTOOTH_HOME/keys if
+// SAWTOOTH_HOME is set, and otherwise ~/.sawtooth/keys.
+func DefaultKeystore() (*Keystore, error) {
+	home := os.Getenv(HOME_VARIABLE)
+	if home != "" {
+		return NewKeystore(filepath.Join(home, "keys")), nil
+	}
+
+	currentUser, err := user.Current()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeystore(filepath.Join(currentUser.HomeDir, ".sawtooth", "keys")), nil
+}
+
+// DefaultKeyName returns the name of the default key: the name of the current user.
+func DefaultKeyName() (string, error) {
+	currentUser, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+
+	return currentUser.Username, nil
+}
+
+// LoadDefaultKey loads the default private key from, in order: the environment variable
+// SAWTOOTH_PRIVATE_KEY, the file named by SAWTOOTH_KEY_FILE, or the key named after the current
+// user in the DefaultKeystore.
+func LoadDefaultKey(passphrase []byte) (signing.PrivateKey, error) {
+	_, ok := os.LookupEnv(PRIVATE_KEY_VARIABLE)
+	if ok {
+		return LoadKeyFromEnv(PRIVATE_KEY_VARIABLE, passphrase)
+	}
+
+	path := os.Getenv(KEY_FILE_VARIABLE)
+	if path != "" {
+		return ReadKeyFile(path, passphrase)
+	}
+
+	keystore, err := DefaultKeystore()
+	if err != nil {
+		return nil, err
+	}
+	name, err := DefaultKeyName()
+	if err != nil {
+		return nil, err
+	}
+
+	return keystore.Load(name, passphrase)
+}
+
+// Path returns the path of the private key file of the named key.
+func (self *Keystore) Path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("Invalid key name: %s", name)
+	}
+
+	return filepath.Join(self.Dir, name + PRIVATE_KEY_EXTENSION), nil
+}
+
+// Load loads the named private key. The passphrase is only used if the key is encrypted.
+func (self *Keystore) Load(name string, passphrase []byte) (signing.PrivateKey, error) {
+	path, err := self.Path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadKeyFile(path, passphrase)
+}
+
+// Generate generates and stores a new private key, encrypted if a passphrase is given.
+func (self *Keystore) Generate(name string, passphrase []byte) (*KeyInfo, error) {
+	privateKey, err := GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return self.Import(name, privateKey, passphrase)
+}
+
+// Import stores a private key, encrypted if a passphrase is given. An existing key of the same
+// name is not overwritten.
+func (self *Keystore) Import(name string, privateKey signing.PrivateKey, passphrase []byte) (*KeyInfo, error) {
+	path, err := self.Path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(self.Dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("Could not create keystore directory: %s", err)
+	}
+
+	err = writeKeyFile(path, privateKey, passphrase, self.ScryptParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyInfo{Name: name, PublicKey: PublicKey(privateKey), Encrypted: len(passphrase) > 0, Path: path}, nil
+}
+
+// Export writes the named key to path in the plaintext format of sawtooth keygen (decrypting it
+// with passphrase if it is encrypted), or encrypted with exportPassphrase if that is given.
+func (self *Keystore) Export(name string, passphrase []byte, path string, exportPassphrase []byte) error {
+	privateKey, err := self.Load(name, passphrase)
+	if err != nil {
+		return err
+	}
+
+	return writeKeyFile(path, privateKey, exportPassphrase, self.ScryptParams)
+}
+
+// Remove deletes the key files of the named key.
+func (self *Keystore) Remove(name string) error {
+	path, err := self.Path(name)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil {
+		return fmt.Errorf("Could not remove key %s: %s", name, err)
+	}
+
+	err = os.Remove(strings.TrimSuffix(path, PRIVATE_KEY_EXTENSION) + PUBLIC_KEY_EXTENSION)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Could not remove public key of %s: %s", name, err)
+	}
+
+	return nil
+}
+
+// List describes the keys in the keystore, ordered by name, without decrypting them. A missing
+// directory is an empty keystore.
+func (self *Keystore) List() ([]*KeyInfo, error) {
+	entries, err := ioutil.ReadDir(self.Dir)
+	if os.IsNotExist(err) {
+		return []*KeyInfo{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Could not read keystore directory: %s", err)
+	}
+
+	keys := []*KeyInfo{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), PRIVATE_KEY_EXTENSION) {
+			continue
+		}
+
+		name := strings.TrimSuffix(entry.Name(), PRIVATE_KEY_EXTENSION)
+		info, err := self.describe(name)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, info)
+	}
+	sort.Slice(keys, func(i int, j int) bool { return keys[i].Name < keys[j].Name })
+
+	return keys, nil
+}
+
+// describe reads the public key of the named key, and whether it is encrypted.
+func (self *Keystore) describe(name string) (*KeyInfo, error) {
+	path, err := self.Path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read key %s: %s", name, err)
+	}
+
+	info := &KeyInfo{Name: name, Path: path, Encrypted: IsEncryptedKey(data)}
+	if info.Encrypted {
+		encrypted, err := parseEncryptedKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid key %s: %s", name, err)
+		}
+		info.PublicKey = encrypted.PublicKey
+	} else {
+		privateKey, err := ParsePrivateKey(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid key %s: %s", name, err)
+		}
+		info.PublicKey = PublicKey(privateKey)
+	}
+
+	return info, nil
+}
@@ -0,0 +1,211 @@
+package keystore_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/hyperledger/sawtooth-sdk-go/signing"
+	"github.com/taekion-org/sawtooth-client-sdk-go/keystore"
+)
+
+func TestKeystore(t *testing.T) {
+	// Keys written by sawtooth keygen are hex encoded
+	seed := sha256.Sum256([]byte("signer"))
+	seedKey, err := keystore.ParsePrivateKey(" " + hex.EncodeToString(seed[:]) + "\n")
+	if err != nil {
+		t.Fatalf("ParsePrivateKey: %s", err)
+	}
+	seedPublicKey := signing.NewSecp256k1Context().GetPublicKey(signing.NewSecp256k1PrivateKey(seed[:])).AsHex()
+	if keystore.PublicKey(seedKey) != seedPublicKey {
+		t.Fatalf("PublicKey: got %s, expected %s", keystore.PublicKey(seedKey), seedPublicKey)
+	}
+
+	generated, err := keystore.GenerateKey()
+	if err != nil || keystore.ValidatePrivateKey(generated) != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	invalidKeys := []string{"not hex", "0102", strings.Repeat("00", 32), strings.Repeat("ff", 32), strings.Repeat("01", 33)}
+	for _, invalid := range invalidKeys {
+		_, err = keystore.ParsePrivateKey(invalid)
+		if err == nil {
+			t.Fatalf("ParsePrivateKey(%s): expected an error", invalid)
+		}
+	}
+	if keystore.ValidatePrivateKey(nil) == nil || keystore.ValidatePrivateKey(signing.NewSecp256k1PrivateKey([]byte(hex.EncodeToString(seed[:])))) == nil {
+		t.Fatalf("ValidatePrivateKey: expected an error")
+	}
+
+	// A keystore holding a plaintext and an encrypted key
+	passphrase := []byte("correct horse")
+	store := keystore.NewKeystore(filepath.Join(t.TempDir(), "keys"))
+	store.ScryptParams = &keystore.ScryptParams{N: 1 << 10, R: 8, P: 1}
+
+	_, err = store.Import("alice", seedKey, nil)
+	if err != nil {
+		t.Fatalf("Import: %s", err)
+	}
+	bob, err := store.Generate("bob", passphrase)
+	if err != nil {
+		t.Fatalf("Generate: %s", err)
+	}
+
+	keys, err := store.List()
+	if err != nil {
+		t.Fatalf("List: %s", err)
+	}
+	if len(keys) != 2 || keys[0].Name != "alice" || keys[0].Encrypted || keys[0].PublicKey != seedPublicKey || keys[1].Name != "bob" || !keys[1].Encrypted || keys[1].PublicKey != bob.PublicKey {
+		t.Fatalf("List: unexpected keys %+v %+v", keys[0], keys[len(keys) - 1])
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(store.Dir, "alice.priv"))
+	if err != nil || strings.TrimSpace(string(data)) != hex.EncodeToString(seed[:]) {
+		t.Fatalf("Import: unexpected private key file %q (%v)", data, err)
+	}
+	data, err = ioutil.ReadFile(filepath.Join(store.Dir, "bob.pub"))
+	if err != nil || strings.TrimSpace(string(data)) != bob.PublicKey {
+		t.Fatalf("Generate: unexpected public key file %q (%v)", data, err)
+	}
+
+	// checkLoad checks a loaded key has the expected public key
+	checkLoad := func(description string, privateKey signing.PrivateKey, err error, publicKey string) {
+		t.Helper()
+
+		if err != nil {
+			t.Fatalf("%s: %s", description, err)
+		}
+		if keystore.PublicKey(privateKey) != publicKey {
+			t.Fatalf("%s: got public key %s, expected %s", description, keystore.PublicKey(privateKey), publicKey)
+		}
+	}
+
+	privateKey, err := store.Load("alice", nil)
+	checkLoad("Load(plaintext)", privateKey, err, seedPublicKey)
+	privateKey, err = store.Load("bob", passphrase)
+	checkLoad("Load(encrypted)", privateKey, err, bob.PublicKey)
+
+	_, err = store.Load("bob", nil)
+	if !errors.Is(err, keystore.ErrPassphraseRequired) {
+		t.Fatalf("Load(no passphrase): got %v", err)
+	}
+	_, err = store.Load("bob", []byte("wrong"))
+	if !errors.Is(err, keystore.ErrIncorrectPassphrase) {
+		t.Fatalf("Load(wrong passphrase): got %v", err)
+	}
+	for _, name := range []string{"", "../alice", ".hidden", "missing"} {
+		_, err = store.Load(name, nil)
+		if err == nil {
+			t.Fatalf("Load(%s): expected an error", name)
+		}
+	}
+	_, err = store.Import("alice", generated, nil)
+	if err == nil {
+		t.Fatalf("Import(existing): expected an error")
+	}
+
+	// Tampering with an encrypted key is detected
+	data, err = ioutil.ReadFile(bob.Path)
+	if err != nil {
+		t.Fatalf("ReadFile: %s", err)
+	}
+	_, err = keystore.DecryptKey([]byte(strings.Replace(string(data), bob.PublicKey, seedPublicKey, 1)), passphrase)
+	if err == nil {
+		t.Fatalf("DecryptKey(tampered): expected an error")
+	}
+
+	// Scrypt parameters needing too much memory are rejected before deriving the key
+	for _, params := range []map[string]int{{"r": 1 << 20, "p": 1}, {"r": 8, "p": 1 << 26}} {
+		var encrypted map[string]interface{}
+		json.Unmarshal(data, &encrypted)
+		kdfParams := encrypted["kdf_params"].(map[string]interface{})
+		kdfParams["r"], kdfParams["p"] = params["r"], params["p"]
+		tampered, _ := json.Marshal(encrypted)
+
+		_, err = keystore.DecryptKey(tampered, passphrase)
+		if err == nil || !strings.Contains(err.Error(), "too much memory") {
+			t.Fatalf("DecryptKey(%v): expected an error, got: %v", params, err)
+		}
+	}
+
+	// Exported keys can be read by sawtooth keygen compatible tools, or stay encrypted
+	exportDir := t.TempDir()
+	err = store.Export("bob", passphrase, filepath.Join(exportDir, "bob.priv"), nil)
+	if err != nil {
+		t.Fatalf("Export: %s", err)
+	}
+	data, err = ioutil.ReadFile(filepath.Join(exportDir, "bob.priv"))
+	if err != nil {
+		t.Fatalf("ReadFile: %s", err)
+	}
+	privateKey, err = keystore.ParsePrivateKey(string(data))
+	checkLoad("Export(plaintext)", privateKey, err, bob.PublicKey)
+
+	err = store.Export("bob", passphrase, filepath.Join(exportDir, "encrypted.priv"), []byte("other"))
+	if err != nil {
+		t.Fatalf("Export(encrypted): %s", err)
+	}
+	privateKey, err = keystore.ReadKeyFile(filepath.Join(exportDir, "encrypted.priv"), []byte("other"))
+	checkLoad("Export(encrypted)", privateKey, err, bob.PublicKey)
+
+	// Keys can be loaded from environment variables and file descriptors
+	t.Setenv("TEST_PRIVATE_KEY", string(data))
+	privateKey, err = keystore.LoadKeyFromEnv("TEST_PRIVATE_KEY", nil)
+	checkLoad("LoadKeyFromEnv", privateKey, err, bob.PublicKey)
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Pipe: %s", err)
+	}
+	defer reader.Close()
+	writer.Write(data)
+	writer.Close()
+	fd, err := syscall.Dup(int(reader.Fd()))
+	if err != nil {
+		t.Fatalf("Dup: %s", err)
+	}
+	privateKey, err = keystore.ReadKeyFromFd(uintptr(fd), nil)
+	checkLoad("ReadKeyFromFd", privateKey, err, bob.PublicKey)
+
+	// The default key is read from the environment, a key file or the default keystore
+	home := t.TempDir()
+	t.Setenv(keystore.HOME_VARIABLE, home)
+	defaultStore, err := keystore.DefaultKeystore()
+	if err != nil || defaultStore.Dir != filepath.Join(home, "keys") {
+		t.Fatalf("DefaultKeystore: got %+v (%v)", defaultStore, err)
+	}
+	name, err := keystore.DefaultKeyName()
+	if err != nil {
+		t.Fatalf("DefaultKeyName: %s", err)
+	}
+	_, err = defaultStore.Import(name, seedKey, passphrase)
+	if err != nil {
+		t.Fatalf("Import(default): %s", err)
+	}
+	privateKey, err = keystore.LoadDefaultKey(passphrase)
+	checkLoad("LoadDefaultKey(keystore)", privateKey, err, seedPublicKey)
+
+	t.Setenv(keystore.KEY_FILE_VARIABLE, bob.Path)
+	privateKey, err = keystore.LoadDefaultKey(passphrase)
+	checkLoad("LoadDefaultKey(file)", privateKey, err, bob.PublicKey)
+
+	t.Setenv(keystore.PRIVATE_KEY_VARIABLE, hex.EncodeToString(seed[:]))
+	privateKey, err = keystore.LoadDefaultKey(nil)
+	checkLoad("LoadDefaultKey(environment)", privateKey, err, seedPublicKey)
+
+	err = store.Remove("bob")
+	if err != nil {
+		t.Fatalf("Remove: %s", err)
+	}
+	keys, err = store.List()
+	if err != nil || len(keys) != 1 {
+		t.Fatalf("List(removed): got %d keys (%v)", len(keys), err)
+	}
+}
@@ -0,0 +1,66 @@
+package merkle
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+// CBOR major types used by node encoding.
+const (
+	cborBytes	byte	= 0x40
+	cborText	byte	= 0x60
+	cborMap		byte	= 0xa0
+	cborNull	byte	= 0xf6
+)
+
+// encodeNode encodes a node in the same way as the validator: a CBOR map {"c": children, "v": value}
+// with keys in sorted order, where children maps address tokens to child hashes and value is
+// null for nodes without a value.
+func encodeNode(value []byte, hasValue bool, children map[string]string) []byte {
+	buffer := appendHeader(nil, cborMap, 2)
+
+	buffer = appendText(buffer, "c")
+	tokens := make([]string, 0, len(children))
+	for token := range children {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+
+	buffer = appendHeader(buffer, cborMap, uint64(len(tokens)))
+	for _, token := range tokens {
+		buffer = appendText(buffer, token)
+		buffer = appendText(buffer, children[token])
+	}
+
+	buffer = appendText(buffer, "v")
+	if hasValue {
+		buffer = appendHeader(buffer, cborBytes, uint64(len(value)))
+		buffer = append(buffer, value...)
+	} else {
+		buffer = append(buffer, cborNull)
+	}
+
+	return buffer
+}
+
+// appendText appends a CBOR text string.
+func appendText(buffer []byte, text string) []byte {
+	buffer = appendHeader(buffer, cborText, uint64(len(text)))
+	return append(buffer, text...)
+}
+
+// appendHeader appends a CBOR item header using the shortest encoding of length.
+func appendHeader(buffer []byte, majorType byte, length uint64) []byte {
+	switch {
+	case length < 24:
+		return append(buffer, majorType | byte(length))
+	case length <= 0xff:
+		return append(buffer, majorType | 24, byte(length))
+	case length <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(buffer, majorType | 25), uint16(length))
+	case length <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(buffer, majorType | 26), uint32(length))
+	default:
+		return binary.BigEndian.AppendUint64(append(buffer, majorType | 27), length)
+	}
+}
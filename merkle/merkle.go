@@ -0,0 +1,212 @@
+// Package merkle computes Sawtooth state root hashes offline, using the same Merkle-Radix tree as
+// the validator: nodes are CBOR encoded, hashed with SHA-512 (truncated to 64 hex characters),
+// and addressed by the 35 two-character tokens of a 70 character state address.
+package merkle
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// TOKEN_SIZE is the number of address characters consumed by each level of the tree.
+const TOKEN_SIZE = 2
+
+// HASH_LENGTH is the length of a node hash in hex characters.
+const HASH_LENGTH = 64
+
+// Tree is an in-memory Merkle-Radix tree. The zero value is not usable; use NewTree.
+type Tree struct {
+	root	*node
+}
+
+// node is a node of the tree. Hashes are cached until the node (or a descendant) changes.
+type node struct {
+	value		[]byte
+	hasValue	bool
+	children	map[string]*node
+
+	hash		string
+}
+
+// NewTree returns an empty tree.
+func NewTree() *Tree {
+	return &Tree{root: newNode()}
+}
+
+// newNode returns a node with no value and no children.
+func newNode() *node {
+	return &node{children: make(map[string]*node)}
+}
+
+// Set sets the value at an address.
+func (self *Tree) Set(address string, value []byte) error {
+	tokens, err := tokenizeAddress(address)
+	if err != nil {
+		return err
+	}
+
+	current := self.root
+	current.hash = ""
+	for _, token := range tokens {
+		child, ok := current.children[token]
+		if !ok {
+			child = newNode()
+			current.children[token] = child
+		}
+		child.hash = ""
+		current = child
+	}
+
+	current.value = append([]byte{}, value...)
+	current.hasValue = true
+
+	return nil
+}
+
+// Get returns the value at an address, and false if the address is not set.
+func (self *Tree) Get(address string) ([]byte, bool, error) {
+	tokens, err := tokenizeAddress(address)
+	if err != nil {
+		return nil, false, err
+	}
+
+	current := self.root
+	for _, token := range tokens {
+		current = current.children[token]
+		if current == nil {
+			return nil, false, nil
+		}
+	}
+
+	return current.value, current.hasValue, nil
+}
+
+// Delete removes the value at an address, along with any nodes left without descendants. It
+// returns false if the address was not set.
+func (self *Tree) Delete(address string) (bool, error) {
+	tokens, err := tokenizeAddress(address)
+	if err != nil {
+		return false, err
+	}
+
+	// Find the path to the leaf
+	path := []*node{self.root}
+	for _, token := range tokens {
+		child := path[len(path) - 1].children[token]
+		if child == nil {
+			return false, nil
+		}
+		path = append(path, child)
+	}
+	if !path[len(path) - 1].hasValue {
+		return false, nil
+	}
+
+	// Remove the leaf, then any ancestors (other than the root) which are now empty
+	for i := len(path) - 1; i >= 0; i-- {
+		path[i].hash = ""
+		if i > 0 && len(path[i].children) == 0 && (i == len(path) - 1 || !path[i].hasValue) {
+			delete(path[i - 1].children, tokens[i - 1])
+		}
+	}
+
+	return true, nil
+}
+
+// SetStates sets the value of each state. Later states replace earlier ones at the same address.
+func (self *Tree) SetStates(states []*types.State) error {
+	for _, state := range states {
+		err := self.Set(state.Address, state.Data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RootHash returns the state root hash of the tree.
+func (self *Tree) RootHash() string {
+	return self.root.computeHash()
+}
+
+// computeHash returns the hash of the node, computing the hashes of any changed descendants.
+func (self *node) computeHash() string {
+	if self.hash != "" {
+		return self.hash
+	}
+
+	children := make(map[string]string, len(self.children))
+	for token, child := range self.children {
+		children[token] = child.computeHash()
+	}
+
+	self.hash = hashNode(encodeNode(self.value, self.hasValue, children))
+	return self.hash
+}
+
+// hashNode returns the hash of an encoded node.
+func hashNode(encoded []byte) string {
+	hash := sha512.Sum512(encoded)
+	return hex.EncodeToString(hash[:])[:HASH_LENGTH]
+}
+
+// tokenizeAddress splits an address into the tokens used to address each level of the tree.
+func tokenizeAddress(address string) ([]string, error) {
+	if len(address) != types.ADDRESS_LENGTH {
+		return nil, fmt.Errorf("Invalid state address (must be %d characters): %s", types.ADDRESS_LENGTH, address)
+	}
+	if strings.ToLower(address) != address {
+		return nil, fmt.Errorf("Invalid state address (must be lower case): %s", address)
+	}
+	_, err := hex.DecodeString(address)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid state address: %s", err)
+	}
+
+	tokens := make([]string, 0, types.ADDRESS_LENGTH / TOKEN_SIZE)
+	for i := 0; i < types.ADDRESS_LENGTH; i += TOKEN_SIZE {
+		tokens = append(tokens, address[i:i + TOKEN_SIZE])
+	}
+
+	return tokens, nil
+}
+
+// StateRoot returns the state root hash of a set of states.
+func StateRoot(states []*types.State) (string, error) {
+	tree := NewTree()
+	err := tree.SetStates(states)
+	if err != nil {
+		return "", err
+	}
+
+	return tree.RootHash(), nil
+}
+
+// StateRootFromIterator returns the state root hash of all states read from an iterator, which
+// must cover the whole of state (e.g. GetStateIteratorAtHead with an empty prefix).
+func StateRootFromIterator(iterator types.Iterator[*types.State]) (string, error) {
+	tree := NewTree()
+	err := types.ForEach(iterator, func(state *types.State) (bool, error) {
+		return true, tree.Set(state.Address, state.Data)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return tree.RootHash(), nil
+}
+
+// CheckStateRoot returns an error if the state root hash of the tree differs from that of header.
+func (self *Tree) CheckStateRoot(header *types.BlockHeader) error {
+	rootHash := self.RootHash()
+	if rootHash != header.StateRootHash {
+		return fmt.Errorf("State root mismatch at block %d: computed %s, block has %s", header.BlockNum, rootHash, header.StateRootHash)
+	}
+
+	return nil
+}
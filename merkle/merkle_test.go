@@ -0,0 +1,150 @@
+package merkle_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/taekion-org/sawtooth-client-sdk-go/merkle"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/conformance"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+func TestStateRoot(t *testing.T) {
+	fixture := conformance.NewFixture()
+	head := conformance.FIXTURE_BLOCKS - 1
+
+	// The state after each block must hash to the block's state root, in any order
+	for blockNum, header := range fixture.Headers {
+		var states []*types.State
+		for _, address := range fixture.AddressesAt(blockNum, "") {
+			states = append(states, &types.State{Address: address, Data: fixture.States[blockNum][address]})
+		}
+
+		for _, reverse := range []bool{false, true} {
+			if reverse {
+				slices.Reverse(states)
+			}
+
+			stateRoot, err := merkle.StateRoot(states)
+			if err != nil {
+				t.Fatalf("StateRoot(%d): %s", blockNum, err)
+			}
+			if stateRoot != header.StateRootHash {
+				t.Fatalf("StateRoot(%d, reverse=%t): got %s, expected %s", blockNum, reverse, stateRoot, header.StateRootHash)
+			}
+		}
+	}
+
+	headBlock, err := types.BlockFromProto(fixture.Head())
+	if err != nil {
+		t.Fatalf("BlockFromProto(head): %s", err)
+	}
+	genesis, err := types.BlockFromProto(fixture.Blocks[0])
+	if err != nil {
+		t.Fatalf("BlockFromProto(genesis): %s", err)
+	}
+
+	// Changing, adding or removing any value changes the state root
+	tree := merkle.NewTree()
+	for address, value := range fixture.States[head] {
+		err := tree.Set(address, value)
+		if err != nil {
+			t.Fatalf("Set(%s): %s", address, err)
+		}
+	}
+	err = tree.CheckStateRoot(&headBlock.Header)
+	if err != nil {
+		t.Fatalf("CheckStateRoot(): %s", err)
+	}
+
+	address := fixture.AddressesAt(head, "")[0]
+	tree.Set(address, []byte("changed"))
+	if tree.CheckStateRoot(&headBlock.Header) == nil {
+		t.Fatalf("CheckStateRoot() succeeded after changing a value")
+	}
+	tree.Set(address, fixture.States[head][address])
+
+	tree.Set(conformance.FixtureAddress(conformance.FIXTURE_KEYS), []byte("added"))
+	if tree.CheckStateRoot(&headBlock.Header) == nil {
+		t.Fatalf("CheckStateRoot() succeeded after adding a value")
+	}
+	tree.Delete(conformance.FixtureAddress(conformance.FIXTURE_KEYS))
+	err = tree.CheckStateRoot(&headBlock.Header)
+	if err != nil {
+		t.Fatalf("CheckStateRoot() after delete: %s", err)
+	}
+
+	tree.Delete(address)
+	if tree.CheckStateRoot(&headBlock.Header) == nil {
+		t.Fatalf("CheckStateRoot() succeeded after removing a value")
+	}
+
+	empty := merkle.NewTree()
+	err = empty.CheckStateRoot(&genesis.Header)
+	if err != nil {
+		t.Fatalf("CheckStateRoot(genesis): %s", err)
+	}
+
+	// Only full, lower case hex addresses can be set
+	for _, invalid := range []string{conformance.FIXTURE_NAMESPACE, "ZZ" + address[2:], "AB" + address[2:]} {
+		err = tree.Set(invalid, []byte("invalid"))
+		if err == nil {
+			t.Fatalf("Set(%s): expected an error", invalid)
+		}
+	}
+}
+
+func TestKnownStateRoots(t *testing.T) {
+	// The state roots of the validator's tree, computed independently of this package by hashing
+	// each node as the CBOR map {"c": {token: child hash}, "v": value or null}, with sorted keys
+	const EMPTY_ROOT = "708ca7fbb701799bb387f2e50deaca402e8502abe229f705693d2d4f350e1ad6"
+	first := "1cf126" + strings.Repeat("00", 32)
+	second := "1cf126" + strings.Repeat("00", 31) + "01"
+	other := strings.Repeat("ab", 35)
+	data := make([]byte, 40)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	tree := merkle.NewTree()
+	if tree.RootHash() != EMPTY_ROOT {
+		t.Fatalf("RootHash(empty): got %s, expected %s", tree.RootHash(), EMPTY_ROOT)
+	}
+
+	steps := []struct {
+		address	string
+		value	[]byte
+		root	string
+	}{
+		{first, []byte("hello"), "7aee3d57dfed6282b0681bf9b7adc444eed95cfd878745531ad803f10036e7b7"},
+		{second, []byte("world"), "b430d668bda8d636ab1253f0b87af7f6f31dd76d8c3b3b1f482cf17f0523e8c2"},
+		{other, data, "50c0d69f83b6fda3e85976151bf212279f43f595475b30818e5d7fcee25ff037"},
+		{first, nil, "a333ba616127d7d9fe992bc0ffd97acb5b04a398b67ca199806faa8add126e5b"},
+		{other, nil, "0810ad062277a07997b3c19d0c4d85d7db76fbe7d4ecd8bfcbf1e3f56639a9fb"},
+		{second, nil, EMPTY_ROOT},
+	}
+
+	// A nil value deletes the address
+	for i, step := range steps {
+		var err error
+		if step.value != nil {
+			err = tree.Set(step.address, step.value)
+		} else {
+			_, err = tree.Delete(step.address)
+		}
+		if err != nil {
+			t.Fatalf("Step %d: %s", i, err)
+		}
+		if tree.RootHash() != step.root {
+			t.Fatalf("Step %d: got state root %s, expected %s", i, tree.RootHash(), step.root)
+		}
+	}
+
+	// An empty value is a leaf, encoded as an empty byte string
+	tree.Set(first, []byte{})
+	expected := "af425863187946606b39a96e4ec493e4c8fa4962607bac40b0c9c4989f292655"
+	if tree.RootHash() != expected {
+		t.Fatalf("RootHash(empty value): got %s, expected %s", tree.RootHash(), expected)
+	}
+}
@@ -2,75 +2,57 @@ package sawtooth_client_sdk_go
 
 import (
 	"fmt"
-	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
 	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
 	"time"
 )
 
-// ExecutePayload submits a single transaction to the blockchain and returns the batch id.
-func (self *SawtoothClient) ExecutePayload(payload SawtoothPayload) (string, error) {
+// ExecutePayload submits a single transaction to the blockchain and returns the submission result.
+func (self *SawtoothClient) ExecutePayload(payload SawtoothPayload) (*SubmissionResult, error) {
 	payloads := []SawtoothPayload{payload}
 	return self.ExecutePayloadBatch(payloads)
 }
 
 // ExecutePayloadSync submits a single transaction to the blockchain and waits for commit.
-func (self *SawtoothClient) ExecutePayloadSync(payload SawtoothPayload, timeout int, pollInterval int) error {
+// See ExecutePayloadBatchSync.
+func (self *SawtoothClient) ExecutePayloadSync(payload SawtoothPayload, timeout int, pollInterval int) (*SubmissionResult, error) {
 	payloads := []SawtoothPayload{payload}
 	return self.ExecutePayloadBatchSync(payloads, timeout, pollInterval)
 }
 
-// ExecutePayload submits a list of transactions to the blockchain (as a single batch) and returns the batch id.
-func (self *SawtoothClient) ExecutePayloadBatch(payloads []SawtoothPayload) (string, error) {
+// ExecutePayloadBatch submits a list of transactions to the blockchain (as a single batch) and returns the submission result.
+func (self *SawtoothClient) ExecutePayloadBatch(payloads []SawtoothPayload) (*SubmissionResult, error) {
 	transactions := make([]*transaction_pb2.Transaction, len(payloads))
 
 	for i, payload := range payloads {
 		transaction, err := self.CreateTransaction(payload)
 		if err != nil {
-			return "", fmt.Errorf("Error while creating transaction for payload %d: %s", i, err)
+			return nil, fmt.Errorf("Error while creating transaction for payload %d: %s", i, err)
 		}
 		transactions[i] = transaction
 	}
 
-	batch, err := self.CreateBatch(transactions)
-	if err != nil {
-		return "", err
-	}
-	batchId := batch.HeaderSignature
-
-	batches := []*batch_pb2.Batch{batch}
-	batchList, err := self.CreateBatchList(batches)
-	if err != nil {
-		return "", err
-	}
-
-	err = self.Transport.SubmitBatchList(batchList)
-	if err != nil {
-		return "", err
-	}
-
-	return batchId, nil
+	return self.submitTransactions(transactions)
 }
 
-// ExecutePayload submits a list of transactions to the blockchain (as a single batch) and waits for commit.
-func (self *SawtoothClient) ExecutePayloadBatchSync(payloads []SawtoothPayload, timeout int, pollInterval int) error {
+// ExecutePayloadBatchSync submits a list of transactions to the blockchain (as a single batch) and waits for commit.
+// The result then also holds the committing block and the transaction receipts. If the batch was
+// submitted but an error occurs while waiting (including the timeout), the result is returned
+// along with the error.
+func (self *SawtoothClient) ExecutePayloadBatchSync(payloads []SawtoothPayload, timeout int, pollInterval int) (*SubmissionResult, error) {
 	// Execute the payload
-	batchId, err := self.ExecutePayloadBatch(payloads)
+	result, err := self.ExecutePayloadBatch(payloads)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Poll for the payload to be executed (batch committed)
-	success, err := self.WaitBatch(batchId, timeout, pollInterval)
+	err = self.WaitCommit(result, timeout, pollInterval)
 	if err != nil {
-		return err
+		return result, err
 	}
 
-	if !success {
-		return fmt.Errorf("Batch not commited as of configured timeout")
-	}
-
-	return nil
+	return result, nil
 }
 
 // WaitBatch performs a polling wait for a particular batch.
@@ -99,6 +81,4 @@ func (self *SawtoothClient) WaitBatch(batchId string, timeout int, pollInterval
 			return false, fmt.Errorf("Batch %s is in status %s", batchId, status)
 		}
 	}
-
-	return false, nil
 }
@@ -0,0 +1,366 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// DAEMON_TIMEOUT is the default timeout of requests to a signing daemon.
+const DAEMON_TIMEOUT = time.Second * 30
+
+// DAEMON_MAX_MESSAGE is the largest message a signing daemon accepts (in bytes).
+const DAEMON_MAX_MESSAGE = 1 << 20
+
+// The signing daemon protocol is JSON over HTTP:
+//
+//	GET  /public_key  -> {"public_key": "<hex>"}
+//	POST /sign        {"message": "<hex>"} -> {"signature": "<hex>"}
+//
+// Errors are returned with a non-2xx status and {"error": "<message>"}.
+const daemonPublicKeyPath = "/public_key"
+const daemonSignPath = "/sign"
+
+type daemonPublicKeyResponse struct {
+	PublicKey	string	`json:"public_key"`
+}
+
+type daemonSignRequest struct {
+	Message		string	`json:"message"`
+}
+
+type daemonSignResponse struct {
+	Signature	string	`json:"signature"`
+}
+
+type daemonErrorResponse struct {
+	Error		string	`json:"error"`
+}
+
+// DaemonSigner is a Signer which delegates signing to a signing daemon (see NewDaemonHandler),
+// so the private key stays in the daemon's process. Every signature returned by the daemon is
+// verified against its public key.
+type DaemonSigner struct {
+	url			*url.URL
+	httpClient	*http.Client
+	token		string
+	publicKey	string
+}
+
+// DaemonSignerOptions holds optional settings for a DaemonSigner.
+type DaemonSignerOptions struct {
+	// HttpClient overrides the default HTTP client (and Timeout). For a unix socket URL, it must
+	// connect to the socket itself.
+	HttpClient	*http.Client
+	// Timeout overrides DAEMON_TIMEOUT.
+	Timeout		time.Duration
+	// Token is sent as a bearer token with each request, if the daemon requires one.
+	Token		string
+	// PublicKey, if set, is the public key the daemon must report.
+	PublicKey	string
+}
+
+// NewDaemonSigner returns a DaemonSigner for the daemon at the given URL, which is either
+// unix:///path/to/socket or http(s)://host:port. Returns an error if the daemon's public key
+// cannot be read.
+func NewDaemonSigner(url *url.URL) (*DaemonSigner, error) {
+	return NewDaemonSignerWithOptions(url, nil)
+}
+
+// NewDaemonSignerWithOptions returns a DaemonSigner configured with the given options (which may
+// be nil).
+func NewDaemonSignerWithOptions(daemonUrl *url.URL, options *DaemonSignerOptions) (*DaemonSigner, error) {
+	if options == nil {
+		options = &DaemonSignerOptions{}
+	}
+
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = DAEMON_TIMEOUT
+	}
+
+	self := &DaemonSigner{url: daemonUrl, httpClient: options.HttpClient, token: options.Token}
+	if daemonUrl.Scheme == "unix" {
+		// Requests are sent to the socket, whatever the host in the URL
+		socketPath := daemonUrl.Path
+		self.url = &url.URL{Scheme: "http", Host: "signer"}
+		if self.httpClient == nil {
+			httpTransport := &http.Transport{
+				DialContext: func(ctx context.Context, network string, address string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", socketPath)
+				},
+			}
+			self.httpClient = &http.Client{Timeout: timeout, Transport: httpTransport}
+		}
+	} else if daemonUrl.Scheme != "http" && daemonUrl.Scheme != "https" {
+		return nil, fmt.Errorf("Unsupported signing daemon URL scheme: %s", daemonUrl.Scheme)
+	}
+	if self.httpClient == nil {
+		self.httpClient = &http.Client{Timeout: timeout}
+	}
+
+	var response daemonPublicKeyResponse
+	err := self.doRequest(context.Background(), http.MethodGet, daemonPublicKeyPath, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKeyBytes, err := hex.DecodeString(response.PublicKey)
+	if err != nil || len(publicKeyBytes) != 33 {
+		return nil, fmt.Errorf("Signing daemon returned an invalid public key: %s", response.PublicKey)
+	}
+	self.publicKey = strings.ToLower(response.PublicKey)
+
+	if options.PublicKey != "" && !strings.EqualFold(options.PublicKey, self.publicKey) {
+		return nil, fmt.Errorf("Signing daemon public key is %s, expected %s", self.publicKey, options.PublicKey)
+	}
+
+	return self, nil
+}
+
+func (self *DaemonSigner) PublicKey() string {
+	return self.publicKey
+}
+
+func (self *DaemonSigner) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	var response daemonSignResponse
+	err := self.doRequest(ctx, http.MethodPost, daemonSignPath, &daemonSignRequest{Message: hex.EncodeToString(message)}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	err = types.VerifySignature(message, response.Signature, self.publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("Signing daemon returned an invalid signature: %s", err)
+	}
+
+	return hex.DecodeString(response.Signature)
+}
+
+// doRequest sends a request to the daemon, decoding the JSON response into result.
+func (self *DaemonSigner) doRequest(ctx context.Context, method string, requestPath string, body interface{}, result interface{}) error {
+	requestUrl := *self.url
+	requestUrl.Path = path.Join(requestUrl.Path, requestPath)
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, requestUrl.String(), reader)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if self.token != "" {
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", self.token))
+	}
+
+	response, err := self.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("Error contacting signing daemon: %s", err)
+	}
+	defer response.Body.Close()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("Error reading signing daemon response: %s", err)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		var errorResponse daemonErrorResponse
+		if json.Unmarshal(data, &errorResponse) == nil && errorResponse.Error != "" {
+			return fmt.Errorf("Signing daemon error (%d): %s", response.StatusCode, errorResponse.Error)
+		}
+		return fmt.Errorf("Signing daemon error (%d)", response.StatusCode)
+	}
+
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return fmt.Errorf("Error decoding signing daemon response: %s", err)
+	}
+
+	return nil
+}
+
+// DaemonHandlerOptions holds optional settings for a signing daemon handler.
+type DaemonHandlerOptions struct {
+	// Token, if set, is the bearer token clients must send with each request.
+	Token				string
+	// FamilyNames, if set, restricts signing to transactions of these families.
+	FamilyNames			[]string
+	// BatcherPublicKey, if set, restricts signing to transactions batched by this key, and to
+	// batches if it is the signer's own key.
+	BatcherPublicKey	string
+}
+
+// NewDaemonHandler returns an http.Handler serving the signing daemon protocol for signer. Only
+// transaction and batch headers naming the signer's public key as their signer are signed. Access
+// to the daemon should be restricted, for example by serving it on a unix socket only readable by
+// the client's user.
+func NewDaemonHandler(signer Signer) http.Handler {
+	return NewDaemonHandlerWithOptions(signer, nil)
+}
+
+// NewDaemonHandlerWithOptions returns a signing daemon handler configured with the given options
+// (which may be nil).
+func NewDaemonHandlerWithOptions(signer Signer, options *DaemonHandlerOptions) http.Handler {
+	handler := &daemonHandler{signer: signer}
+	if options != nil {
+		handler.token = options.Token
+		handler.familyNames = options.FamilyNames
+		handler.batcherPublicKey = strings.ToLower(options.BatcherPublicKey)
+	}
+
+	return handler
+}
+
+// daemonHandler implements the signing daemon protocol.
+type daemonHandler struct {
+	signer				Signer
+	token				string
+	familyNames			[]string
+	batcherPublicKey	string
+}
+
+func (self *daemonHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if self.token != "" {
+		expected := "Bearer " + self.token
+		if subtle.ConstantTimeCompare([]byte(request.Header.Get("Authorization")), []byte(expected)) != 1 {
+			self.writeError(writer, http.StatusUnauthorized, "Invalid or missing bearer token")
+			return
+		}
+	}
+
+	switch {
+	case request.URL.Path == daemonPublicKeyPath && request.Method == http.MethodGet:
+		self.writeResponse(writer, &daemonPublicKeyResponse{PublicKey: self.signer.PublicKey()})
+	case request.URL.Path == daemonSignPath && request.Method == http.MethodPost:
+		self.sign(writer, request)
+	case request.URL.Path == daemonPublicKeyPath || request.URL.Path == daemonSignPath:
+		self.writeError(writer, http.StatusMethodNotAllowed, "Method not allowed")
+	default:
+		self.writeError(writer, http.StatusNotFound, "Not found")
+	}
+}
+
+// sign handles a signing request.
+func (self *daemonHandler) sign(writer http.ResponseWriter, request *http.Request) {
+	var signRequest daemonSignRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(writer, request.Body, 2 * DAEMON_MAX_MESSAGE + 1024))
+	err := decoder.Decode(&signRequest)
+	if err != nil {
+		self.writeError(writer, http.StatusBadRequest, fmt.Sprintf("Invalid request: %s", err))
+		return
+	}
+
+	message, err := hex.DecodeString(signRequest.Message)
+	if err != nil || len(message) > DAEMON_MAX_MESSAGE {
+		self.writeError(writer, http.StatusBadRequest, "Invalid message")
+		return
+	}
+
+	err = self.checkMessage(message)
+	if err != nil {
+		self.writeError(writer, http.StatusForbidden, fmt.Sprintf("Message rejected: %s", err))
+		return
+	}
+
+	signature, err := self.signer.Sign(request.Context(), message)
+	if err != nil {
+		self.writeError(writer, http.StatusInternalServerError, fmt.Sprintf("Signing failed: %s", err))
+		return
+	}
+
+	self.writeResponse(writer, &daemonSignResponse{Signature: hex.EncodeToString(signature)})
+}
+
+// checkMessage returns an error unless message is a serialized transaction or batch header signed
+// by the signer's key, and allowed by the handler's options.
+func (self *daemonHandler) checkMessage(message []byte) error {
+	publicKey := strings.ToLower(self.signer.PublicKey())
+
+	// A batch header has no family name or payload hash, so cannot be taken for a transaction header
+	var transactionHeader transaction_pb2.TransactionHeader
+	err := proto.Unmarshal(message, &transactionHeader)
+	if err == nil && transactionHeader.FamilyName != "" && isPayloadHash(transactionHeader.PayloadSha512) {
+		if strings.ToLower(transactionHeader.SignerPublicKey) != publicKey {
+			return fmt.Errorf("Transaction signer is %s, not %s", transactionHeader.SignerPublicKey, publicKey)
+		}
+		if len(self.familyNames) > 0 && !containsString(self.familyNames, transactionHeader.FamilyName) {
+			return fmt.Errorf("Transaction family %s is not allowed", transactionHeader.FamilyName)
+		}
+		if self.batcherPublicKey != "" && strings.ToLower(transactionHeader.BatcherPublicKey) != self.batcherPublicKey {
+			return fmt.Errorf("Transaction batcher %s is not allowed", transactionHeader.BatcherPublicKey)
+		}
+		return nil
+	}
+
+	var batchHeader batch_pb2.BatchHeader
+	err = proto.Unmarshal(message, &batchHeader)
+	if err == nil && len(batchHeader.TransactionIds) > 0 {
+		if strings.ToLower(batchHeader.SignerPublicKey) != publicKey {
+			return fmt.Errorf("Batch signer is %s, not %s", batchHeader.SignerPublicKey, publicKey)
+		}
+		if self.batcherPublicKey != "" && publicKey != self.batcherPublicKey {
+			return fmt.Errorf("Batches are not allowed")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("Not a transaction or batch header")
+}
+
+// isPayloadHash returns true if hash is a hex encoded SHA-512 hash.
+func isPayloadHash(hash string) bool {
+	decoded, err := hex.DecodeString(hash)
+	return err == nil && len(decoded) == 64
+}
+
+// containsString returns true if values contains value.
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (self *daemonHandler) writeResponse(writer http.ResponseWriter, response interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(writer).Encode(response)
+}
+
+func (self *daemonHandler) writeError(writer http.ResponseWriter, status int, message string) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	json.NewEncoder(writer).Encode(&daemonErrorResponse{Error: message})
+}
+
+var _ Signer = (*DaemonSigner)(nil)
@@ -0,0 +1,196 @@
+package signer_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/signing"
+	"github.com/taekion-org/sawtooth-client-sdk-go/signer"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// corruptSigner is a signer returning signatures of the wrong message.
+type corruptSigner struct {
+	signer.Signer
+}
+
+func (self *corruptSigner) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	return self.Signer.Sign(ctx, append(message, 0))
+}
+
+// serve starts a signing daemon on a unix socket, returning its URL.
+func serve(t *testing.T, handler http.Handler) *url.URL {
+	t.Helper()
+
+	socketPath := filepath.Join(t.TempDir(), "signer.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("Listen: %s", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go http.Serve(listener, handler)
+
+	return &url.URL{Scheme: "unix", Path: socketPath}
+}
+
+// transactionHeader returns a serialized transaction header of the given family, signed by
+// publicKey and batched by batcherPublicKey.
+func transactionHeader(t *testing.T, familyName string, publicKey string, batcherPublicKey string, nonce string) []byte {
+	t.Helper()
+
+	payloadHash := sha512.Sum512([]byte(nonce))
+	header, err := proto.Marshal(&transaction_pb2.TransactionHeader{
+		FamilyName: familyName,
+		FamilyVersion: "1.0",
+		SignerPublicKey: publicKey,
+		BatcherPublicKey: batcherPublicKey,
+		Nonce: nonce,
+		PayloadSha512: hex.EncodeToString(payloadHash[:]),
+	})
+	if err != nil {
+		t.Fatalf("Marshal: %s", err)
+	}
+
+	return header
+}
+
+func TestDaemonSigner(t *testing.T) {
+	seed := sha256.Sum256([]byte("signer"))
+	privateKey := signing.NewSecp256k1PrivateKey(seed[:])
+	publicKey := signing.NewSecp256k1Context().GetPublicKey(privateKey).AsHex()
+
+	memorySigner := signer.NewMemorySigner(privateKey)
+	if memorySigner.PublicKey() != publicKey {
+		t.Fatalf("PublicKey: got %s, expected %s", memorySigner.PublicKey(), publicKey)
+	}
+
+	daemonUrl := serve(t, signer.NewDaemonHandlerWithOptions(memorySigner, &signer.DaemonHandlerOptions{Token: "secret"}))
+	daemonSigner, err := signer.NewDaemonSignerWithOptions(daemonUrl, &signer.DaemonSignerOptions{Token: "secret", PublicKey: strings.ToUpper(publicKey)})
+	if err != nil {
+		t.Fatalf("NewDaemonSigner: %s", err)
+	}
+	if daemonSigner.PublicKey() != publicKey {
+		t.Fatalf("PublicKey(daemon): got %s, expected %s", daemonSigner.PublicKey(), publicKey)
+	}
+
+	// The daemon signs headers with the key it holds
+	header := transactionHeader(t, "intkey", publicKey, publicKey, "daemon")
+	signature, err := daemonSigner.Sign(context.Background(), header)
+	if err != nil {
+		t.Fatalf("Sign: %s", err)
+	}
+	err = types.VerifySignature(header, hex.EncodeToString(signature), publicKey)
+	if err != nil {
+		t.Fatalf("Sign: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = daemonSigner.Sign(ctx, header)
+	if err == nil {
+		t.Fatalf("Sign(daemon, cancelled): expected an error")
+	}
+	_, err = memorySigner.Sign(ctx, header)
+	if err == nil {
+		t.Fatalf("Sign(cancelled): expected an error")
+	}
+
+	// The daemon can also be reached over TCP
+	server := httptest.NewServer(signer.NewDaemonHandler(memorySigner))
+	defer server.Close()
+	serverUrl, _ := url.Parse(server.URL)
+	tcpSigner, err := signer.NewDaemonSigner(serverUrl)
+	if err != nil {
+		t.Fatalf("NewDaemonSigner(tcp): %s", err)
+	}
+	signature, err = tcpSigner.Sign(context.Background(), header)
+	if err != nil {
+		t.Fatalf("Sign(tcp): %s", err)
+	}
+	err = types.VerifySignature(header, hex.EncodeToString(signature), publicKey)
+	if err != nil {
+		t.Fatalf("Sign(tcp): %s", err)
+	}
+
+	// Only headers for the daemon's key are signed
+	batchHeader, err := proto.Marshal(&batch_pb2.BatchHeader{SignerPublicKey: publicKey, TransactionIds: []string{"transaction"}})
+	if err != nil {
+		t.Fatalf("Marshal: %s", err)
+	}
+	_, err = tcpSigner.Sign(context.Background(), batchHeader)
+	if err != nil {
+		t.Fatalf("Sign(batch header): %s", err)
+	}
+
+	otherPublicKey := signing.NewSecp256k1Context().GetPublicKey(signing.NewSecp256k1Context().NewRandomPrivateKey()).AsHex()
+	rejected := map[string][]byte{
+		"arbitrary bytes": []byte("arbitrary bytes"),
+		"empty": {},
+		"other signer": transactionHeader(t, "intkey", otherPublicKey, publicKey, "other"),
+	}
+	for description, message := range rejected {
+		_, err = tcpSigner.Sign(context.Background(), message)
+		if err == nil {
+			t.Fatalf("Sign(%s): expected an error", description)
+		}
+	}
+
+	// The daemon can be restricted to families and a batcher
+	restricted, err := signer.NewDaemonSigner(serve(t, signer.NewDaemonHandlerWithOptions(memorySigner, &signer.DaemonHandlerOptions{FamilyNames: []string{"intkey"}, BatcherPublicKey: otherPublicKey})))
+	if err != nil {
+		t.Fatalf("NewDaemonSigner(restricted): %s", err)
+	}
+	for description, message := range map[string][]byte{
+		"batcher": header,
+		"family": transactionHeader(t, "xo", publicKey, otherPublicKey, "family"),
+		"batch": batchHeader,
+	} {
+		_, err = restricted.Sign(context.Background(), message)
+		if err == nil {
+			t.Fatalf("Sign(restricted %s): expected an error", description)
+		}
+	}
+	_, err = restricted.Sign(context.Background(), transactionHeader(t, "intkey", publicKey, otherPublicKey, "allowed"))
+	if err != nil {
+		t.Fatalf("Sign(restricted): %s", err)
+	}
+
+	// Daemons with the wrong token, key or signatures are rejected
+	_, err = signer.NewDaemonSigner(daemonUrl)
+	if err == nil {
+		t.Fatalf("NewDaemonSigner(no token): expected an error")
+	}
+	_, err = signer.NewDaemonSignerWithOptions(daemonUrl, &signer.DaemonSignerOptions{Token: "secret", PublicKey: otherPublicKey})
+	if err == nil {
+		t.Fatalf("NewDaemonSigner(other public key): expected an error")
+	}
+	_, err = signer.NewDaemonSigner(&url.URL{Scheme: "unix", Path: filepath.Join(t.TempDir(), "missing.sock")})
+	if err == nil {
+		t.Fatalf("NewDaemonSigner(missing): expected an error")
+	}
+	_, err = signer.NewDaemonSigner(&url.URL{Scheme: "ftp", Host: "localhost"})
+	if err == nil {
+		t.Fatalf("NewDaemonSigner(ftp): expected an error")
+	}
+
+	corruptDaemon, err := signer.NewDaemonSigner(serve(t, signer.NewDaemonHandler(&corruptSigner{memorySigner})))
+	if err != nil {
+		t.Fatalf("NewDaemonSigner(corrupt): %s", err)
+	}
+	_, err = corruptDaemon.Sign(context.Background(), header)
+	if err == nil {
+		t.Fatalf("Sign(corrupt): expected an error")
+	}
+}
@@ -0,0 +1,54 @@
+// Package signer abstracts the signing of transaction and batch headers, so private keys need not
+// be held in the client's memory.
+//
+// A Signer may keep its key in memory (MemorySigner), or delegate to a signing daemon
+// (DaemonSigner), an HSM or a cloud KMS. Implementations only need to return the public key and
+// Sawtooth compatible secp256k1 signatures.
+package signer
+
+import (
+	"context"
+
+	"github.com/hyperledger/sawtooth-sdk-go/signing"
+)
+
+// Signer signs serialized transaction and batch headers.
+type Signer interface {
+	// PublicKey returns the signer's hex encoded (compressed secp256k1) public key.
+	PublicKey() string
+	// Sign returns the 64 byte compact secp256k1 signature of the SHA-256 hash of message, as
+	// created by the Sawtooth signing library.
+	Sign(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// MemorySigner is a Signer holding its private key in memory.
+type MemorySigner struct {
+	signer		*signing.Signer
+	publicKey	string
+}
+
+// NewMemorySigner returns a MemorySigner for a secp256k1 private key.
+func NewMemorySigner(privateKey signing.PrivateKey) *MemorySigner {
+	cryptoFactory := signing.NewCryptoFactory(signing.CreateContext("secp256k1"))
+	return NewMemorySignerFromSigner(cryptoFactory.NewSigner(privateKey))
+}
+
+// NewMemorySignerFromSigner returns a MemorySigner wrapping a signer from the Sawtooth SDK.
+func NewMemorySignerFromSigner(signer *signing.Signer) *MemorySigner {
+	return &MemorySigner{signer: signer, publicKey: signer.GetPublicKey().AsHex()}
+}
+
+func (self *MemorySigner) PublicKey() string {
+	return self.publicKey
+}
+
+func (self *MemorySigner) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return self.signer.Sign(message), nil
+}
+
+var _ Signer = (*MemorySigner)(nil)
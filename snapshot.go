@@ -0,0 +1,67 @@
+package sawtooth_client_sdk_go
+
+import (
+	"fmt"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// Snapshot provides a consistent view of state: every read made through a Snapshot is answered
+// at the same chain head, regardless of any blocks committed in the meantime.
+type Snapshot struct {
+	// Head is the id of the block the snapshot is pinned to.
+	Head		string
+	// StateRoot is the state root hash of that block.
+	StateRoot	string
+	// BlockNum is the block number of that block.
+	BlockNum	uint64
+
+	transport	transport.SawtoothClientTransport
+}
+
+// NewSnapshot returns a Snapshot pinned to the current chain head.
+func (self *SawtoothClient) NewSnapshot() (*Snapshot, error) {
+	block, err := types.HeadBlock(self.Transport.GetBlockIterator(1, false))
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving chain head: %s", err)
+	}
+
+	return newSnapshot(self.Transport, block), nil
+}
+
+// NewSnapshotAtHead returns a Snapshot pinned to the given head (block id).
+func (self *SawtoothClient) NewSnapshotAtHead(head string) (*Snapshot, error) {
+	block, err := self.Transport.GetBlock(head)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSnapshot(self.Transport, block), nil
+}
+
+// newSnapshot constructs a Snapshot pinned to the given block.
+func newSnapshot(transport transport.SawtoothClientTransport, block *types.Block) *Snapshot {
+	return &Snapshot{
+		Head: block.HeaderSignature,
+		StateRoot: block.Header.StateRootHash,
+		BlockNum: block.Header.BlockNum,
+		transport: transport,
+	}
+}
+
+// GetState returns the state at the given address, as of the snapshot's head.
+func (self *Snapshot) GetState(address string) (*types.State, error) {
+	return self.transport.GetStateAtHead(address, self.Head)
+}
+
+// GetStates returns the state at each of the given addresses, as of the snapshot's head.
+// Addresses that have no state are present in the result with a nil value.
+func (self *Snapshot) GetStates(addresses []string) (map[string]*types.State, error) {
+	return self.transport.GetStatesAtHead(addresses, self.Head)
+}
+
+// GetStateIterator returns a types.StateIterator that can iterate over all state matching the given
+// prefix, as of the snapshot's head.
+func (self *Snapshot) GetStateIterator(addressPrefix string, fetch int, reverse bool) types.StateIterator {
+	return self.transport.GetStateIteratorAtHead(addressPrefix, self.Head, fetch, reverse)
+}
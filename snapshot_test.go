@@ -0,0 +1,97 @@
+package sawtooth_client_sdk_go_test
+
+import (
+	"testing"
+
+	sawtooth_client_sdk_go "github.com/taekion-org/sawtooth-client-sdk-go"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/conformance"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/errors"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+func TestSnapshot(t *testing.T) {
+	transports := map[string]func(testing.TB, *conformance.Validator) transport.SawtoothClientTransport{
+		"rest": conformance.NewRestTransport,
+		"zmq": conformance.NewZmqTransport,
+	}
+
+	for name, newTransport := range transports {
+		t.Run(name, func(t *testing.T) {
+			fixture := conformance.NewFixture()
+			client := &sawtooth_client_sdk_go.SawtoothClient{Transport: newTransport(t, conformance.NewValidator(fixture))}
+			head := len(fixture.Blocks) - 1
+
+			snapshot, err := client.NewSnapshot()
+			if err != nil {
+				t.Fatalf("NewSnapshot: %s", err)
+			}
+			if snapshot.Head != fixture.Head().HeaderSignature || snapshot.StateRoot != fixture.StateRoots[head] || snapshot.BlockNum != uint64(head) {
+				t.Fatalf("NewSnapshot: unexpected snapshot %+v", snapshot)
+			}
+
+			// A block changing state is committed after the snapshot was taken
+			changed := fixture.AddressesAt(head, "")[0]
+			added := conformance.FixtureAddress(conformance.FIXTURE_KEYS)
+			fixture.CommitStates(map[string][]byte{changed: []byte("changed"), added: []byte("added")})
+
+			state, err := client.Transport.GetState(changed)
+			if err != nil || string(state.Data) != "changed" {
+				t.Fatalf("GetState(changed): got %v (%v), expected the committed value", state, err)
+			}
+
+			// The snapshot still answers at the head it was pinned to
+			state, err = snapshot.GetState(changed)
+			if err != nil || string(state.Data) != string(fixture.States[head][changed]) {
+				t.Fatalf("Snapshot.GetState(changed): got %v (%v), expected %q", state, err, fixture.States[head][changed])
+			}
+			_, err = snapshot.GetState(added)
+			if !errors.HasErrorCode(err, errors.STATE_NOT_FOUND) {
+				t.Fatalf("Snapshot.GetState(added): expected error code %d, got: %v", errors.STATE_NOT_FOUND, err)
+			}
+
+			states, err := snapshot.GetStates([]string{changed, added})
+			if err != nil {
+				t.Fatalf("Snapshot.GetStates: %s", err)
+			}
+			if states[changed] == nil || string(states[changed].Data) != string(fixture.States[head][changed]) || states[added] != nil {
+				t.Fatalf("Snapshot.GetStates: unexpected states %v", states)
+			}
+
+			values, err := types.Collect(types.NewIterator(snapshot.GetStateIterator(conformance.FIXTURE_NAMESPACE, 3, false)))
+			if err != nil {
+				t.Fatalf("Snapshot.GetStateIterator: %s", err)
+			}
+			addresses := fixture.AddressesAt(head, conformance.FIXTURE_NAMESPACE)
+			if len(values) != len(addresses) {
+				t.Fatalf("Snapshot.GetStateIterator: got %d states, expected %d", len(values), len(addresses))
+			}
+			for i, address := range addresses {
+				if values[i].Address != address || string(values[i].Data) != string(fixture.States[head][address]) {
+					t.Fatalf("Snapshot.GetStateIterator: got %s=%q, expected %s=%q", values[i].Address, values[i].Data, address, fixture.States[head][address])
+				}
+			}
+
+			// A snapshot can be pinned to an earlier head
+			past := 4
+			pastSnapshot, err := client.NewSnapshotAtHead(fixture.Blocks[past].HeaderSignature)
+			if err != nil {
+				t.Fatalf("NewSnapshotAtHead: %s", err)
+			}
+			if pastSnapshot.StateRoot != fixture.StateRoots[past] || pastSnapshot.BlockNum != uint64(past) {
+				t.Fatalf("NewSnapshotAtHead: unexpected snapshot %+v", pastSnapshot)
+			}
+			for _, address := range fixture.AddressesAt(past, "") {
+				state, err = pastSnapshot.GetState(address)
+				if err != nil || string(state.Data) != string(fixture.States[past][address]) {
+					t.Fatalf("Snapshot.GetState(past): got %v (%v), expected %q", state, err, fixture.States[past][address])
+				}
+			}
+
+			_, err = client.NewSnapshotAtHead(conformance.UnknownId("snapshot"))
+			if err == nil {
+				t.Fatalf("NewSnapshotAtHead(unknown): expected an error")
+			}
+		})
+	}
+}
@@ -1,29 +1,56 @@
 package sawtooth_client_sdk_go
 
 import (
+	"context"
 	"encoding/hex"
+	"fmt"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	"github.com/taekion-org/sawtooth-client-sdk-go/signer"
 	"strings"
 )
 
-// CreateTransaction constructs a single transaction from the provided payload.
+// CreateTransaction constructs a single transaction from the provided payload, to be batched by
+// this client (see BatcherPublicKey).
 func (self *SawtoothClient) CreateTransaction(payload SawtoothPayload) (*transaction_pb2.Transaction, error) {
+	return self.CreateTransactionForBatcher(payload, self.BatcherPublicKey())
+}
+
+// CreateTransactionForBatcher constructs a single transaction from the provided payload, signed by
+// this client, to be batched by the holder of batcherPublicKey (a hex encoded public key).
+func (self *SawtoothClient) CreateTransactionForBatcher(payload SawtoothPayload, batcherPublicKey string) (*transaction_pb2.Transaction, error) {
+	return self.CreateTransactionForBatcherContext(context.Background(), payload, batcherPublicKey)
+}
+
+// CreateTransactionForBatcherContext is CreateTransactionForBatcher, passing ctx to the signer.
+func (self *SawtoothClient) CreateTransactionForBatcherContext(ctx context.Context, payload SawtoothPayload, batcherPublicKey string) (*transaction_pb2.Transaction, error) {
+	return self.createTransaction(ctx, payload, batcherPublicKey, nil)
+}
+
+// createTransaction constructs a single transaction from the provided payload, adding dependencies
+// to those of the payload.
+func (self *SawtoothClient) createTransaction(ctx context.Context, payload SawtoothPayload, batcherPublicKey string, dependencies []string) (*transaction_pb2.Transaction, error) {
 	payloadEncoded, err := self.ClientImpl.EncodePayload(payload)
 	if err != nil {
 		return nil, err
 	}
 
+	// The payload's own slice is not modified
+	transactionDependencies := payload.GetDependencies()
+	if len(dependencies) > 0 {
+		transactionDependencies = append(append([]string{}, transactionDependencies...), dependencies...)
+	}
+
 	headerPB := &transaction_pb2.TransactionHeader{
-		SignerPublicKey:  self.Signer.GetPublicKey().AsHex(),
+		SignerPublicKey:  self.Signer.PublicKey(),
 		FamilyName:       self.ClientImpl.GetFamilyName(),
 		FamilyVersion:    self.ClientImpl.GetFamilyVersion(),
 		Inputs:           payload.GetInputAddresses(),
 		Outputs:          payload.GetOutputAddresses(),
-		Dependencies:     payload.GetDependencies(),
+		Dependencies:     transactionDependencies,
 		PayloadSha512:    HexdigestByte(payloadEncoded),
-		BatcherPublicKey: self.Signer.GetPublicKey().AsHex(),
+		BatcherPublicKey: strings.ToLower(batcherPublicKey),
 		Nonce:            payload.GetNonce(),
 	}
 
@@ -32,7 +59,11 @@ func (self *SawtoothClient) CreateTransaction(payload SawtoothPayload) (*transac
 		return nil, err
 	}
 
-	signature := strings.ToLower(hex.EncodeToString(self.Signer.Sign(header)))
+	signatureBytes, err := self.Signer.Sign(ctx, header)
+	if err != nil {
+		return nil, fmt.Errorf("Error signing transaction: %s", err)
+	}
+	signature := strings.ToLower(hex.EncodeToString(signatureBytes))
 
 	transaction := &transaction_pb2.Transaction{
 		Header:          header,
@@ -42,15 +73,38 @@ func (self *SawtoothClient) CreateTransaction(payload SawtoothPayload) (*transac
 	return transaction, nil
 }
 
-// CreateBatch constructs a batch from a list of payloads.
+// CreateBatch constructs a batch from a list of transactions, signed by this client's batcher (see
+// BatcherPublicKey). Every transaction must name that key as its batcher.
 func (self *SawtoothClient) CreateBatch(transactions []*transaction_pb2.Transaction) (*batch_pb2.Batch, error) {
+	return self.CreateBatchWithSigner(transactions, self.batchSigner())
+}
+
+// CreateBatchWithSigner constructs a batch from a list of transactions, signed by batchSigner.
+// Every transaction must name batchSigner's public key as its batcher.
+func (self *SawtoothClient) CreateBatchWithSigner(transactions []*transaction_pb2.Transaction, batchSigner signer.Signer) (*batch_pb2.Batch, error) {
+	return self.CreateBatchWithSignerContext(context.Background(), transactions, batchSigner)
+}
+
+// CreateBatchWithSignerContext is CreateBatchWithSigner, passing ctx to the signer.
+func (self *SawtoothClient) CreateBatchWithSignerContext(ctx context.Context, transactions []*transaction_pb2.Transaction, batchSigner signer.Signer) (*batch_pb2.Batch, error) {
+	batcherPublicKey := batchSigner.PublicKey()
+
 	var transactionSignatures []string
 	for _, transaction := range transactions {
+		var transactionHeader transaction_pb2.TransactionHeader
+		err := proto.Unmarshal(transaction.Header, &transactionHeader)
+		if err != nil {
+			return nil, fmt.Errorf("Error decoding header of transaction %s: %s", transaction.HeaderSignature, err)
+		}
+		if !strings.EqualFold(transactionHeader.BatcherPublicKey, batcherPublicKey) {
+			return nil, fmt.Errorf("Transaction %s must be batched by %s, not %s", transaction.HeaderSignature, transactionHeader.BatcherPublicKey, batcherPublicKey)
+		}
+
 		transactionSignatures = append(transactionSignatures, transaction.HeaderSignature)
 	}
 
 	headerPB := batch_pb2.BatchHeader{
-		SignerPublicKey: self.Signer.GetPublicKey().AsHex(),
+		SignerPublicKey: batcherPublicKey,
 		TransactionIds:  transactionSignatures,
 	}
 	header, err := proto.Marshal(&headerPB)
@@ -58,7 +112,11 @@ func (self *SawtoothClient) CreateBatch(transactions []*transaction_pb2.Transact
 		return nil, err
 	}
 
-	signature := strings.ToLower(hex.EncodeToString(self.Signer.Sign(header)))
+	signatureBytes, err := batchSigner.Sign(ctx, header)
+	if err != nil {
+		return nil, fmt.Errorf("Error signing batch: %s", err)
+	}
+	signature := strings.ToLower(hex.EncodeToString(signatureBytes))
 
 	batch := &batch_pb2.Batch{
 		Header:          header,
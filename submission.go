@@ -0,0 +1,143 @@
+package sawtooth_client_sdk_go
+
+import (
+	"fmt"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+	"time"
+)
+
+// COMMIT_SEARCH_DEPTH is the number of blocks, from the chain head, searched for the block that
+// committed a batch.
+const COMMIT_SEARCH_DEPTH = 100
+
+// SubmittedTransaction describes a transaction submitted in a batch.
+type SubmittedTransaction struct {
+	TransactionId		string
+	Nonce				string
+	SignerPublicKey		string
+	BatcherPublicKey	string
+	Dependencies		[]string
+	// PayloadSize is the size of the encoded payload, in bytes.
+	PayloadSize			int
+}
+
+// SubmissionResult describes a batch submitted by the client and its transactions, in batch order.
+type SubmissionResult struct {
+	BatchId					string
+	BatchSignerPublicKey	string
+	Transactions			[]SubmittedTransaction
+	// SubmittedAt is the time at which the batch was accepted by the transport.
+	SubmittedAt				time.Time
+
+	// BlockId, BlockNum and Receipts (in transaction order) are set by WaitCommit (and the Sync methods), once
+	// the batch has been committed. BlockId and BlockNum are left unset if the committing block is no
+	// longer within COMMIT_SEARCH_DEPTH blocks of the chain head.
+	BlockId					string
+	BlockNum				uint64
+	Receipts				[]*types.TransactionReceipt
+}
+
+// TransactionIds returns the ids of the submitted transactions, in batch order.
+func (self *SubmissionResult) TransactionIds() []string {
+	transactionIds := make([]string, len(self.Transactions))
+	for i, transaction := range self.Transactions {
+		transactionIds[i] = transaction.TransactionId
+	}
+
+	return transactionIds
+}
+
+// Committed returns true if the result records the block that committed the batch.
+func (self *SubmissionResult) Committed() bool {
+	return self.BlockId != ""
+}
+
+// newSubmissionResult describes a batch about to be submitted.
+func newSubmissionResult(batch *batch_pb2.Batch, transactions []*transaction_pb2.Transaction) (*SubmissionResult, error) {
+	decoded, err := types.BatchFromProto(batch)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding batch: %s", err)
+	}
+
+	result := &SubmissionResult{
+		BatchId: batch.HeaderSignature,
+		BatchSignerPublicKey: decoded.Header.SignerPublicKey,
+		Transactions: make([]SubmittedTransaction, len(decoded.Transactions)),
+	}
+
+	for i, transaction := range decoded.Transactions {
+		result.Transactions[i] = SubmittedTransaction{
+			TransactionId: transaction.HeaderSignature,
+			Nonce: transaction.Header.Nonce,
+			SignerPublicKey: transaction.Header.SignerPublicKey,
+			BatcherPublicKey: transaction.Header.BatcherPublicKey,
+			Dependencies: transaction.Header.Dependencies,
+			PayloadSize: len(transactions[i].Payload),
+		}
+	}
+
+	return result, nil
+}
+
+// WaitCommit performs a polling wait for a submitted batch to be committed (as WaitBatch does), then
+// records the committing block and the receipts of its transactions in the result. Failing to find
+// the committing block is not an error: the batch is committed, but Committed() returns false.
+func (self *SawtoothClient) WaitCommit(result *SubmissionResult, timeout int, pollInterval int) error {
+	success, err := self.WaitBatch(result.BatchId, timeout, pollInterval)
+	if err != nil {
+		return err
+	}
+
+	if !success {
+		return fmt.Errorf("Batch not commited as of configured timeout")
+	}
+
+	block, err := self.findBatchBlock(result.BatchId)
+	if err != nil {
+		return err
+	}
+	if block != nil {
+		result.BlockId = block.HeaderSignature
+		result.BlockNum = block.Header.BlockNum
+	}
+
+	receipts, err := self.Transport.GetTransactionReceipts(result.TransactionIds())
+	if err != nil {
+		return fmt.Errorf("Error getting transaction receipts: %s", err)
+	}
+
+	result.Receipts = make([]*types.TransactionReceipt, len(result.Transactions))
+	for i, transaction := range result.Transactions {
+		receipt, ok := receipts[transaction.TransactionId]
+		if !ok {
+			return fmt.Errorf("No receipt returned for transaction %s", transaction.TransactionId)
+		}
+		result.Receipts[i] = receipt
+	}
+
+	return nil
+}
+
+// findBatchBlock returns the block that committed a batch, searching back from the chain head, or
+// nil if it is not within COMMIT_SEARCH_DEPTH blocks.
+func (self *SawtoothClient) findBatchBlock(batchId string) (*types.Block, error) {
+	iterator := self.Transport.GetBlockIterator(COMMIT_SEARCH_DEPTH, false)
+	defer iterator.Close()
+
+	for searched := 0; searched < COMMIT_SEARCH_DEPTH && iterator.Next(); searched++ {
+		block, err := iterator.Current()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, blockBatchId := range block.Header.BatchIds {
+			if blockBatchId == batchId {
+				return block, nil
+			}
+		}
+	}
+
+	return nil, iterator.Error()
+}
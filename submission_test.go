@@ -0,0 +1,110 @@
+package sawtooth_client_sdk_go_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	sawtooth_client_sdk_go "github.com/taekion-org/sawtooth-client-sdk-go"
+	"github.com/taekion-org/sawtooth-client-sdk-go/signer"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/conformance"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// headerSignaturePattern matches a well-formed transaction or batch id.
+var headerSignaturePattern = regexp.MustCompile("^[0-9a-f]{128}$")
+
+func TestSubmissionResult(t *testing.T) {
+	fixture := conformance.NewFixture()
+	clientTransport := conformance.NewRestTransport(t, conformance.NewValidator(fixture))
+
+	client := &sawtooth_client_sdk_go.SawtoothClient{Signer: signer.NewMemorySignerFromSigner(fixture.Signer), Transport: clientTransport, ClientImpl: &fixtureImpl{}}
+	newPayloads := func(nonce string) []sawtooth_client_sdk_go.SawtoothPayload {
+		return []sawtooth_client_sdk_go.SawtoothPayload{&fixturePayload{Value: "first", Nonce: nonce + "-1"}, &fixturePayload{Value: "second value", Nonce: nonce + "-2"}}
+	}
+
+	// checkResult checks the description of the transactions submitted for payloads
+	checkResult := func(description string, result *sawtooth_client_sdk_go.SubmissionResult, payloads []sawtooth_client_sdk_go.SawtoothPayload) {
+		t.Helper()
+
+		if result.BatchSignerPublicKey != fixture.SignerPublicKey || len(result.Transactions) != len(payloads) {
+			t.Fatalf("%s: unexpected result %+v", description, result)
+		}
+		for i, transaction := range result.Transactions {
+			if !headerSignaturePattern.MatchString(transaction.TransactionId) || transaction.TransactionId != result.TransactionIds()[i] {
+				t.Fatalf("%s: unexpected transaction id %s", description, transaction.TransactionId)
+			}
+			payload := payloads[i].(*fixturePayload)
+			if transaction.SignerPublicKey != fixture.SignerPublicKey || transaction.BatcherPublicKey != fixture.SignerPublicKey || transaction.Nonce != payload.Nonce {
+				t.Fatalf("%s: unexpected transaction %+v", description, transaction)
+			}
+			if transaction.PayloadSize != len(payload.Value) {
+				t.Fatalf("%s: got payload size %d, expected %d", description, transaction.PayloadSize, len(payload.Value))
+			}
+		}
+	}
+
+	before := time.Now()
+	payloads := newPayloads("pending")
+	result, err := client.ExecutePayloadBatch(payloads)
+	if err != nil {
+		t.Fatalf("ExecutePayloadBatch: %s", err)
+	}
+	checkResult("ExecutePayloadBatch", result, payloads)
+	if result.SubmittedAt.Before(before) || result.SubmittedAt.After(time.Now()) || result.Committed() {
+		t.Fatalf("ExecutePayloadBatch: unexpected result %+v", result)
+	}
+	status, err := clientTransport.GetBatchStatus(result.BatchId, 0)
+	if err != nil || status != types.BATCH_STATUS_PENDING {
+		t.Fatalf("GetBatchStatus: got %s (%v), expected %s", status, err, types.BATCH_STATUS_PENDING)
+	}
+
+	// Once committed, the result records the block and the receipts
+	fixture.AutoCommit = true
+	payloads = newPayloads("committed")
+	result, err = client.ExecutePayloadBatchSync(payloads, 5, 1)
+	if err != nil {
+		t.Fatalf("ExecutePayloadBatchSync: %s", err)
+	}
+	checkResult("ExecutePayloadBatchSync", result, payloads)
+	if !result.Committed() || result.BlockId != fixture.Head().HeaderSignature || result.BlockNum != conformance.FIXTURE_BLOCKS {
+		t.Fatalf("ExecutePayloadBatchSync: got block %d %s, expected %d %s", result.BlockNum, result.BlockId, conformance.FIXTURE_BLOCKS, fixture.Head().HeaderSignature)
+	}
+	if len(result.Receipts) != len(payloads) {
+		t.Fatalf("ExecutePayloadBatchSync: got %d receipts, expected %d", len(result.Receipts), len(payloads))
+	}
+
+	batch, err := clientTransport.GetBatch(result.BatchId)
+	if err != nil {
+		t.Fatalf("GetBatch: %s", err)
+	}
+	for i, transaction := range batch.Transactions {
+		submitted := result.Transactions[i]
+		if transaction.HeaderSignature != submitted.TransactionId || transaction.Header.Nonce != submitted.Nonce || len(transaction.Payload) != submitted.PayloadSize {
+			t.Fatalf("GetBatch: transaction %d differs from the result: %+v", i, submitted)
+		}
+		if result.Receipts[i].TransactionId != submitted.TransactionId {
+			t.Fatalf("ExecutePayloadBatchSync: receipt %d is for transaction %s", i, result.Receipts[i].TransactionId)
+		}
+	}
+
+	// A batch committed too far back from the head is committed, but its block is not recorded
+	fixture.AutoCommit = false
+	for i := 0; i < sawtooth_client_sdk_go.COMMIT_SEARCH_DEPTH; i++ {
+		fixture.Commit(nil)
+	}
+	buried := &sawtooth_client_sdk_go.SubmissionResult{BatchId: result.BatchId, Transactions: result.Transactions}
+	err = client.WaitCommit(buried, 1, 0)
+	if err != nil {
+		t.Fatalf("WaitCommit(buried): %s", err)
+	}
+	if buried.Committed() || len(buried.Receipts) != len(payloads) {
+		t.Fatalf("WaitCommit(buried): unexpected result %+v", buried)
+	}
+
+	// The result is returned along with the error when the batch is not committed in time
+	result, err = client.ExecutePayloadSync(&fixturePayload{Value: "timeout", Nonce: "timeout"}, 1, 0)
+	if err == nil || result == nil || result.Committed() || len(result.Transactions) != 1 {
+		t.Fatalf("ExecutePayloadSync(timeout): got %+v (%v), expected an uncommitted result and an error", result, err)
+	}
+}
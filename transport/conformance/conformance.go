@@ -0,0 +1,169 @@
+// Package conformance provides a test suite that checks a SawtoothClientTransport implementation
+// against a known fixture chain, so that every transport can be shown to behave identically.
+//
+// The suite is backed by a fake Validator which answers validator requests from the fixture.
+// A transport is connected to it either directly (see Validator.NewConnection, which stands in
+// for a ZMQ connection) or through RestApi, which serves the REST API on top of the validator.
+// The tests of packages built on top of a transport use the same fixture, through NewRestTransport
+// or NewZmqTransport.
+package conformance
+
+import (
+	"bytes"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/errors"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// TransportConstructor returns a transport connected to the given validator.
+type TransportConstructor func(t *testing.T, validator *Validator) transport.SawtoothClientTransport
+
+// Run runs the conformance suite. Each test gets a fresh fixture, validator and transport.
+func Run(t *testing.T, newTransport TransportConstructor) {
+	tests := []struct {
+		name	string
+		test	func(*testing.T, *Fixture, transport.SawtoothClientTransport)
+	}{
+		{"GetBlock", testGetBlock},
+		{"BlockIterator", testBlockIterator},
+		{"BlockRangeIterator", testBlockRangeIterator},
+		{"BlockIteratorSince", testBlockIteratorSince},
+		{"BlockCursorSince", testBlockCursorSince},
+		{"GetBatch", testGetBatch},
+		{"BatchIterator", testBatchIterator},
+		{"GetTransaction", testGetTransaction},
+		{"TransactionIterator", testTransactionIterator},
+		{"IteratorCursor", testIteratorCursor},
+		{"Prefetch", testPrefetch},
+		{"GetState", testGetState},
+		{"GetStates", testGetStates},
+		{"StateIterator", testStateIterator},
+		{"BatchStatus", testBatchStatus},
+		{"SubmitBatchList", testSubmitBatchList},
+		{"TransactionReceipts", testTransactionReceipts},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fixture := NewFixture()
+			transport := newTransport(t, NewValidator(fixture))
+			test.test(t, fixture, transport)
+		})
+	}
+}
+
+// RunChain runs the tests of the suite reading blocks, batches and transactions (and resuming
+// iterators, which includes state at the chain head) against a transport serving the given
+// fixture. It is used for read-only transports which cannot be connected to a Validator, such as
+// those serving an archive of the chain.
+func RunChain(t *testing.T, fixture *Fixture, clientTransport transport.SawtoothClientTransport) {
+	tests := []struct {
+		name	string
+		test	func(*testing.T, *Fixture, transport.SawtoothClientTransport)
+	}{
+		{"GetBlock", testGetBlock},
+		{"BlockIterator", testBlockIterator},
+		{"BlockRangeIterator", testBlockRangeIterator},
+		{"BlockIteratorSince", testBlockIteratorSince},
+		{"GetBatch", testGetBatch},
+		{"BatchIterator", testBatchIterator},
+		{"GetTransaction", testGetTransaction},
+		{"TransactionIterator", testTransactionIterator},
+		{"IteratorCursor", testIteratorCursor},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.test(t, fixture, clientTransport)
+		})
+	}
+}
+
+// pageSizes returns the page sizes used to test paging over n items: single items, a size that
+// does not divide n, a size ending exactly at the last item, one larger than n, and the default.
+func pageSizes(n int) []int {
+	return []int{1, 3, n, n + 1, 0}
+}
+
+// collect reads all values of an iterator, failing the test on error.
+func collect[T any](t *testing.T, iterator types.Iterator[T]) []T {
+	t.Helper()
+
+	values, err := types.Collect(iterator)
+	if err != nil {
+		t.Fatalf("Iteration failed: %s", err)
+	}
+
+	return values
+}
+
+// checkValues fails the test if the values (which are pointers) differ from those expected.
+func checkValues[T any](t *testing.T, description string, got []*T, expected []*T) {
+	t.Helper()
+
+	if len(got) != len(expected) {
+		t.Fatalf("%s: got %d values, expected %d", description, len(got), len(expected))
+	}
+
+	for i := range expected {
+		if !reflect.DeepEqual(*got[i], *expected[i]) {
+			t.Fatalf("%s: value %d differs:\n got: %+v\nwant: %+v", description, i, *got[i], *expected[i])
+		}
+	}
+}
+
+// checkErrorCode fails the test unless err is a transport error with the given code.
+func checkErrorCode(t *testing.T, description string, err error, code errors.SawtoothTransportErrorCode) {
+	t.Helper()
+
+	if !errors.HasErrorCode(err, code) {
+		t.Fatalf("%s: expected error code %d, got: %v", description, code, err)
+	}
+}
+
+// checkState fails the test if state does not hold the expected address, data and head.
+// An empty head is not checked.
+func checkState(t *testing.T, description string, state *types.State, address string, data []byte, head string) {
+	t.Helper()
+
+	if state == nil {
+		t.Fatalf("%s: no state returned", description)
+	}
+	if state.Address != address || !bytes.Equal(state.Data, data) {
+		t.Fatalf("%s: got %s=%q, expected %s=%q", description, state.Address, state.Data, address, data)
+	}
+	if head != "" && state.Head != head {
+		t.Fatalf("%s: got head %s, expected %s", description, state.Head, head)
+	}
+}
+
+// WaitForGoroutines fails the test unless every goroutine running a function whose name contains
+// name exits within a few seconds, as goroutines which were told to stop may take a moment to exit.
+func WaitForGoroutines(t testing.TB, name string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	buffer := make([]byte, 1 << 20)
+	for {
+		running := 0
+		for _, stack := range strings.Split(string(buffer[:runtime.Stack(buffer, true)]), "\n\n") {
+			if strings.Contains(stack, name) {
+				running++
+			}
+		}
+		if running == 0 {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines running %s did not exit", running, name)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
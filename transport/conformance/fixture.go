@@ -0,0 +1,460 @@
+package conformance
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/block_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/events_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	transaction_receipt_pb2 "github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_receipt_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/signing"
+	"github.com/taekion-org/sawtooth-client-sdk-go/merkle"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+)
+
+// FIXTURE_BLOCKS is the number of blocks in the fixture chain (including the genesis block).
+const FIXTURE_BLOCKS = 13
+
+// FIXTURE_KEYS is the number of distinct state addresses written by the fixture chain.
+const FIXTURE_KEYS = 20
+
+// FIXTURE_NAMESPACE is the address prefix of all state written by the fixture chain.
+const FIXTURE_NAMESPACE = "1cf126"
+
+// FIXTURE_FAMILY_NAME is the transaction family of all transactions in the fixture chain.
+const FIXTURE_FAMILY_NAME = "intkey"
+
+// FIXTURE_EVENT_TYPE is the type of the event emitted by each fixture transaction.
+const FIXTURE_EVENT_TYPE = "intkey/set"
+
+// Fixture is a small, deterministic chain used to check transports against known data. Block n
+// is Blocks[n]; States[n] and StateRoots[n] hold the state after block n was applied.
+type Fixture struct {
+	Blocks			[]*block_pb2.Block
+	Headers			[]*block_pb2.BlockHeader
+	States			[]map[string][]byte
+	StateRoots		[]string
+
+	// PendingBatchId is the id of a batch that has been submitted but not yet committed.
+	PendingBatchId	string
+	// InvalidBatchId is the id of a batch that was rejected by the validator.
+	InvalidBatchId	string
+
+	// AutoCommit makes the validator commit submitted batches immediately, each list of batches
+	// in a new block (see Commit).
+	AutoCommit		bool
+
+	// SignerPublicKey is the public key of the signer of everything in the chain.
+	SignerPublicKey	string
+	// Signer signs everything in the chain. Its key is derived from a fixed seed.
+	Signer			*signing.Signer
+
+	batches			map[string]*batch_pb2.Batch
+	transactions	map[string]*transaction_pb2.Transaction
+	receipts		map[string]*transaction_receipt_pb2.TransactionReceipt
+	blockIndex		map[string]int
+}
+
+// NewFixture builds the fixture chain. Every call returns an identical chain.
+func NewFixture() *Fixture {
+	seed := sha256.Sum256([]byte("signer"))
+	privateKey := signing.NewSecp256k1PrivateKey(seed[:])
+	signer := signing.NewCryptoFactory(signing.NewSecp256k1Context()).NewSigner(privateKey)
+
+	fixture := &Fixture{
+		SignerPublicKey: signer.GetPublicKey().AsHex(),
+		Signer: signer,
+		batches: make(map[string]*batch_pb2.Batch),
+		transactions: make(map[string]*transaction_pb2.Transaction),
+		receipts: make(map[string]*transaction_receipt_pb2.TransactionReceipt),
+		blockIndex: make(map[string]int),
+	}
+
+	state := make(map[string][]byte)
+	tree := merkle.NewTree()
+	previousBlockId := transport.GENESIS_PREVIOUS_BLOCK_ID
+
+	for blockNum := 0; blockNum < FIXTURE_BLOCKS; blockNum++ {
+		var batches []*batch_pb2.Batch
+
+		// The genesis block is empty; other blocks hold one or two batches of one to three transactions
+		if blockNum > 0 {
+			for b := 0; b < 1 + blockNum % 2; b++ {
+				var transactions []*transaction_pb2.Transaction
+				for n := 0; n < 1 + (blockNum + b) % 3; n++ {
+					key := (blockNum * 7 + b * 3 + n) % FIXTURE_KEYS
+					value := []byte(fmt.Sprintf("%d:%d:%d", blockNum, b, n))
+					transaction := fixture.NewTransaction(key, value, fmt.Sprintf("%d-%d-%d", blockNum, b, n))
+
+					state[FixtureAddress(key)] = value
+					err := tree.Set(FixtureAddress(key), value)
+					if err != nil {
+						panic(err)
+					}
+					transactions = append(transactions, transaction)
+				}
+
+				batch := fixture.NewBatch(transactions)
+				batches = append(batches, batch)
+			}
+		}
+
+		// Snapshot the state after this block
+		blockState := make(map[string][]byte, len(state))
+		for address, value := range state {
+			blockState[address] = value
+		}
+		stateRoot := tree.RootHash()
+
+		header := &block_pb2.BlockHeader{
+			BlockNum: uint64(blockNum),
+			PreviousBlockId: previousBlockId,
+			SignerPublicKey: fixture.SignerPublicKey,
+			Consensus: []byte("Devmode"),
+			StateRootHash: stateRoot,
+		}
+		for _, batch := range batches {
+			header.BatchIds = append(header.BatchIds, batch.HeaderSignature)
+		}
+
+		headerBytes := mustMarshal(header)
+		block := &block_pb2.Block{
+			Header: headerBytes,
+			HeaderSignature: fixture.sign(headerBytes),
+			Batches: batches,
+		}
+
+		for _, batch := range batches {
+			fixture.addBatch(batch)
+		}
+
+		fixture.blockIndex[block.HeaderSignature] = blockNum
+		fixture.Blocks = append(fixture.Blocks, block)
+		fixture.Headers = append(fixture.Headers, header)
+		fixture.States = append(fixture.States, blockState)
+		fixture.StateRoots = append(fixture.StateRoots, stateRoot)
+
+		previousBlockId = block.HeaderSignature
+	}
+
+	pending := fixture.NewBatch([]*transaction_pb2.Transaction{fixture.NewTransaction(0, []byte("pending"), "pending")})
+	fixture.PendingBatchId = pending.HeaderSignature
+
+	invalid := fixture.NewBatch([]*transaction_pb2.Transaction{fixture.NewTransaction(1, []byte("invalid"), "invalid")})
+	fixture.InvalidBatchId = invalid.HeaderSignature
+
+	return fixture
+}
+
+// Fork replaces the blocks from blockNum onwards with count new blocks, as if the validator had
+// switched to another fork. Each new block holds a single transaction, which leaves state unchanged.
+func (self *Fixture) Fork(blockNum int, count int) {
+	for _, block := range self.Blocks[blockNum:] {
+		delete(self.blockIndex, block.HeaderSignature)
+		for _, batch := range block.Batches {
+			delete(self.batches, batch.HeaderSignature)
+			for _, transaction := range batch.Transactions {
+				delete(self.transactions, transaction.HeaderSignature)
+				delete(self.receipts, transaction.HeaderSignature)
+			}
+		}
+	}
+
+	self.Blocks = self.Blocks[:blockNum]
+	self.Headers = self.Headers[:blockNum]
+	self.States = self.States[:blockNum]
+	self.StateRoots = self.StateRoots[:blockNum]
+
+	for n := 0; n < count; n++ {
+		previous := len(self.Blocks) - 1
+		key := (previous * 7) % FIXTURE_KEYS
+		transaction := self.NewTransaction(key, self.States[previous][FixtureAddress(key)], fmt.Sprintf("fork-%d-%d", blockNum, n))
+		batch := self.NewBatch([]*transaction_pb2.Transaction{transaction})
+
+		header := &block_pb2.BlockHeader{
+			BlockNum: uint64(previous + 1),
+			PreviousBlockId: self.Blocks[previous].HeaderSignature,
+			SignerPublicKey: self.SignerPublicKey,
+			Consensus: []byte("Devmode"),
+			StateRootHash: self.StateRoots[previous],
+			BatchIds: []string{batch.HeaderSignature},
+		}
+		headerBytes := mustMarshal(header)
+		block := &block_pb2.Block{
+			Header: headerBytes,
+			HeaderSignature: self.sign(headerBytes),
+			Batches: []*batch_pb2.Batch{batch},
+		}
+
+		self.addBatch(batch)
+		self.blockIndex[block.HeaderSignature] = previous + 1
+		self.Blocks = append(self.Blocks, block)
+		self.Headers = append(self.Headers, header)
+		self.States = append(self.States, self.States[previous])
+		self.StateRoots = append(self.StateRoots, self.StateRoots[previous])
+	}
+}
+
+// Commit appends a block holding the given batches to the chain, as if the validator had committed
+// them. State is left unchanged, so the receipts of their transactions hold no state changes.
+func (self *Fixture) Commit(batches []*batch_pb2.Batch) {
+	self.commit(batches, nil)
+}
+
+// CommitStates appends an empty block to the chain which sets the given state values, as if
+// transactions of another client had changed them.
+func (self *Fixture) CommitStates(values map[string][]byte) {
+	self.commit(nil, values)
+}
+
+// commit appends a block holding batches, after which the state holds values.
+func (self *Fixture) commit(batches []*batch_pb2.Batch, values map[string][]byte) {
+	previous := len(self.Blocks) - 1
+
+	state, stateRoot := self.States[previous], self.StateRoots[previous]
+	if len(values) > 0 {
+		state = make(map[string][]byte, len(state) + len(values))
+		for address, value := range self.States[previous] {
+			state[address] = value
+		}
+		for address, value := range values {
+			state[address] = value
+		}
+
+		tree := merkle.NewTree()
+		for address, value := range state {
+			err := tree.Set(address, value)
+			if err != nil {
+				panic(err)
+			}
+		}
+		stateRoot = tree.RootHash()
+	}
+
+	header := &block_pb2.BlockHeader{
+		BlockNum: uint64(previous + 1),
+		PreviousBlockId: self.Blocks[previous].HeaderSignature,
+		SignerPublicKey: self.SignerPublicKey,
+		Consensus: []byte("Devmode"),
+		StateRootHash: stateRoot,
+	}
+	for _, batch := range batches {
+		header.BatchIds = append(header.BatchIds, batch.HeaderSignature)
+	}
+
+	headerBytes := mustMarshal(header)
+	block := &block_pb2.Block{
+		Header: headerBytes,
+		HeaderSignature: self.sign(headerBytes),
+		Batches: batches,
+	}
+
+	for _, batch := range batches {
+		self.batches[batch.HeaderSignature] = batch
+		for _, transaction := range batch.Transactions {
+			self.transactions[transaction.HeaderSignature] = transaction
+			self.receipts[transaction.HeaderSignature] = &transaction_receipt_pb2.TransactionReceipt{TransactionId: transaction.HeaderSignature}
+		}
+	}
+
+	self.blockIndex[block.HeaderSignature] = previous + 1
+	self.Blocks = append(self.Blocks, block)
+	self.Headers = append(self.Headers, header)
+	self.States = append(self.States, state)
+	self.StateRoots = append(self.StateRoots, stateRoot)
+}
+
+// addBatch records a batch of fixture transactions, and the receipts of its transactions.
+func (self *Fixture) addBatch(batch *batch_pb2.Batch) {
+	self.batches[batch.HeaderSignature] = batch
+	for _, transaction := range batch.Transactions {
+		self.transactions[transaction.HeaderSignature] = transaction
+		self.receipts[transaction.HeaderSignature] = self.newReceipt(transaction)
+	}
+}
+
+// newReceipt builds the receipt of a fixture transaction, which sets its address to the payload and
+// emits an event.
+func (self *Fixture) newReceipt(transaction *transaction_pb2.Transaction) *transaction_receipt_pb2.TransactionReceipt {
+	address := self.TransactionAddress(transaction)
+
+	return &transaction_receipt_pb2.TransactionReceipt{
+		TransactionId: transaction.HeaderSignature,
+		StateChanges: []*transaction_receipt_pb2.StateChange{
+			{Address: address, Value: transaction.Payload, Type: transaction_receipt_pb2.StateChange_SET},
+		},
+		Events: []*events_pb2.Event{
+			{
+				EventType: FIXTURE_EVENT_TYPE,
+				Attributes: []*events_pb2.Event_Attribute{{Key: "address", Value: address}},
+				Data: transaction.Payload,
+			},
+		},
+		Data: [][]byte{transaction.Payload},
+	}
+}
+
+// NewTransaction builds a transaction setting the fixture key to value. Transactions are
+// distinguished by nonce.
+func (self *Fixture) NewTransaction(key int, value []byte, nonce string) *transaction_pb2.Transaction {
+	address := FixtureAddress(key)
+	payloadHash := sha512.Sum512(value)
+
+	header := &transaction_pb2.TransactionHeader{
+		BatcherPublicKey: self.SignerPublicKey,
+		FamilyName: FIXTURE_FAMILY_NAME,
+		FamilyVersion: "1.0",
+		Inputs: []string{address},
+		Outputs: []string{address},
+		Nonce: nonce,
+		PayloadSha512: hex.EncodeToString(payloadHash[:]),
+		SignerPublicKey: self.SignerPublicKey,
+	}
+
+	headerBytes := mustMarshal(header)
+	return &transaction_pb2.Transaction{
+		Header: headerBytes,
+		HeaderSignature: self.sign(headerBytes),
+		Payload: value,
+	}
+}
+
+// TransactionAddress returns the address set by a fixture transaction.
+func (self *Fixture) TransactionAddress(transaction *transaction_pb2.Transaction) string {
+	var header transaction_pb2.TransactionHeader
+	err := proto.Unmarshal(transaction.Header, &header)
+	if err != nil {
+		panic(err)
+	}
+
+	return header.Outputs[0]
+}
+
+// NewBatch builds a batch holding the given transactions.
+func (self *Fixture) NewBatch(transactions []*transaction_pb2.Transaction) *batch_pb2.Batch {
+	header := &batch_pb2.BatchHeader{SignerPublicKey: self.SignerPublicKey}
+	for _, transaction := range transactions {
+		header.TransactionIds = append(header.TransactionIds, transaction.HeaderSignature)
+	}
+
+	headerBytes := mustMarshal(header)
+	return &batch_pb2.Batch{
+		Header: headerBytes,
+		HeaderSignature: self.sign(headerBytes),
+		Transactions: transactions,
+	}
+}
+
+// sign returns the hex encoded signature of a serialized header.
+func (self *Fixture) sign(headerBytes []byte) string {
+	return hex.EncodeToString(self.Signer.Sign(headerBytes))
+}
+
+// Head returns the block at the head of the fixture chain.
+func (self *Fixture) Head() *block_pb2.Block {
+	return self.Blocks[len(self.Blocks) - 1]
+}
+
+// BlockNum returns the number of the block with the given id, and false if there is none.
+func (self *Fixture) BlockNum(blockId string) (int, bool) {
+	blockNum, ok := self.blockIndex[blockId]
+	return blockNum, ok
+}
+
+// Batch returns the committed batch with the given id, or nil.
+func (self *Fixture) Batch(batchId string) *batch_pb2.Batch {
+	return self.batches[batchId]
+}
+
+// Transaction returns the committed transaction with the given id, or nil.
+func (self *Fixture) Transaction(transactionId string) *transaction_pb2.Transaction {
+	return self.transactions[transactionId]
+}
+
+// Receipt returns the receipt of the committed transaction with the given id, or nil.
+func (self *Fixture) Receipt(transactionId string) *transaction_receipt_pb2.TransactionReceipt {
+	return self.receipts[transactionId]
+}
+
+// StateRootBlockNum returns the number of the block with the given state root, and false if there is none.
+func (self *Fixture) StateRootBlockNum(stateRoot string) (int, bool) {
+	for blockNum, root := range self.StateRoots {
+		if root == stateRoot {
+			return blockNum, true
+		}
+	}
+
+	return 0, false
+}
+
+// BatchesAt returns the batches committed up to and including block head, newest block first.
+func (self *Fixture) BatchesAt(head int) []*batch_pb2.Batch {
+	var batches []*batch_pb2.Batch
+	for blockNum := head; blockNum >= 0; blockNum-- {
+		batches = append(batches, self.Blocks[blockNum].Batches...)
+	}
+
+	return batches
+}
+
+// TransactionsAt returns the transactions committed up to and including block head, newest block first.
+func (self *Fixture) TransactionsAt(head int) []*transaction_pb2.Transaction {
+	var transactions []*transaction_pb2.Transaction
+	for _, batch := range self.BatchesAt(head) {
+		transactions = append(transactions, batch.Transactions...)
+	}
+
+	return transactions
+}
+
+// AddressesAt returns the sorted addresses with the given prefix present in state after block head.
+func (self *Fixture) AddressesAt(head int, prefix string) []string {
+	var addresses []string
+	for address := range self.States[head] {
+		if strings.HasPrefix(address, prefix) {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Strings(addresses)
+
+	return addresses
+}
+
+// FixtureAddress returns the state address of a fixture key.
+func FixtureAddress(key int) string {
+	return FIXTURE_NAMESPACE + fixtureHash512([]byte(fmt.Sprintf("key-%d", key)))[:64]
+}
+
+// UnknownId returns a well-formed id that is not present in the fixture.
+func UnknownId(seed string) string {
+	return fixtureHash512([]byte(fmt.Sprintf("unknown-%s", seed)))
+}
+
+// fixtureHash256 returns the hex SHA-256 hash of a string.
+func fixtureHash256(data string) string {
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:])
+}
+
+// fixtureHash512 returns the hex SHA-512 hash of data, which has the form of a header signature.
+func fixtureHash512(data []byte) string {
+	hash := sha512.Sum512(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// mustMarshal marshals a protobuf which is known to be valid.
+func mustMarshal(message proto.Message) []byte {
+	data, err := proto.Marshal(message)
+	if err != nil {
+		panic(err)
+	}
+
+	return data
+}
@@ -0,0 +1,552 @@
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/block_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_batch_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_batch_submit_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_block_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_list_control_pb2"
+	client_peer "github.com/hyperledger/sawtooth-sdk-go/protobuf/client_peers_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_receipt_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_state_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_transaction_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	transaction_receipt_pb2 "github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_receipt_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/validator_pb2"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/errors"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/rest"
+)
+
+// restError is an error response of the REST API.
+type restError struct {
+	status	int
+	code	errors.SawtoothTransportErrorCode
+	title	string
+}
+
+// restErrors maps the validator response statuses shared by all requests to REST API errors.
+// NO_RESOURCE is mapped by each handler, as its error code depends on the resource.
+var restErrors = map[string]restError{
+	"INTERNAL_ERROR": {http.StatusInternalServerError, errors.VALIDATOR_UNKNOWN_ERROR, "Unknown Validator Error"},
+	"NOT_READY": {http.StatusServiceUnavailable, errors.VALIDATOR_NOT_READY, "Validator Not Ready"},
+	"NO_ROOT": {http.StatusNotFound, errors.INVALID_HEAD, "Head Not Found"},
+	"INVALID_ROOT": {http.StatusNotFound, errors.INVALID_HEAD, "Head Not Found"},
+	"INVALID_PAGING": {http.StatusBadRequest, errors.INVALID_PAGING_QUERY, "Invalid Paging Query"},
+	"INVALID_SORT": {http.StatusBadRequest, errors.INVALID_SORT_QUERY, "Invalid Sort Query"},
+	"INVALID_ID": {http.StatusBadRequest, errors.INVALID_RESOURCE_ID, "Invalid Resource Id"},
+	"INVALID_ADDRESS": {http.StatusBadRequest, errors.INVALID_STATE_ADDRESS, "Invalid State Address"},
+	"INVALID_BATCH": {http.StatusBadRequest, errors.BATCH_INVALID, "Submitted Batches Invalid"},
+	"QUEUE_FULL": {http.StatusTooManyRequests, errors.BATCH_UNABLE_TO_ACCEPT, "Unable to Accept Batches"},
+}
+
+// RestApi serves the Sawtooth REST API from a Validator, translating each HTTP request into
+// validator requests and their responses into JSON in the same way as the Sawtooth REST API.
+type RestApi struct {
+	Validator	*Validator
+}
+
+// NewRestApi returns a RestApi serving the given validator, for use with httptest.NewServer.
+func NewRestApi(validator *Validator) *RestApi {
+	return &RestApi{Validator: validator}
+}
+
+// NewRestTransport returns a REST transport connected to a test server running a RestApi for the
+// validator, for the tests of packages built on top of a transport. The server is closed when the
+// test completes.
+func NewRestTransport(t testing.TB, validator *Validator) transport.SawtoothClientTransport {
+	t.Helper()
+
+	server := httptest.NewServer(NewRestApi(validator))
+	t.Cleanup(server.Close)
+
+	serverUrl, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Error parsing server URL: %s", err)
+	}
+
+	client, err := rest.NewSawtoothClientTransportRest(serverUrl)
+	if err != nil {
+		t.Fatalf("Error creating transport: %s", err)
+	}
+
+	return client
+}
+
+// ServeHTTP implements http.Handler.
+func (self *RestApi) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	var err *restError
+	switch {
+	case r.Method == http.MethodGet && len(parts) == 1 && parts[0] == "peers":
+		err = self.getPeers(w, r)
+	case r.Method == http.MethodGet && len(parts) == 1 && parts[0] == "blocks":
+		err = self.listBlocks(w, r)
+	case r.Method == http.MethodGet && len(parts) == 2 && parts[0] == "blocks":
+		err = self.getBlock(w, r, parts[1])
+	case r.Method == http.MethodGet && len(parts) == 1 && parts[0] == "batches":
+		err = self.listBatches(w, r)
+	case r.Method == http.MethodGet && len(parts) == 2 && parts[0] == "batches":
+		err = self.getBatch(w, r, parts[1])
+	case r.Method == http.MethodPost && len(parts) == 1 && parts[0] == "batches":
+		err = self.submitBatches(w, r)
+	case r.Method == http.MethodGet && len(parts) == 1 && parts[0] == "transactions":
+		err = self.listTransactions(w, r)
+	case r.Method == http.MethodGet && len(parts) == 2 && parts[0] == "transactions":
+		err = self.getTransaction(w, r, parts[1])
+	case r.Method == http.MethodGet && len(parts) == 1 && parts[0] == "state":
+		err = self.listState(w, r)
+	case r.Method == http.MethodGet && len(parts) == 2 && parts[0] == "state":
+		err = self.getState(w, r, parts[1])
+	case r.Method == http.MethodPost && len(parts) == 1 && parts[0] == "batch_statuses":
+		err = self.batchStatuses(w, r)
+	case (r.Method == http.MethodGet || r.Method == http.MethodPost) && len(parts) == 1 && parts[0] == "receipts":
+		err = self.receipts(w, r)
+	default:
+		err = &restError{http.StatusNotFound, errors.UNKNOWN_ERROR, "Not Found"}
+	}
+
+	if err != nil {
+		writeJson(w, err.status, map[string]interface{}{
+			"error": map[string]interface{}{"code": err.code, "title": err.title, "message": err.title},
+		})
+	}
+}
+
+func (self *RestApi) getPeers(w http.ResponseWriter, r *http.Request) *restError {
+	request := &client_peer.ClientPeersGetRequest{}
+	var response client_peer.ClientPeersGetResponse
+	err := self.query(validator_pb2.Message_CLIENT_PEERS_GET_REQUEST, request, &response, errors.UNKNOWN_ERROR)
+	if err != nil {
+		return err
+	}
+
+	writeJson(w, http.StatusOK, map[string]interface{}{"data": response.Peers, "link": r.URL.String()})
+	return nil
+}
+
+func (self *RestApi) listBlocks(w http.ResponseWriter, r *http.Request) *restError {
+	paging, sorting, err := listControls(r, "block_num")
+	if err != nil {
+		return err
+	}
+
+	request := &client_block_pb2.ClientBlockListRequest{HeadId: r.URL.Query().Get("head"), Paging: paging, Sorting: sorting}
+	var response client_block_pb2.ClientBlockListResponse
+	err = self.query(validator_pb2.Message_CLIENT_BLOCK_LIST_REQUEST, request, &response, errors.BLOCK_NOT_FOUND)
+	if err != nil {
+		return err
+	}
+
+	data := make([]interface{}, len(response.Blocks))
+	for i, block := range response.Blocks {
+		data[i] = blockJson(block)
+	}
+
+	writeList(w, r, data, response.HeadId, response.Paging)
+	return nil
+}
+
+func (self *RestApi) getBlock(w http.ResponseWriter, r *http.Request, blockId string) *restError {
+	request := &client_block_pb2.ClientBlockGetByIdRequest{BlockId: blockId}
+	var response client_block_pb2.ClientBlockGetResponse
+	err := self.query(validator_pb2.Message_CLIENT_BLOCK_GET_BY_ID_REQUEST, request, &response, errors.BLOCK_NOT_FOUND)
+	if err != nil {
+		return err
+	}
+
+	writeJson(w, http.StatusOK, map[string]interface{}{"data": blockJson(response.Block), "link": r.URL.String()})
+	return nil
+}
+
+func (self *RestApi) listBatches(w http.ResponseWriter, r *http.Request) *restError {
+	paging, sorting, err := listControls(r, "default")
+	if err != nil {
+		return err
+	}
+
+	request := &client_batch_pb2.ClientBatchListRequest{HeadId: r.URL.Query().Get("head"), Paging: paging, Sorting: sorting}
+	var response client_batch_pb2.ClientBatchListResponse
+	err = self.query(validator_pb2.Message_CLIENT_BATCH_LIST_REQUEST, request, &response, errors.BATCH_NOT_FOUND)
+	if err != nil {
+		return err
+	}
+
+	data := make([]interface{}, len(response.Batches))
+	for i, batch := range response.Batches {
+		data[i] = batchJson(batch)
+	}
+
+	writeList(w, r, data, response.HeadId, response.Paging)
+	return nil
+}
+
+func (self *RestApi) getBatch(w http.ResponseWriter, r *http.Request, batchId string) *restError {
+	request := &client_batch_pb2.ClientBatchGetRequest{BatchId: batchId}
+	var response client_batch_pb2.ClientBatchGetResponse
+	err := self.query(validator_pb2.Message_CLIENT_BATCH_GET_REQUEST, request, &response, errors.BATCH_NOT_FOUND)
+	if err != nil {
+		return err
+	}
+
+	writeJson(w, http.StatusOK, map[string]interface{}{"data": batchJson(response.Batch), "link": r.URL.String()})
+	return nil
+}
+
+func (self *RestApi) submitBatches(w http.ResponseWriter, r *http.Request) *restError {
+	body, readErr := ioutil.ReadAll(r.Body)
+	if readErr != nil {
+		return &restError{http.StatusBadRequest, errors.BATCH_NONE_SUBMITTED, "No Batches Submitted"}
+	}
+
+	var batchList batch_pb2.BatchList
+	if proto.Unmarshal(body, &batchList) != nil {
+		return &restError{http.StatusBadRequest, errors.BATCH_PROTOBUF_NOT_DECODABLE, "Protobuf Not Decodable"}
+	}
+	if len(batchList.Batches) == 0 {
+		return &restError{http.StatusBadRequest, errors.BATCH_NONE_SUBMITTED, "No Batches Submitted"}
+	}
+
+	request := &client_batch_submit_pb2.ClientBatchSubmitRequest{Batches: batchList.Batches}
+	var response client_batch_submit_pb2.ClientBatchSubmitResponse
+	err := self.query(validator_pb2.Message_CLIENT_BATCH_SUBMIT_REQUEST, request, &response, errors.UNKNOWN_ERROR)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(batchList.Batches))
+	for i, batch := range batchList.Batches {
+		ids[i] = batch.HeaderSignature
+	}
+
+	writeJson(w, http.StatusAccepted, map[string]interface{}{"link": "/batch_statuses?id=" + strings.Join(ids, ",")})
+	return nil
+}
+
+func (self *RestApi) listTransactions(w http.ResponseWriter, r *http.Request) *restError {
+	paging, sorting, err := listControls(r, "default")
+	if err != nil {
+		return err
+	}
+
+	request := &client_transaction_pb2.ClientTransactionListRequest{HeadId: r.URL.Query().Get("head"), Paging: paging, Sorting: sorting}
+	var response client_transaction_pb2.ClientTransactionListResponse
+	err = self.query(validator_pb2.Message_CLIENT_TRANSACTION_LIST_REQUEST, request, &response, errors.TRANSACTION_NOT_FOUND)
+	if err != nil {
+		return err
+	}
+
+	data := make([]interface{}, len(response.Transactions))
+	for i, transaction := range response.Transactions {
+		data[i] = transactionJson(transaction)
+	}
+
+	writeList(w, r, data, response.HeadId, response.Paging)
+	return nil
+}
+
+func (self *RestApi) getTransaction(w http.ResponseWriter, r *http.Request, transactionId string) *restError {
+	request := &client_transaction_pb2.ClientTransactionGetRequest{TransactionId: transactionId}
+	var response client_transaction_pb2.ClientTransactionGetResponse
+	err := self.query(validator_pb2.Message_CLIENT_TRANSACTION_GET_REQUEST, request, &response, errors.TRANSACTION_NOT_FOUND)
+	if err != nil {
+		return err
+	}
+
+	writeJson(w, http.StatusOK, map[string]interface{}{"data": transactionJson(response.Transaction), "link": r.URL.String()})
+	return nil
+}
+
+func (self *RestApi) listState(w http.ResponseWriter, r *http.Request) *restError {
+	paging, sorting, err := listControls(r, "default")
+	if err != nil {
+		return err
+	}
+
+	head, stateRoot, err := self.resolveHead(r.URL.Query().Get("head"))
+	if err != nil {
+		return err
+	}
+
+	request := &client_state_pb2.ClientStateListRequest{StateRoot: stateRoot, Address: r.URL.Query().Get("address"), Paging: paging, Sorting: sorting}
+	var response client_state_pb2.ClientStateListResponse
+	err = self.query(validator_pb2.Message_CLIENT_STATE_LIST_REQUEST, request, &response, errors.STATE_NOT_FOUND)
+	if err != nil {
+		return err
+	}
+
+	data := make([]interface{}, len(response.Entries))
+	for i, entry := range response.Entries {
+		data[i] = map[string]interface{}{"address": entry.Address, "data": entry.Data}
+	}
+
+	writeList(w, r, data, head, response.Paging)
+	return nil
+}
+
+func (self *RestApi) getState(w http.ResponseWriter, r *http.Request, address string) *restError {
+	head, stateRoot, err := self.resolveHead(r.URL.Query().Get("head"))
+	if err != nil {
+		return err
+	}
+
+	request := &client_state_pb2.ClientStateGetRequest{StateRoot: stateRoot, Address: address}
+	var response client_state_pb2.ClientStateGetResponse
+	err = self.query(validator_pb2.Message_CLIENT_STATE_GET_REQUEST, request, &response, errors.STATE_NOT_FOUND)
+	if err != nil {
+		return err
+	}
+
+	writeJson(w, http.StatusOK, map[string]interface{}{"data": response.Value, "head": head, "link": r.URL.String()})
+	return nil
+}
+
+func (self *RestApi) batchStatuses(w http.ResponseWriter, r *http.Request) *restError {
+	var batchIds []string
+	body, readErr := ioutil.ReadAll(r.Body)
+	if readErr != nil || json.Unmarshal(body, &batchIds) != nil {
+		return &restError{http.StatusBadRequest, errors.BATCH_STATUS_UNAVAILABLE, "Bad Status Request"}
+	}
+
+	request := &client_batch_submit_pb2.ClientBatchStatusRequest{BatchIds: batchIds}
+	var response client_batch_submit_pb2.ClientBatchStatusResponse
+	err := self.query(validator_pb2.Message_CLIENT_BATCH_STATUS_REQUEST, request, &response, errors.BATCH_STATUS_UNAVAILABLE)
+	if err != nil {
+		return err
+	}
+
+	data := make([]interface{}, len(response.BatchStatuses))
+	for i, status := range response.BatchStatuses {
+		invalid := make([]interface{}, len(status.InvalidTransactions))
+		for j, transaction := range status.InvalidTransactions {
+			invalid[j] = map[string]interface{}{"id": transaction.TransactionId, "message": transaction.Message}
+		}
+		data[i] = map[string]interface{}{"id": status.BatchId, "status": status.Status.String(), "invalid_transactions": invalid}
+	}
+
+	writeJson(w, http.StatusOK, map[string]interface{}{"data": data, "link": r.URL.String()})
+	return nil
+}
+
+func (self *RestApi) receipts(w http.ResponseWriter, r *http.Request) *restError {
+	// Transaction ids are given in the query string, or posted as a JSON list
+	var transactionIds []string
+	if r.Method == http.MethodPost {
+		body, readErr := ioutil.ReadAll(r.Body)
+		if readErr != nil || json.Unmarshal(body, &transactionIds) != nil {
+			return &restError{http.StatusBadRequest, errors.INVALID_RESOURCE_ID, "Bad Receipts Request"}
+		}
+	} else if r.URL.Query().Get("id") != "" {
+		transactionIds = strings.Split(r.URL.Query().Get("id"), ",")
+	}
+	if len(transactionIds) == 0 {
+		return &restError{http.StatusBadRequest, errors.INVALID_RESOURCE_ID, "Bad Receipts Request"}
+	}
+
+	request := &client_receipt_pb2.ClientReceiptGetRequest{TransactionIds: transactionIds}
+	var response client_receipt_pb2.ClientReceiptGetResponse
+	err := self.query(validator_pb2.Message_CLIENT_RECEIPT_GET_REQUEST, request, &response, errors.TRANSACTION_RECEIPT_NOT_FOUND)
+	if err != nil {
+		return err
+	}
+
+	data := make([]interface{}, len(response.Receipts))
+	for i, receipt := range response.Receipts {
+		data[i] = receiptJson(receipt)
+	}
+
+	writeJson(w, http.StatusOK, map[string]interface{}{"data": data, "link": r.URL.String()})
+	return nil
+}
+
+// resolveHead returns the block id and state root to read state from. The REST API reads state by
+// block, so the head ("" for the chain head) is looked up first.
+func (self *RestApi) resolveHead(head string) (string, string, *restError) {
+	var block *block_pb2.Block
+	if head == "" {
+		request := &client_block_pb2.ClientBlockListRequest{Paging: &client_list_control_pb2.ClientPagingControls{Limit: 1}}
+		var response client_block_pb2.ClientBlockListResponse
+		err := self.query(validator_pb2.Message_CLIENT_BLOCK_LIST_REQUEST, request, &response, errors.BLOCK_NOT_FOUND)
+		if err != nil {
+			return "", "", err
+		}
+		block = response.Blocks[0]
+	} else {
+		request := &client_block_pb2.ClientBlockGetByIdRequest{BlockId: head}
+		var response client_block_pb2.ClientBlockGetResponse
+		err := self.query(validator_pb2.Message_CLIENT_BLOCK_GET_BY_ID_REQUEST, request, &response, errors.INVALID_HEAD)
+		if err != nil {
+			return "", "", &restError{http.StatusNotFound, errors.INVALID_HEAD, "Head Not Found"}
+		}
+		block = response.Block
+	}
+
+	var header block_pb2.BlockHeader
+	if proto.Unmarshal(block.Header, &header) != nil {
+		return "", "", &restError{http.StatusInternalServerError, errors.VALIDATOR_INVALID_RESPONSE, "Invalid Validator Response"}
+	}
+
+	return block.HeaderSignature, header.StateRootHash, nil
+}
+
+// query sends a request to the validator and decodes its response, returning the REST API error
+// for any error status. notFound is the error code used for NO_RESOURCE.
+func (self *RestApi) query(t validator_pb2.Message_MessageType, request proto.Message, response proto.Message, notFound errors.SawtoothTransportErrorCode) *restError {
+	content, err := proto.Marshal(request)
+	if err == nil {
+		_, content, err = self.Validator.Handle(t, content)
+	}
+	if err == nil {
+		err = proto.Unmarshal(content, response)
+	}
+	if err != nil {
+		return &restError{http.StatusInternalServerError, errors.VALIDATOR_INVALID_RESPONSE, "Invalid Validator Response"}
+	}
+
+	status := reflect.ValueOf(response).MethodByName("GetStatus").Call(nil)[0].Interface().(fmt.Stringer).String()
+	switch status {
+	case "OK":
+		return nil
+	case "NO_RESOURCE":
+		return &restError{http.StatusNotFound, notFound, "Not Found"}
+	}
+
+	restErr, ok := restErrors[status]
+	if !ok {
+		restErr = restError{http.StatusInternalServerError, errors.VALIDATOR_UNKNOWN_ERROR, "Unknown Validator Error"}
+	}
+
+	return &restErr
+}
+
+// listControls parses the paging and sorting query parameters of a list request.
+func listControls(r *http.Request, sortKey string) (*client_list_control_pb2.ClientPagingControls, []*client_list_control_pb2.ClientSortControls, *restError) {
+	query := r.URL.Query()
+
+	paging := &client_list_control_pb2.ClientPagingControls{Start: query.Get("start")}
+	if query.Get("limit") != "" {
+		limit, err := strconv.Atoi(query.Get("limit"))
+		if err != nil || limit <= 0 {
+			return nil, nil, &restError{http.StatusBadRequest, errors.INVALID_COUNT_QUERY, "Invalid Count Query"}
+		}
+		paging.Limit = int32(limit)
+	}
+
+	// As in the REST API, any value of "reverse" other than "false" reverses the order
+	_, reverse := query["reverse"]
+	if query.Get("reverse") == "false" {
+		reverse = false
+	}
+
+	sorting := []*client_list_control_pb2.ClientSortControls{{Keys: []string{sortKey}, Reverse: reverse}}
+
+	return paging, sorting, nil
+}
+
+// writeList writes the response to a list request, including the paging links.
+func writeList(w http.ResponseWriter, r *http.Request, data []interface{}, head string, paging *client_list_control_pb2.ClientPagingResponse) {
+	pagingJson := map[string]interface{}{"start": paging.GetStart(), "limit": paging.GetLimit()}
+
+	if paging.GetNext() != "" {
+		next := *r.URL
+		query := next.Query()
+		query.Set("head", head)
+		query.Set("start", paging.GetNext())
+		query.Set("limit", fmt.Sprintf("%d", paging.GetLimit()))
+		next.RawQuery = query.Encode()
+
+		nextUrl := url.URL{Scheme: "http", Host: r.Host, Path: next.Path, RawQuery: next.RawQuery}
+		pagingJson["next_position"] = paging.GetNext()
+		pagingJson["next"] = nextUrl.String()
+	}
+
+	writeJson(w, http.StatusOK, map[string]interface{}{"data": data, "head": head, "link": r.URL.String(), "paging": pagingJson})
+}
+
+// writeJson writes a JSON response.
+func writeJson(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// headerJson decodes a protobuf header and converts it to JSON in the same way as the REST API,
+// which includes default values and uses the protobuf field names.
+func headerJson(header []byte, message proto.Message) json.RawMessage {
+	err := proto.Unmarshal(header, message)
+	if err != nil {
+		return nil
+	}
+
+	marshaler := jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+	data, err := marshaler.MarshalToString(message)
+	if err != nil {
+		return nil
+	}
+
+	return json.RawMessage(data)
+}
+
+// blockJson converts a block to the form returned by the REST API.
+func blockJson(block *block_pb2.Block) map[string]interface{} {
+	batches := make([]interface{}, len(block.Batches))
+	for i, batch := range block.Batches {
+		batches[i] = batchJson(batch)
+	}
+
+	return map[string]interface{}{
+		"header": headerJson(block.Header, &block_pb2.BlockHeader{}),
+		"header_signature": block.HeaderSignature,
+		"batches": batches,
+	}
+}
+
+// batchJson converts a batch to the form returned by the REST API.
+func batchJson(batch *batch_pb2.Batch) map[string]interface{} {
+	transactions := make([]interface{}, len(batch.Transactions))
+	for i, transaction := range batch.Transactions {
+		transactions[i] = transactionJson(transaction)
+	}
+
+	return map[string]interface{}{
+		"header": headerJson(batch.Header, &batch_pb2.BatchHeader{}),
+		"header_signature": batch.HeaderSignature,
+		"trace": batch.Trace,
+		"transactions": transactions,
+	}
+}
+
+// transactionJson converts a transaction to the form returned by the REST API.
+func transactionJson(transaction *transaction_pb2.Transaction) map[string]interface{} {
+	return map[string]interface{}{
+		"header": headerJson(transaction.Header, &transaction_pb2.TransactionHeader{}),
+		"header_signature": transaction.HeaderSignature,
+		"payload": transaction.Payload,
+	}
+}
+
+// receiptJson converts a transaction receipt to the form returned by the REST API.
+func receiptJson(receipt *transaction_receipt_pb2.TransactionReceipt) json.RawMessage {
+	marshaler := jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+	data, err := marshaler.MarshalToString(receipt)
+	if err != nil {
+		return nil
+	}
+
+	return json.RawMessage(data)
+}
@@ -0,0 +1,669 @@
+package conformance
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/block_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/errors"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// cursorIterator is satisfied by all of the typed transport iterators.
+type cursorIterator[T any] interface {
+	types.CommonIterator
+	Current() (T, error)
+}
+
+// reversed returns a reversed copy of values.
+func reversed[T any](values []T) []T {
+	result := make([]T, len(values))
+	for i, value := range values {
+		result[len(values) - 1 - i] = value
+	}
+
+	return result
+}
+
+// expectedBlocks converts fixture blocks into the values a transport should return.
+func expectedBlocks(blocks []*block_pb2.Block) []*types.Block {
+	result := make([]*types.Block, len(blocks))
+	for i, block := range blocks {
+		result[i], _ = types.BlockFromProto(block)
+	}
+
+	return result
+}
+
+// expectedBatches converts fixture batches into the values a transport should return.
+func expectedBatches(batches []*batch_pb2.Batch) []*types.Batch {
+	result := make([]*types.Batch, len(batches))
+	for i, batch := range batches {
+		result[i], _ = types.BatchFromProto(batch)
+	}
+
+	return result
+}
+
+// expectedTransactions converts fixture transactions into the values a transport should return.
+func expectedTransactions(transactions []*transaction_pb2.Transaction) []*types.Transaction {
+	result := make([]*types.Transaction, len(transactions))
+	for i, transaction := range transactions {
+		result[i], _ = types.TransactionFromProto(transaction)
+	}
+
+	return result
+}
+
+func testGetBlock(t *testing.T, fixture *Fixture, transport transport.SawtoothClientTransport) {
+	expected := expectedBlocks(fixture.Blocks)
+	for i, block := range fixture.Blocks {
+		got, err := transport.GetBlock(block.HeaderSignature)
+		if err != nil {
+			t.Fatalf("GetBlock(%d): %s", i, err)
+		}
+		checkValues(t, fmt.Sprintf("GetBlock(%d)", i), []*types.Block{got}, expected[i:i + 1])
+	}
+
+	_, err := transport.GetBlock(UnknownId("block"))
+	checkErrorCode(t, "GetBlock(unknown)", err, errors.BLOCK_NOT_FOUND)
+
+	_, err = transport.GetBlock("not-a-block-id")
+	checkErrorCode(t, "GetBlock(invalid)", err, errors.INVALID_RESOURCE_ID)
+}
+
+func testBlockIterator(t *testing.T, fixture *Fixture, transport transport.SawtoothClientTransport) {
+	// Blocks are returned newest first unless reversed
+	expected := reversed(expectedBlocks(fixture.Blocks))
+
+	for _, fetch := range pageSizes(len(expected)) {
+		got := collect(t, types.NewIterator(transport.GetBlockIterator(fetch, false)))
+		checkValues(t, fmt.Sprintf("GetBlockIterator(%d, false)", fetch), got, expected)
+
+		got = collect(t, types.NewIterator(transport.GetBlockIterator(fetch, true)))
+		checkValues(t, fmt.Sprintf("GetBlockIterator(%d, true)", fetch), got, reversed(expected))
+	}
+}
+
+func testBlockRangeIterator(t *testing.T, fixture *Fixture, transport transport.SawtoothClientTransport) {
+	blocks := expectedBlocks(fixture.Blocks)
+	last := uint64(len(blocks) - 1)
+
+	ranges := [][2]uint64{{2, 7}, {0, last}, {5, 5}, {last - 1, last}}
+	for _, r := range ranges {
+		expected := blocks[r[0]:r[1] + 1]
+		for _, fetch := range []int{1, 3, 0} {
+			got := collect(t, types.NewIterator(transport.GetBlockRangeIterator(r[0], r[1], fetch, true)))
+			checkValues(t, fmt.Sprintf("GetBlockRangeIterator(%d, %d, %d, true)", r[0], r[1], fetch), got, expected)
+
+			got = collect(t, types.NewIterator(transport.GetBlockRangeIterator(r[0], r[1], fetch, false)))
+			checkValues(t, fmt.Sprintf("GetBlockRangeIterator(%d, %d, %d, false)", r[0], r[1], fetch), got, reversed(expected))
+		}
+	}
+
+	// Ranges extending past the head end at the head
+	got := collect(t, types.NewIterator(transport.GetBlockRangeIterator(last - 2, last + 5, 0, false)))
+	checkValues(t, "GetBlockRangeIterator(past head, false)", got, reversed(blocks[last - 2:]))
+	got = collect(t, types.NewIterator(transport.GetBlockRangeIterator(last - 2, last + 5, 0, true)))
+	checkValues(t, "GetBlockRangeIterator(past head, true)", got, blocks[last - 2:])
+	for _, reverse := range []bool{false, true} {
+		iterator := transport.GetBlockRangeIterator(last + 1, last + 5, 0, reverse)
+		if iterator.Next() || iterator.Error() != nil {
+			t.Fatalf("GetBlockRangeIterator(after head, %t): expected no blocks, got: %v", reverse, iterator.Error())
+		}
+	}
+
+	iterator := transport.GetBlockRangeIterator(7, 2, 0, true)
+	if iterator.Next() || iterator.Error() == nil {
+		t.Fatalf("GetBlockRangeIterator(7, 2): expected an error")
+	}
+}
+
+func testBlockIteratorSince(t *testing.T, fixture *Fixture, transport transport.SawtoothClientTransport) {
+	blocks := expectedBlocks(fixture.Blocks)
+
+	for _, since := range []int{0, 5, len(blocks) - 2} {
+		got := collect(t, types.NewIterator(transport.GetBlockIteratorSince(fixture.Blocks[since].HeaderSignature, 2)))
+		checkValues(t, fmt.Sprintf("GetBlockIteratorSince(%d)", since), got, blocks[since + 1:])
+	}
+
+	// There are no blocks after the head
+	iterator := transport.GetBlockIteratorSince(fixture.Head().HeaderSignature, 0)
+	if iterator.Next() {
+		t.Fatalf("GetBlockIteratorSince(head): expected no blocks")
+	}
+	if iterator.Error() != nil {
+		t.Fatalf("GetBlockIteratorSince(head): %s", iterator.Error())
+	}
+
+	iterator = transport.GetBlockIteratorSince(UnknownId("since"), 0)
+	if iterator.Next() {
+		t.Fatalf("GetBlockIteratorSince(unknown): expected no blocks")
+	}
+	checkErrorCode(t, "GetBlockIteratorSince(unknown)", iterator.Error(), errors.BLOCK_NOT_FOUND)
+}
+
+func testBlockCursorSince(t *testing.T, fixture *Fixture, transport transport.SawtoothClientTransport) {
+	// checkResume resumes an iterator from its cursor, after a block is committed
+	checkResume := func(description string, iterator types.BlockIterator, expected int) {
+		t.Helper()
+
+		cursor, err := iterator.Cursor()
+		if err != nil {
+			t.Fatalf("%s: Cursor: %s", description, err)
+		}
+		fixture.Commit(nil)
+
+		got := collect(t, types.NewIterator(transport.GetBlockIteratorFromCursor(cursor)))
+		checkValues(t, description, got, expectedBlocks(fixture.Blocks[expected:]))
+	}
+
+	// Resuming after reading all blocks returns those committed since
+	for _, fetch := range []int{1, 3, 0} {
+		since := len(fixture.Blocks) - 4
+		iterator := transport.GetBlockIteratorSince(fixture.Blocks[since].HeaderSignature, fetch)
+		got := collect(t, types.NewIterator(iterator))
+		checkValues(t, fmt.Sprintf("GetBlockIteratorSince(%d)", fetch), got, expectedBlocks(fixture.Blocks[since + 1:]))
+
+		checkResume(fmt.Sprintf("GetBlockIteratorSince(%d) resumed", fetch), iterator, len(fixture.Blocks))
+	}
+
+	// Resuming before a block is committed returns nothing, and the cursor can be resumed again
+	iterator := transport.GetBlockIteratorSince(fixture.Head().HeaderSignature, 0)
+	cursor, err := iterator.Cursor()
+	if err != nil {
+		t.Fatalf("GetBlockIteratorSince(head): Cursor: %s", err)
+	}
+	resumed := transport.GetBlockIteratorFromCursor(cursor)
+	if resumed.Next() || resumed.Error() != nil {
+		t.Fatalf("GetBlockIteratorSince(head) resumed: expected no blocks, got: %v", resumed.Error())
+	}
+	checkResume("GetBlockIteratorSince(head) resumed", resumed, len(fixture.Blocks))
+}
+
+func testGetBatch(t *testing.T, fixture *Fixture, transport transport.SawtoothClientTransport) {
+	batches := fixture.BatchesAt(len(fixture.Blocks) - 1)
+	expected := expectedBatches(batches)
+	for i, batch := range batches {
+		got, err := transport.GetBatch(batch.HeaderSignature)
+		if err != nil {
+			t.Fatalf("GetBatch(%d): %s", i, err)
+		}
+		checkValues(t, fmt.Sprintf("GetBatch(%d)", i), []*types.Batch{got}, expected[i:i + 1])
+	}
+
+	// A batch that is pending is not yet available
+	_, err := transport.GetBatch(fixture.PendingBatchId)
+	checkErrorCode(t, "GetBatch(pending)", err, errors.BATCH_NOT_FOUND)
+
+	_, err = transport.GetBatch(UnknownId("batch"))
+	checkErrorCode(t, "GetBatch(unknown)", err, errors.BATCH_NOT_FOUND)
+
+	_, err = transport.GetBatch("not-a-batch-id")
+	checkErrorCode(t, "GetBatch(invalid)", err, errors.INVALID_RESOURCE_ID)
+}
+
+func testBatchIterator(t *testing.T, fixture *Fixture, transport transport.SawtoothClientTransport) {
+	expected := expectedBatches(fixture.BatchesAt(len(fixture.Blocks) - 1))
+
+	for _, fetch := range pageSizes(len(expected)) {
+		got := collect(t, types.NewIterator(transport.GetBatchIterator(fetch, false)))
+		checkValues(t, fmt.Sprintf("GetBatchIterator(%d, false)", fetch), got, expected)
+
+		got = collect(t, types.NewIterator(transport.GetBatchIterator(fetch, true)))
+		checkValues(t, fmt.Sprintf("GetBatchIterator(%d, true)", fetch), got, reversed(expected))
+	}
+}
+
+func testGetTransaction(t *testing.T, fixture *Fixture, transport transport.SawtoothClientTransport) {
+	transactions := fixture.TransactionsAt(len(fixture.Blocks) - 1)
+	expected := expectedTransactions(transactions)
+	for i, transaction := range transactions {
+		got, err := transport.GetTransaction(transaction.HeaderSignature)
+		if err != nil {
+			t.Fatalf("GetTransaction(%d): %s", i, err)
+		}
+		checkValues(t, fmt.Sprintf("GetTransaction(%d)", i), []*types.Transaction{got}, expected[i:i + 1])
+	}
+
+	_, err := transport.GetTransaction(UnknownId("transaction"))
+	checkErrorCode(t, "GetTransaction(unknown)", err, errors.TRANSACTION_NOT_FOUND)
+
+	_, err = transport.GetTransaction("not-a-transaction-id")
+	checkErrorCode(t, "GetTransaction(invalid)", err, errors.INVALID_RESOURCE_ID)
+}
+
+func testTransactionIterator(t *testing.T, fixture *Fixture, transport transport.SawtoothClientTransport) {
+	expected := expectedTransactions(fixture.TransactionsAt(len(fixture.Blocks) - 1))
+
+	for _, fetch := range pageSizes(len(expected)) {
+		got := collect(t, types.NewIterator(transport.GetTransactionIterator(fetch, false)))
+		checkValues(t, fmt.Sprintf("GetTransactionIterator(%d, false)", fetch), got, expected)
+
+		got = collect(t, types.NewIterator(transport.GetTransactionIterator(fetch, true)))
+		checkValues(t, fmt.Sprintf("GetTransactionIterator(%d, true)", fetch), got, reversed(expected))
+	}
+}
+
+// checkCursorResume reads count values from iterator, then resumes from its cursor (after
+// encoding and decoding it) and checks that together they return the expected values.
+func testPrefetch(t *testing.T, fixture *Fixture, transport transport.SawtoothClientTransport) {
+	head := len(fixture.Blocks) - 1
+	blocks := reversed(expectedBlocks(fixture.Blocks))
+	transactions := expectedTransactions(fixture.TransactionsAt(head))
+
+	// Pages read ahead are returned complete and in order
+	for _, depth := range []int{1, 4} {
+		for _, fetch := range []int{1, 3, 0} {
+			description := fmt.Sprintf("(fetch=%d, prefetch=%d)", fetch, depth)
+
+			blockIterator := transport.GetBlockIterator(fetch, false)
+			blockIterator.SetPrefetch(depth)
+			checkValues(t, "GetBlockIterator" + description, collect(t, types.NewIterator(blockIterator)), blocks)
+
+			transactionIterator := transport.GetTransactionIterator(fetch, true)
+			transactionIterator.SetPrefetch(depth)
+			checkValues(t, "GetTransactionIterator" + description, collect(t, types.NewIterator(transactionIterator)), reversed(transactions))
+		}
+	}
+
+	// Closing an iterator part way through stops the pages being read ahead
+	for _, depth := range []int{1, 4} {
+		iterator := transport.GetBlockIterator(1, false)
+		iterator.SetPrefetch(depth)
+		for i := 0; i < 2; i++ {
+			if !iterator.Next() {
+				t.Fatalf("GetBlockIterator(prefetch=%d): %v", depth, iterator.Error())
+			}
+		}
+		iterator.Close()
+		if iterator.Next() {
+			t.Fatalf("GetBlockIterator(prefetch=%d): got a value after Close", depth)
+		}
+	}
+	WaitForGoroutines(t, "startPrefetch")
+
+	// Errors are reported when the page that failed is reached
+	iterator := transport.GetStateIteratorAtHead(FIXTURE_NAMESPACE, UnknownId("head"), 0, false)
+	iterator.SetPrefetch(2)
+	if iterator.Next() {
+		t.Fatalf("GetStateIteratorAtHead(unknown head, prefetch): expected no values")
+	}
+	checkErrorCode(t, "GetStateIteratorAtHead(unknown head, prefetch)", iterator.Error(), errors.INVALID_HEAD)
+}
+
+func checkCursorResume[T any](t *testing.T, description string, iterator cursorIterator[*T], resume func(*types.Cursor) cursorIterator[*T], count int, expected []*T) {
+	t.Helper()
+
+	var got []*T
+	for len(got) < count && iterator.Next() {
+		value, err := iterator.Current()
+		if err != nil {
+			t.Fatalf("%s: %s", description, err)
+		}
+		got = append(got, value)
+	}
+
+	cursor, err := iterator.Cursor()
+	if err != nil {
+		t.Fatalf("%s: Cursor(): %s", description, err)
+	}
+	iterator.Close()
+
+	encoded, err := cursor.Encode()
+	if err != nil {
+		t.Fatalf("%s: Encode(): %s", description, err)
+	}
+	cursor, err = types.DecodeCursor(encoded)
+	if err != nil {
+		t.Fatalf("%s: DecodeCursor(): %s", description, err)
+	}
+
+	resumed := resume(cursor)
+	for resumed.Next() {
+		value, err := resumed.Current()
+		if err != nil {
+			t.Fatalf("%s: %s", description, err)
+		}
+		got = append(got, value)
+	}
+	if resumed.Error() != nil {
+		t.Fatalf("%s: resumed iteration failed: %s", description, resumed.Error())
+	}
+
+	checkValues(t, description, got, expected)
+}
+
+func testIteratorCursor(t *testing.T, fixture *Fixture, transport transport.SawtoothClientTransport) {
+	head := len(fixture.Blocks) - 1
+	blocks := reversed(expectedBlocks(fixture.Blocks))
+	batches := expectedBatches(fixture.BatchesAt(head))
+	transactions := expectedTransactions(fixture.TransactionsAt(head))
+
+	var states []*types.State
+	for _, address := range fixture.AddressesAt(head, FIXTURE_NAMESPACE) {
+		states = append(states, &types.State{Address: address, Data: fixture.States[head][address], Head: fixture.Head().HeaderSignature})
+	}
+
+	// Stop part way through a page, at the end of a page and after the last value
+	for _, count := range []int{4, 6, 1000} {
+		for _, fetch := range []int{3, 0} {
+			description := fmt.Sprintf("(fetch=%d, count=%d)", fetch, count)
+
+			checkCursorResume[types.Block](t, "blocks " + description,
+				transport.GetBlockIterator(fetch, false),
+				func(cursor *types.Cursor) cursorIterator[*types.Block] { return transport.GetBlockIteratorFromCursor(cursor) },
+				count, blocks)
+
+			checkCursorResume[types.Batch](t, "batches " + description,
+				transport.GetBatchIterator(fetch, true),
+				func(cursor *types.Cursor) cursorIterator[*types.Batch] { return transport.GetBatchIteratorFromCursor(cursor) },
+				count, reversed(batches))
+
+			checkCursorResume[types.Transaction](t, "transactions " + description,
+				transport.GetTransactionIterator(fetch, false),
+				func(cursor *types.Cursor) cursorIterator[*types.Transaction] { return transport.GetTransactionIteratorFromCursor(cursor) },
+				count, transactions)
+
+			checkCursorResume[types.State](t, "state " + description,
+				transport.GetStateIterator(FIXTURE_NAMESPACE, fetch, false),
+				func(cursor *types.Cursor) cursorIterator[*types.State] { return transport.GetStateIteratorFromCursor(cursor) },
+				count, states)
+		}
+	}
+
+	// A cursor pins the head, so a resumed iteration is not affected by later blocks
+	checkCursorResume[types.Block](t, "block range",
+		transport.GetBlockRangeIterator(3, 9, 2, true),
+		func(cursor *types.Cursor) cursorIterator[*types.Block] { return transport.GetBlockIteratorFromCursor(cursor) },
+		3, reversed(blocks)[3:10])
+
+	// A cursor cannot be used for a different kind of iterator
+	cursor := types.NewCursor(types.CURSOR_BLOCKS, 0, false)
+	iterator := transport.GetStateIteratorFromCursor(cursor)
+	if iterator.Next() || iterator.Error() == nil {
+		t.Fatalf("GetStateIteratorFromCursor(blocks cursor): expected an error")
+	}
+}
+
+func testGetState(t *testing.T, fixture *Fixture, transport transport.SawtoothClientTransport) {
+	head := len(fixture.Blocks) - 1
+	headId := fixture.Head().HeaderSignature
+
+	for _, address := range fixture.AddressesAt(head, "") {
+		state, err := transport.GetState(address)
+		if err != nil {
+			t.Fatalf("GetState(%s): %s", address, err)
+		}
+		checkState(t, "GetState", state, address, fixture.States[head][address], headId)
+	}
+
+	// Read the state as it was at an earlier block
+	past := 4
+	pastId := fixture.Blocks[past].HeaderSignature
+	for _, address := range fixture.AddressesAt(head, "") {
+		value, ok := fixture.States[past][address]
+
+		state, err := transport.GetStateAtHead(address, pastId)
+		if !ok {
+			checkErrorCode(t, "GetStateAtHead(absent)", err, errors.STATE_NOT_FOUND)
+			continue
+		}
+		if err != nil {
+			t.Fatalf("GetStateAtHead(%s): %s", address, err)
+		}
+		checkState(t, "GetStateAtHead", state, address, value, pastId)
+
+		// The head reported for a state root read is transport specific, so it is not checked
+		state, err = transport.GetStateAtRoot(address, fixture.StateRoots[past])
+		if err != nil {
+			t.Fatalf("GetStateAtRoot(%s): %s", address, err)
+		}
+		checkState(t, "GetStateAtRoot", state, address, value, "")
+	}
+
+	_, err := transport.GetState(FixtureAddress(FIXTURE_KEYS))
+	checkErrorCode(t, "GetState(absent)", err, errors.STATE_NOT_FOUND)
+
+	_, err = transport.GetState("not-an-address")
+	checkErrorCode(t, "GetState(invalid)", err, errors.INVALID_STATE_ADDRESS)
+
+	_, err = transport.GetStateAtHead(FixtureAddress(0), UnknownId("head"))
+	checkErrorCode(t, "GetStateAtHead(unknown head)", err, errors.INVALID_HEAD)
+
+	_, err = transport.GetStateAtHead(FixtureAddress(0), "not-a-head")
+	checkErrorCode(t, "GetStateAtHead(invalid head)", err, errors.INVALID_HEAD)
+
+	_, err = transport.GetStateAtRoot(FixtureAddress(0), fixtureHash256("unknown"))
+	checkErrorCode(t, "GetStateAtRoot(unknown root)", err, errors.INVALID_HEAD)
+}
+
+func testGetStates(t *testing.T, fixture *Fixture, transport transport.SawtoothClientTransport) {
+	absent := FixtureAddress(FIXTURE_KEYS)
+
+	for _, head := range []int{len(fixture.Blocks) - 1, 4} {
+		headId := fixture.Blocks[head].HeaderSignature
+		addresses := append(fixture.AddressesAt(len(fixture.Blocks) - 1, ""), absent)
+
+		var states map[string]*types.State
+		var err error
+		if head == len(fixture.Blocks) - 1 {
+			states, err = transport.GetStates(addresses)
+		} else {
+			states, err = transport.GetStatesAtHead(addresses, headId)
+		}
+		if err != nil {
+			t.Fatalf("GetStates(head=%d): %s", head, err)
+		}
+
+		if len(states) != len(addresses) {
+			t.Fatalf("GetStates(head=%d): got %d results, expected %d", head, len(states), len(addresses))
+		}
+
+		for _, address := range addresses {
+			state, present := states[address]
+			value, ok := fixture.States[head][address]
+			if !present {
+				t.Fatalf("GetStates(head=%d): no result for %s", head, address)
+			}
+			if !ok {
+				if state != nil {
+					t.Fatalf("GetStates(head=%d): expected nil for absent address %s", head, address)
+				}
+				continue
+			}
+			checkState(t, fmt.Sprintf("GetStates(head=%d)", head), state, address, value, headId)
+		}
+	}
+
+	_, err := transport.GetStatesAtHead([]string{absent}, UnknownId("head"))
+	checkErrorCode(t, "GetStatesAtHead(unknown head)", err, errors.INVALID_HEAD)
+}
+
+func testStateIterator(t *testing.T, fixture *Fixture, transport transport.SawtoothClientTransport) {
+	head := len(fixture.Blocks) - 1
+	past := 4
+	narrow := fixture.AddressesAt(head, FIXTURE_NAMESPACE)[0][:len(FIXTURE_NAMESPACE) + 2]
+
+	for _, prefix := range []string{"", FIXTURE_NAMESPACE, narrow, FixtureAddress(FIXTURE_KEYS)} {
+		for _, reverse := range []bool{false, true} {
+			for _, block := range []int{head, past} {
+				blockId := fixture.Blocks[block].HeaderSignature
+
+				var expected []*types.State
+				for _, address := range fixture.AddressesAt(block, prefix) {
+					expected = append(expected, &types.State{Address: address, Data: fixture.States[block][address]})
+				}
+				if reverse {
+					expected = reversed(expected)
+				}
+
+				for _, fetch := range pageSizes(len(expected)) {
+					description := fmt.Sprintf("(%q, block=%d, fetch=%d, reverse=%t)", prefix, block, fetch, reverse)
+
+					var iterators map[string]types.StateIterator
+					if block == head {
+						iterators = map[string]types.StateIterator{
+							"GetStateIterator": transport.GetStateIterator(prefix, fetch, reverse),
+						}
+					} else {
+						iterators = map[string]types.StateIterator{
+							"GetStateIteratorAtHead": transport.GetStateIteratorAtHead(prefix, blockId, fetch, reverse),
+							"GetStateIteratorAtRoot": transport.GetStateIteratorAtRoot(prefix, fixture.StateRoots[block], fetch, reverse),
+						}
+					}
+
+					for name, iterator := range iterators {
+						got := collect(t, types.NewIterator(iterator))
+						if len(got) != len(expected) {
+							t.Fatalf("%s%s: got %d values, expected %d", name, description, len(got), len(expected))
+						}
+
+						// The head reported for a state root read is transport specific, so it is not checked
+						checkHead := blockId
+						if name == "GetStateIteratorAtRoot" {
+							checkHead = ""
+						}
+						for i := range expected {
+							checkState(t, name + description, got[i], expected[i].Address, expected[i].Data, checkHead)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	iterator := transport.GetStateIterator("not-a-prefix", 0, false)
+	if iterator.Next() {
+		t.Fatalf("GetStateIterator(invalid): expected no values")
+	}
+	checkErrorCode(t, "GetStateIterator(invalid)", iterator.Error(), errors.INVALID_STATE_ADDRESS)
+
+	// Prefixes are made of whole two-character tokens
+	iterator = transport.GetStateIterator(FIXTURE_NAMESPACE + "0", 0, false)
+	if iterator.Next() {
+		t.Fatalf("GetStateIterator(odd prefix): expected no values")
+	}
+	checkErrorCode(t, "GetStateIterator(odd prefix)", iterator.Error(), errors.INVALID_STATE_ADDRESS)
+
+	iterator = transport.GetStateIteratorAtHead(FIXTURE_NAMESPACE, UnknownId("head"), 0, false)
+	if iterator.Next() {
+		t.Fatalf("GetStateIteratorAtHead(unknown head): expected no values")
+	}
+	checkErrorCode(t, "GetStateIteratorAtHead(unknown head)", iterator.Error(), errors.INVALID_HEAD)
+}
+
+func testBatchStatus(t *testing.T, fixture *Fixture, transport transport.SawtoothClientTransport) {
+	committed := fixture.Head().Batches[0].HeaderSignature
+	unknown := UnknownId("status")
+
+	expected := map[string]types.BatchStatus{
+		committed: types.BATCH_STATUS_COMMITTED,
+		fixture.PendingBatchId: types.BATCH_STATUS_PENDING,
+		fixture.InvalidBatchId: types.BATCH_STATUS_INVALID,
+		unknown: types.BATCH_STATUS_UNKNOWN,
+	}
+
+	for batchId, status := range expected {
+		got, err := transport.GetBatchStatus(batchId, 0)
+		if err != nil {
+			t.Fatalf("GetBatchStatus(%s): %s", status, err)
+		}
+		if got != status {
+			t.Fatalf("GetBatchStatus(%s): got %s", status, got)
+		}
+	}
+
+	batchIds := []string{committed, fixture.PendingBatchId, fixture.InvalidBatchId, unknown}
+	got, err := transport.GetBatchStatusMultiple(batchIds, 1)
+	if err != nil {
+		t.Fatalf("GetBatchStatusMultiple: %s", err)
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("GetBatchStatusMultiple: got %d results, expected %d", len(got), len(expected))
+	}
+	for batchId, status := range expected {
+		if got[batchId] != status {
+			t.Fatalf("GetBatchStatusMultiple: got %s, expected %s", got[batchId], status)
+		}
+	}
+
+	_, err = transport.GetBatchStatus("not-a-batch-id", 0)
+	checkErrorCode(t, "GetBatchStatus(invalid)", err, errors.INVALID_RESOURCE_ID)
+}
+
+func testSubmitBatchList(t *testing.T, fixture *Fixture, transport transport.SawtoothClientTransport) {
+	transaction := fixture.NewTransaction(2, []byte("submitted"), "submitted")
+	batch := fixture.NewBatch([]*transaction_pb2.Transaction{transaction})
+
+	err := transport.SubmitBatchList(&batch_pb2.BatchList{Batches: []*batch_pb2.Batch{batch}})
+	if err != nil {
+		t.Fatalf("SubmitBatchList: %s", err)
+	}
+
+	status, err := transport.GetBatchStatus(batch.HeaderSignature, 0)
+	if err != nil {
+		t.Fatalf("GetBatchStatus(submitted): %s", err)
+	}
+	if status != types.BATCH_STATUS_PENDING {
+		t.Fatalf("GetBatchStatus(submitted): got %s, expected %s", status, types.BATCH_STATUS_PENDING)
+	}
+
+	invalid := fixture.NewBatch([]*transaction_pb2.Transaction{transaction})
+	invalid.Header = []byte{0xff}
+
+	err = transport.SubmitBatchList(&batch_pb2.BatchList{Batches: []*batch_pb2.Batch{invalid}})
+	checkErrorCode(t, "SubmitBatchList(invalid)", err, errors.BATCH_INVALID)
+}
+
+func testTransactionReceipts(t *testing.T, fixture *Fixture, transport transport.SawtoothClientTransport) {
+	var transactionIds []string
+	var expected []*types.TransactionReceipt
+	for _, batch := range fixture.Head().Batches {
+		for _, transaction := range batch.Transactions {
+			transactionIds = append(transactionIds, transaction.HeaderSignature)
+			expected = append(expected, types.TransactionReceiptFromProto(fixture.Receipt(transaction.HeaderSignature)))
+		}
+	}
+
+	var got []*types.TransactionReceipt
+	for _, transactionId := range transactionIds {
+		receipt, err := transport.GetTransactionReceipt(transactionId)
+		if err != nil {
+			t.Fatalf("GetTransactionReceipt: %s", err)
+		}
+		got = append(got, receipt)
+	}
+	checkValues(t, "GetTransactionReceipt", got, expected)
+
+	// Receipts hold the state changes and events of their transactions
+	transaction := fixture.Transaction(transactionIds[0])
+	change := got[0].StateChanges[0]
+	if change.Type != types.STATE_CHANGE_SET || change.Address != fixture.TransactionAddress(transaction) || !bytes.Equal(change.Value, transaction.Payload) {
+		t.Fatalf("GetTransactionReceipt: unexpected state change %+v", change)
+	}
+	if got[0].Events[0].EventType != FIXTURE_EVENT_TYPE || got[0].Events[0].Attributes[0].Value != change.Address {
+		t.Fatalf("GetTransactionReceipt: unexpected event %+v", got[0].Events[0])
+	}
+
+	receipts, err := transport.GetTransactionReceipts(transactionIds)
+	if err != nil {
+		t.Fatalf("GetTransactionReceipts: %s", err)
+	}
+	got = nil
+	for _, transactionId := range transactionIds {
+		got = append(got, receipts[transactionId])
+	}
+	checkValues(t, "GetTransactionReceipts", got, expected)
+
+	_, err = transport.GetTransactionReceipts(append(transactionIds, UnknownId("receipt")))
+	checkErrorCode(t, "GetTransactionReceipts(unknown)", err, errors.TRANSACTION_RECEIPT_NOT_FOUND)
+
+	_, err = transport.GetTransactionReceipt("not-a-transaction-id")
+	checkErrorCode(t, "GetTransactionReceipt(invalid)", err, errors.INVALID_RESOURCE_ID)
+}
+
@@ -0,0 +1,553 @@
+package conformance
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/messaging"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_batch_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_batch_submit_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_block_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_list_control_pb2"
+	client_peer "github.com/hyperledger/sawtooth-sdk-go/protobuf/client_peers_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_receipt_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_state_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_transaction_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/validator_pb2"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/zmq"
+)
+
+// DEFAULT_PAGE_SIZE and MAX_PAGE_SIZE match the paging limits of the Sawtooth validator.
+const (
+	DEFAULT_PAGE_SIZE	= 100
+	MAX_PAGE_SIZE		= 1000
+)
+
+var headerSignaturePattern = regexp.MustCompile("^[0-9a-f]{128}$")
+var addressPattern = regexp.MustCompile(fmt.Sprintf("^[0-9a-f]{%d}$", types.ADDRESS_LENGTH))
+var addressPrefixPattern = regexp.MustCompile(fmt.Sprintf("^([0-9a-f]{2}){0,%d}$", types.ADDRESS_LENGTH / 2))
+
+// Validator answers the client requests of the validator's ZMQ interface from a Fixture, following
+// the semantics of the Sawtooth validator (ordering, paging and error statuses).
+type Validator struct {
+	Fixture		*Fixture
+
+	// submitted records the ids of batches submitted through the validator
+	submitted	map[string]bool
+	mutex		sync.Mutex
+}
+
+// NewValidator returns a Validator serving the given fixture.
+func NewValidator(fixture *Fixture) *Validator {
+	return &Validator{Fixture: fixture, submitted: make(map[string]bool)}
+}
+
+// Handle answers a single request, returning the type and content of the response message.
+func (self *Validator) Handle(t validator_pb2.Message_MessageType, content []byte) (validator_pb2.Message_MessageType, []byte, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	var request proto.Message
+	var handler func(proto.Message) proto.Message
+	var responseType validator_pb2.Message_MessageType
+
+	switch t {
+	case validator_pb2.Message_CLIENT_PEERS_GET_REQUEST:
+		request, handler, responseType = &client_peer.ClientPeersGetRequest{}, self.handlePeersGet, validator_pb2.Message_CLIENT_PEERS_GET_RESPONSE
+	case validator_pb2.Message_CLIENT_BLOCK_LIST_REQUEST:
+		request, handler, responseType = &client_block_pb2.ClientBlockListRequest{}, self.handleBlockList, validator_pb2.Message_CLIENT_BLOCK_LIST_RESPONSE
+	case validator_pb2.Message_CLIENT_BLOCK_GET_BY_ID_REQUEST:
+		request, handler, responseType = &client_block_pb2.ClientBlockGetByIdRequest{}, self.handleBlockGet, validator_pb2.Message_CLIENT_BLOCK_GET_RESPONSE
+	case validator_pb2.Message_CLIENT_BATCH_LIST_REQUEST:
+		request, handler, responseType = &client_batch_pb2.ClientBatchListRequest{}, self.handleBatchList, validator_pb2.Message_CLIENT_BATCH_LIST_RESPONSE
+	case validator_pb2.Message_CLIENT_BATCH_GET_REQUEST:
+		request, handler, responseType = &client_batch_pb2.ClientBatchGetRequest{}, self.handleBatchGet, validator_pb2.Message_CLIENT_BATCH_GET_RESPONSE
+	case validator_pb2.Message_CLIENT_TRANSACTION_LIST_REQUEST:
+		request, handler, responseType = &client_transaction_pb2.ClientTransactionListRequest{}, self.handleTransactionList, validator_pb2.Message_CLIENT_TRANSACTION_LIST_RESPONSE
+	case validator_pb2.Message_CLIENT_TRANSACTION_GET_REQUEST:
+		request, handler, responseType = &client_transaction_pb2.ClientTransactionGetRequest{}, self.handleTransactionGet, validator_pb2.Message_CLIENT_TRANSACTION_GET_RESPONSE
+	case validator_pb2.Message_CLIENT_STATE_LIST_REQUEST:
+		request, handler, responseType = &client_state_pb2.ClientStateListRequest{}, self.handleStateList, validator_pb2.Message_CLIENT_STATE_LIST_RESPONSE
+	case validator_pb2.Message_CLIENT_STATE_GET_REQUEST:
+		request, handler, responseType = &client_state_pb2.ClientStateGetRequest{}, self.handleStateGet, validator_pb2.Message_CLIENT_STATE_GET_RESPONSE
+	case validator_pb2.Message_CLIENT_BATCH_STATUS_REQUEST:
+		request, handler, responseType = &client_batch_submit_pb2.ClientBatchStatusRequest{}, self.handleBatchStatus, validator_pb2.Message_CLIENT_BATCH_STATUS_RESPONSE
+	case validator_pb2.Message_CLIENT_BATCH_SUBMIT_REQUEST:
+		request, handler, responseType = &client_batch_submit_pb2.ClientBatchSubmitRequest{}, self.handleBatchSubmit, validator_pb2.Message_CLIENT_BATCH_SUBMIT_RESPONSE
+	case validator_pb2.Message_CLIENT_RECEIPT_GET_REQUEST:
+		request, handler, responseType = &client_receipt_pb2.ClientReceiptGetRequest{}, self.handleReceiptGet, validator_pb2.Message_CLIENT_RECEIPT_GET_RESPONSE
+	default:
+		return 0, nil, fmt.Errorf("Unsupported message type: %s", t)
+	}
+
+	err := proto.Unmarshal(content, request)
+	if err != nil {
+		return 0, nil, fmt.Errorf("Error decoding %s: %s", t, err)
+	}
+
+	response, err := proto.Marshal(handler(request))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return responseType, response, nil
+}
+
+func (self *Validator) handlePeersGet(message proto.Message) proto.Message {
+	return &client_peer.ClientPeersGetResponse{Status: client_peer.ClientPeersGetResponse_OK}
+}
+
+func (self *Validator) handleBlockList(message proto.Message) proto.Message {
+	request := message.(*client_block_pb2.ClientBlockListRequest)
+
+	head, ok := self.resolveHead(request.HeadId)
+	if !ok {
+		return &client_block_pb2.ClientBlockListResponse{Status: client_block_pb2.ClientBlockListResponse_NO_ROOT}
+	}
+
+	// Blocks are listed newest first by default
+	ids := make([]string, 0, head + 1)
+	for blockNum := head; blockNum >= 0; blockNum-- {
+		ids = append(ids, types.BlockPagingStart(uint64(blockNum)))
+	}
+	ids = reverseIf(ids, isReversed(request.Sorting))
+
+	start, end, paging, ok := paginate(ids, request.Paging)
+	if !ok {
+		return &client_block_pb2.ClientBlockListResponse{Status: client_block_pb2.ClientBlockListResponse_INVALID_PAGING}
+	}
+
+	response := &client_block_pb2.ClientBlockListResponse{
+		Status: client_block_pb2.ClientBlockListResponse_OK,
+		HeadId: self.Fixture.Blocks[head].HeaderSignature,
+		Paging: paging,
+	}
+	for _, id := range ids[start:end] {
+		blockNum, _ := strconv.ParseUint(id, 0, 64)
+		response.Blocks = append(response.Blocks, self.Fixture.Blocks[blockNum])
+	}
+
+	return response
+}
+
+func (self *Validator) handleBlockGet(message proto.Message) proto.Message {
+	request := message.(*client_block_pb2.ClientBlockGetByIdRequest)
+
+	if !headerSignaturePattern.MatchString(request.BlockId) {
+		return &client_block_pb2.ClientBlockGetResponse{Status: client_block_pb2.ClientBlockGetResponse_INVALID_ID}
+	}
+
+	blockNum, ok := self.Fixture.BlockNum(request.BlockId)
+	if !ok {
+		return &client_block_pb2.ClientBlockGetResponse{Status: client_block_pb2.ClientBlockGetResponse_NO_RESOURCE}
+	}
+
+	return &client_block_pb2.ClientBlockGetResponse{
+		Status: client_block_pb2.ClientBlockGetResponse_OK,
+		Block: self.Fixture.Blocks[blockNum],
+	}
+}
+
+func (self *Validator) handleBatchList(message proto.Message) proto.Message {
+	request := message.(*client_batch_pb2.ClientBatchListRequest)
+
+	head, ok := self.resolveHead(request.HeadId)
+	if !ok {
+		return &client_batch_pb2.ClientBatchListResponse{Status: client_batch_pb2.ClientBatchListResponse_NO_ROOT}
+	}
+
+	batches := self.Fixture.BatchesAt(head)
+	ids := make([]string, len(batches))
+	for i, batch := range batches {
+		ids[i] = batch.HeaderSignature
+	}
+	ids = reverseIf(ids, isReversed(request.Sorting))
+
+	start, end, paging, ok := paginate(ids, request.Paging)
+	if !ok {
+		return &client_batch_pb2.ClientBatchListResponse{Status: client_batch_pb2.ClientBatchListResponse_INVALID_PAGING}
+	}
+
+	response := &client_batch_pb2.ClientBatchListResponse{
+		Status: client_batch_pb2.ClientBatchListResponse_OK,
+		HeadId: self.Fixture.Blocks[head].HeaderSignature,
+		Paging: paging,
+	}
+	for _, id := range ids[start:end] {
+		response.Batches = append(response.Batches, self.Fixture.Batch(id))
+	}
+
+	return response
+}
+
+func (self *Validator) handleBatchGet(message proto.Message) proto.Message {
+	request := message.(*client_batch_pb2.ClientBatchGetRequest)
+
+	if !headerSignaturePattern.MatchString(request.BatchId) {
+		return &client_batch_pb2.ClientBatchGetResponse{Status: client_batch_pb2.ClientBatchGetResponse_INVALID_ID}
+	}
+
+	batch := self.Fixture.Batch(request.BatchId)
+	if batch == nil {
+		return &client_batch_pb2.ClientBatchGetResponse{Status: client_batch_pb2.ClientBatchGetResponse_NO_RESOURCE}
+	}
+
+	return &client_batch_pb2.ClientBatchGetResponse{Status: client_batch_pb2.ClientBatchGetResponse_OK, Batch: batch}
+}
+
+func (self *Validator) handleTransactionList(message proto.Message) proto.Message {
+	request := message.(*client_transaction_pb2.ClientTransactionListRequest)
+
+	head, ok := self.resolveHead(request.HeadId)
+	if !ok {
+		return &client_transaction_pb2.ClientTransactionListResponse{Status: client_transaction_pb2.ClientTransactionListResponse_NO_ROOT}
+	}
+
+	transactions := self.Fixture.TransactionsAt(head)
+	ids := make([]string, len(transactions))
+	for i, transaction := range transactions {
+		ids[i] = transaction.HeaderSignature
+	}
+	ids = reverseIf(ids, isReversed(request.Sorting))
+
+	start, end, paging, ok := paginate(ids, request.Paging)
+	if !ok {
+		return &client_transaction_pb2.ClientTransactionListResponse{Status: client_transaction_pb2.ClientTransactionListResponse_INVALID_PAGING}
+	}
+
+	response := &client_transaction_pb2.ClientTransactionListResponse{
+		Status: client_transaction_pb2.ClientTransactionListResponse_OK,
+		HeadId: self.Fixture.Blocks[head].HeaderSignature,
+		Paging: paging,
+	}
+	for _, id := range ids[start:end] {
+		response.Transactions = append(response.Transactions, self.Fixture.Transaction(id))
+	}
+
+	return response
+}
+
+func (self *Validator) handleTransactionGet(message proto.Message) proto.Message {
+	request := message.(*client_transaction_pb2.ClientTransactionGetRequest)
+
+	if !headerSignaturePattern.MatchString(request.TransactionId) {
+		return &client_transaction_pb2.ClientTransactionGetResponse{Status: client_transaction_pb2.ClientTransactionGetResponse_INVALID_ID}
+	}
+
+	transaction := self.Fixture.Transaction(request.TransactionId)
+	if transaction == nil {
+		return &client_transaction_pb2.ClientTransactionGetResponse{Status: client_transaction_pb2.ClientTransactionGetResponse_NO_RESOURCE}
+	}
+
+	return &client_transaction_pb2.ClientTransactionGetResponse{Status: client_transaction_pb2.ClientTransactionGetResponse_OK, Transaction: transaction}
+}
+
+func (self *Validator) handleStateList(message proto.Message) proto.Message {
+	request := message.(*client_state_pb2.ClientStateListRequest)
+
+	blockNum, ok := self.resolveStateRoot(request.StateRoot)
+	if !ok {
+		return &client_state_pb2.ClientStateListResponse{Status: client_state_pb2.ClientStateListResponse_INVALID_ROOT}
+	}
+
+	if !addressPrefixPattern.MatchString(request.Address) {
+		return &client_state_pb2.ClientStateListResponse{Status: client_state_pb2.ClientStateListResponse_INVALID_ADDRESS}
+	}
+
+	// State is listed in address order by default
+	addresses := reverseIf(self.Fixture.AddressesAt(blockNum, request.Address), isReversed(request.Sorting))
+
+	start, end, paging, ok := paginate(addresses, request.Paging)
+	if !ok {
+		return &client_state_pb2.ClientStateListResponse{Status: client_state_pb2.ClientStateListResponse_INVALID_PAGING}
+	}
+
+	response := &client_state_pb2.ClientStateListResponse{
+		Status: client_state_pb2.ClientStateListResponse_OK,
+		StateRoot: self.Fixture.StateRoots[blockNum],
+		Paging: paging,
+	}
+	for _, address := range addresses[start:end] {
+		entry := &client_state_pb2.ClientStateListResponse_Entry{Address: address, Data: self.Fixture.States[blockNum][address]}
+		response.Entries = append(response.Entries, entry)
+	}
+
+	return response
+}
+
+func (self *Validator) handleStateGet(message proto.Message) proto.Message {
+	request := message.(*client_state_pb2.ClientStateGetRequest)
+
+	blockNum, ok := self.resolveStateRoot(request.StateRoot)
+	if !ok {
+		return &client_state_pb2.ClientStateGetResponse{Status: client_state_pb2.ClientStateGetResponse_INVALID_ROOT}
+	}
+
+	if !addressPattern.MatchString(request.Address) {
+		return &client_state_pb2.ClientStateGetResponse{Status: client_state_pb2.ClientStateGetResponse_INVALID_ADDRESS}
+	}
+
+	value, ok := self.Fixture.States[blockNum][request.Address]
+	if !ok {
+		return &client_state_pb2.ClientStateGetResponse{Status: client_state_pb2.ClientStateGetResponse_NO_RESOURCE}
+	}
+
+	return &client_state_pb2.ClientStateGetResponse{
+		Status: client_state_pb2.ClientStateGetResponse_OK,
+		Value: value,
+		StateRoot: self.Fixture.StateRoots[blockNum],
+	}
+}
+
+func (self *Validator) handleBatchStatus(message proto.Message) proto.Message {
+	request := message.(*client_batch_submit_pb2.ClientBatchStatusRequest)
+
+	if len(request.BatchIds) == 0 {
+		return &client_batch_submit_pb2.ClientBatchStatusResponse{Status: client_batch_submit_pb2.ClientBatchStatusResponse_NO_RESOURCE}
+	}
+
+	response := &client_batch_submit_pb2.ClientBatchStatusResponse{Status: client_batch_submit_pb2.ClientBatchStatusResponse_OK}
+	for _, batchId := range request.BatchIds {
+		if !headerSignaturePattern.MatchString(batchId) {
+			return &client_batch_submit_pb2.ClientBatchStatusResponse{Status: client_batch_submit_pb2.ClientBatchStatusResponse_INVALID_ID}
+		}
+
+		status := &client_batch_submit_pb2.ClientBatchStatus{BatchId: batchId, Status: client_batch_submit_pb2.ClientBatchStatus_UNKNOWN}
+		switch {
+		case self.Fixture.Batch(batchId) != nil:
+			status.Status = client_batch_submit_pb2.ClientBatchStatus_COMMITTED
+		case batchId == self.Fixture.PendingBatchId || self.submitted[batchId]:
+			status.Status = client_batch_submit_pb2.ClientBatchStatus_PENDING
+		case batchId == self.Fixture.InvalidBatchId:
+			status.Status = client_batch_submit_pb2.ClientBatchStatus_INVALID
+			status.InvalidTransactions = []*client_batch_submit_pb2.ClientBatchStatus_InvalidTransaction{
+				{TransactionId: batchId, Message: "Invalid transaction"},
+			}
+		}
+		response.BatchStatuses = append(response.BatchStatuses, status)
+	}
+
+	return response
+}
+
+func (self *Validator) handleBatchSubmit(message proto.Message) proto.Message {
+	request := message.(*client_batch_submit_pb2.ClientBatchSubmitRequest)
+
+	for _, batch := range request.Batches {
+		if !validBatch(batch) {
+			return &client_batch_submit_pb2.ClientBatchSubmitResponse{Status: client_batch_submit_pb2.ClientBatchSubmitResponse_INVALID_BATCH}
+		}
+	}
+
+	if self.Fixture.AutoCommit {
+		self.Fixture.Commit(request.Batches)
+	} else {
+		for _, batch := range request.Batches {
+			self.submitted[batch.HeaderSignature] = true
+		}
+	}
+
+	return &client_batch_submit_pb2.ClientBatchSubmitResponse{Status: client_batch_submit_pb2.ClientBatchSubmitResponse_OK}
+}
+
+func (self *Validator) handleReceiptGet(message proto.Message) proto.Message {
+	request := message.(*client_receipt_pb2.ClientReceiptGetRequest)
+
+	// As in the validator, the request fails unless every receipt is found
+	response := &client_receipt_pb2.ClientReceiptGetResponse{Status: client_receipt_pb2.ClientReceiptGetResponse_OK}
+	for _, transactionId := range request.TransactionIds {
+		if !headerSignaturePattern.MatchString(transactionId) {
+			return &client_receipt_pb2.ClientReceiptGetResponse{Status: client_receipt_pb2.ClientReceiptGetResponse_INVALID_ID}
+		}
+
+		receipt := self.Fixture.Receipt(transactionId)
+		if receipt == nil {
+			return &client_receipt_pb2.ClientReceiptGetResponse{Status: client_receipt_pb2.ClientReceiptGetResponse_NO_RESOURCE}
+		}
+		response.Receipts = append(response.Receipts, receipt)
+	}
+
+	return response
+}
+
+// resolveHead returns the number of the block with the given id ("" for the chain head).
+func (self *Validator) resolveHead(headId string) (int, bool) {
+	if headId == "" {
+		return len(self.Fixture.Blocks) - 1, true
+	}
+
+	return self.Fixture.BlockNum(headId)
+}
+
+// resolveStateRoot returns the number of the block with the given state root ("" for the chain head).
+func (self *Validator) resolveStateRoot(stateRoot string) (int, bool) {
+	if stateRoot == "" {
+		return len(self.Fixture.Blocks) - 1, true
+	}
+
+	return self.Fixture.StateRootBlockNum(stateRoot)
+}
+
+// validBatch checks that a batch and its transactions have well-formed headers and signatures.
+func validBatch(batch *batch_pb2.Batch) bool {
+	var header batch_pb2.BatchHeader
+	if proto.Unmarshal(batch.Header, &header) != nil || !headerSignaturePattern.MatchString(batch.HeaderSignature) {
+		return false
+	}
+
+	if len(batch.Transactions) == 0 || len(batch.Transactions) != len(header.TransactionIds) {
+		return false
+	}
+
+	for i, transaction := range batch.Transactions {
+		var transactionHeader transaction_pb2.TransactionHeader
+		if proto.Unmarshal(transaction.Header, &transactionHeader) != nil || transaction.HeaderSignature != header.TransactionIds[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isReversed returns true if the sort controls request the reverse of the default order.
+func isReversed(sorting []*client_list_control_pb2.ClientSortControls) bool {
+	return len(sorting) > 0 && sorting[0].Reverse
+}
+
+// reverseIf returns ids, reversed if reverse is true.
+func reverseIf(ids []string, reverse bool) []string {
+	if !reverse {
+		return ids
+	}
+
+	reversed := make([]string, len(ids))
+	for i, id := range ids {
+		reversed[len(ids) - 1 - i] = id
+	}
+
+	return reversed
+}
+
+// paginate selects the page of ids requested by paging, in the same way as the validator: the page
+// starts at the id given as start and holds up to limit ids. Returns false if start is not found.
+func paginate(ids []string, paging *client_list_control_pb2.ClientPagingControls) (int, int, *client_list_control_pb2.ClientPagingResponse, bool) {
+	limit := DEFAULT_PAGE_SIZE
+	start := ""
+	if paging != nil {
+		if paging.Limit > 0 {
+			limit = int(paging.Limit)
+		}
+		if limit > MAX_PAGE_SIZE {
+			limit = MAX_PAGE_SIZE
+		}
+		start = paging.Start
+	}
+
+	response := &client_list_control_pb2.ClientPagingResponse{Start: start, Limit: int32(limit)}
+	if len(ids) == 0 {
+		return 0, 0, response, start == ""
+	}
+
+	startIndex := 0
+	if start != "" {
+		startIndex = -1
+		for i, id := range ids {
+			if strings.EqualFold(id, start) {
+				startIndex = i
+				break
+			}
+		}
+		if startIndex < 0 {
+			return 0, 0, nil, false
+		}
+	}
+
+	endIndex := startIndex + limit
+	if endIndex < len(ids) {
+		response.Next = ids[endIndex]
+	} else {
+		endIndex = len(ids)
+	}
+
+	return startIndex, endIndex, response, true
+}
+
+// Connection is a connection to a Validator, providing the parts of messaging.Connection used by
+// the ZMQ transport. Requests are answered as soon as they are sent.
+type Connection struct {
+	validator	*Validator
+	identity	string
+	replies		map[string]*validator_pb2.Message
+	closed		bool
+}
+
+// NewConnection returns a new Connection to the validator.
+func (self *Validator) NewConnection() *Connection {
+	return &Connection{
+		validator: self,
+		identity: messaging.GenerateId(),
+		replies: make(map[string]*validator_pb2.Message),
+	}
+}
+
+// SendNewMsg sends a request to the validator and returns its correlation id.
+func (self *Connection) SendNewMsg(t validator_pb2.Message_MessageType, c []byte) (string, error) {
+	if self.closed {
+		return "", fmt.Errorf("Connection is closed")
+	}
+
+	responseType, content, err := self.validator.Handle(t, c)
+	if err != nil {
+		return "", err
+	}
+
+	corrId := messaging.GenerateId()
+	self.replies[corrId] = &validator_pb2.Message{MessageType: responseType, CorrelationId: corrId, Content: content}
+
+	return corrId, nil
+}
+
+// RecvMsgWithId returns the reply to the request with the given correlation id.
+func (self *Connection) RecvMsgWithId(corrId string) (string, *validator_pb2.Message, error) {
+	msg, ok := self.replies[corrId]
+	if !ok {
+		return "", nil, fmt.Errorf("No reply with correlation id %s", corrId)
+	}
+	delete(self.replies, corrId)
+
+	return "", msg, nil
+}
+
+// Close closes the connection.
+func (self *Connection) Close() {
+	self.closed = true
+}
+
+// Identity returns the identity of the connection.
+func (self *Connection) Identity() string {
+	return self.identity
+}
+
+// NewZmqTransport returns a ZMQ transport whose connections are Connections to the validator, for
+// the tests of packages built on top of a transport.
+func NewZmqTransport(t testing.TB, validator *Validator) transport.SawtoothClientTransport {
+	t.Helper()
+
+	client, err := zmq.NewSawtoothClientTransportZmqWithDialer(func() (zmq.ZmqConnection, error) {
+		return validator.NewConnection(), nil
+	})
+	if err != nil {
+		t.Fatalf("Error creating transport: %s", err)
+	}
+
+	return client
+}
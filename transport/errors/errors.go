@@ -11,6 +11,9 @@ type SawtoothTransportErrorCode uint
 const (
 	NO_ERROR						SawtoothTransportErrorCode		= 0
 	REQUEST_ERROR					SawtoothTransportErrorCode		= 512
+	TRANSPORT_READ_ONLY				SawtoothTransportErrorCode		= 513
+	STATE_UNAVAILABLE				SawtoothTransportErrorCode		= 514
+	RECEIPT_UNAVAILABLE				SawtoothTransportErrorCode		= 515
 	UNKNOWN_ERROR					SawtoothTransportErrorCode		= 1024
 
 	VALIDATOR_UNKNOWN_ERROR			SawtoothTransportErrorCode		= 10
@@ -54,3 +57,9 @@ func (self *SawtoothClientTransportError) Error() string {
 func NewSawtoothClientTransportRequestError(err error) error {
 	return &SawtoothClientTransportError{ErrorCode: REQUEST_ERROR, ErrorObject: err}
 }
+
+// HasErrorCode returns true if err is a SawtoothClientTransportError with the given error code.
+func HasErrorCode(err error, errorCode SawtoothTransportErrorCode) bool {
+	transportError, ok := err.(*SawtoothClientTransportError)
+	return ok && transportError.ErrorCode == errorCode
+}
@@ -14,6 +14,7 @@ type SawtoothClientTransport interface {
 	// Methods to retrieve and submit batches.
 	GetBatch(batchId string) (*types.Batch, error)
 	GetBatchIterator(fetch int, reverse bool) types.BatchIterator
+	GetBatchIteratorFromCursor(cursor *types.Cursor) types.BatchIterator
 	GetBatchStatus(batchId string, wait int) (types.BatchStatus, error)
 	GetBatchStatusMultiple(batchIds []string, wait int) (map[string]types.BatchStatus, error)
 	SubmitBatchList(batchList *batch_pb2.BatchList) error
@@ -21,13 +22,27 @@ type SawtoothClientTransport interface {
 	// Methods to retrieve blocks.
 	GetBlock(blockId string) (*types.Block, error)
 	GetBlockIterator(fetch int, reverse bool) types.BlockIterator
+	GetBlockIteratorFromCursor(cursor *types.Cursor) types.BlockIterator
+	GetBlockRangeIterator(first uint64, last uint64, fetch int, reverse bool) types.BlockIterator
+	GetBlockIteratorSince(blockId string, fetch int) types.BlockIterator
 
 	// Methods to retrieve transactions.
 	GetTransaction(transactionId string) (*types.Transaction, error)
 	GetTransactionIterator(fetch int, reverse bool) types.TransactionIterator
+	GetTransactionIteratorFromCursor(cursor *types.Cursor) types.TransactionIterator
+
+	// Methods to retrieve the receipts of committed transactions.
+	GetTransactionReceipt(transactionId string) (*types.TransactionReceipt, error)
+	GetTransactionReceipts(transactionIds []string) (map[string]*types.TransactionReceipt, error)
 
 	// Methods to retrieve state.
 	GetState(address string) (*types.State, error)
 	GetStateAtHead(address string, head string) (*types.State, error)
+	GetStateAtRoot(address string, stateRoot string) (*types.State, error)
+	GetStates(addresses []string) (map[string]*types.State, error)
+	GetStatesAtHead(addresses []string, head string) (map[string]*types.State, error)
 	GetStateIterator(addressPrefix string, fetch int, reverse bool) types.StateIterator
+	GetStateIteratorAtHead(addressPrefix string, head string, fetch int, reverse bool) types.StateIterator
+	GetStateIteratorAtRoot(addressPrefix string, stateRoot string, fetch int, reverse bool) types.StateIterator
+	GetStateIteratorFromCursor(cursor *types.Cursor) types.StateIterator
 }
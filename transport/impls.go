@@ -14,15 +14,60 @@ type SawtoothClientTransportType string
 const TRANSPORT_REST SawtoothClientTransportType = "rest"
 // TRANSPORT_ZMQ represents the ZMQ transport implementation.
 const TRANSPORT_ZMQ SawtoothClientTransportType = "zmq"
+// TRANSPORT_ARCHIVE represents the read-only transport over a chain archive. It is implemented by
+// the archive package, which must be imported for this type to be available.
+const TRANSPORT_ARCHIVE SawtoothClientTransportType = "archive"
+
+// SawtoothClientTransportConstructor instantiates a transport implemented outside this package.
+type SawtoothClientTransportConstructor func(url *url.URL) (SawtoothClientTransport, error)
+
+// registeredTransports holds the constructors added with RegisterSawtoothClientTransport.
+var registeredTransports = make(map[SawtoothClientTransportType]SawtoothClientTransportConstructor)
+
+// RegisterSawtoothClientTransport makes a transport implemented outside this package available to
+// NewSawtoothClientTransport. It is intended to be called from the init function of the package
+// implementing the transport, and panics if the type is already registered. Registered transports
+// are configured through their URL only.
+func RegisterSawtoothClientTransport(transportType SawtoothClientTransportType, constructor SawtoothClientTransportConstructor) {
+	_, exists := registeredTransports[transportType]
+	if exists || transportType == TRANSPORT_REST || transportType == TRANSPORT_ZMQ {
+		panic(fmt.Sprintf("Transport type already registered: %s", transportType))
+	}
+
+	registeredTransports[transportType] = constructor
+}
+
+// SawtoothClientTransportOptions holds optional, implementation-specific settings for a transport.
+// Only the settings matching the requested transport type are used.
+type SawtoothClientTransportOptions struct {
+	// Rest holds settings for the REST API transport.
+	Rest	*rest.SawtoothClientTransportRestOptions
+	// Zmq holds settings for the ZMQ transport.
+	Zmq		*zmq.SawtoothClientTransportZmqOptions
+}
 
 // NewSawtoothClientTransport instantiates and returns a new SawtoothClientTransport of the specified type.
 func NewSawtoothClientTransport(transportType SawtoothClientTransportType, url *url.URL) (SawtoothClientTransport, error) {
+	return NewSawtoothClientTransportWithOptions(transportType, url, nil)
+}
+
+// NewSawtoothClientTransportWithOptions instantiates and returns a new SawtoothClientTransport of the
+// specified type, configured with the given options (which may be nil).
+func NewSawtoothClientTransportWithOptions(transportType SawtoothClientTransportType, url *url.URL, options *SawtoothClientTransportOptions) (SawtoothClientTransport, error) {
+	if options == nil {
+		options = &SawtoothClientTransportOptions{}
+	}
+
 	switch transportType {
 	case TRANSPORT_REST:
-		return rest.NewSawtoothClientTransportRest(url)
+		return rest.NewSawtoothClientTransportRestWithOptions(url, options.Rest)
 	case TRANSPORT_ZMQ:
-		return zmq.NewSawtoothClientTransportZmq(url)
+		return zmq.NewSawtoothClientTransportZmqWithOptions(url, options.Zmq)
 	default:
-		return nil, fmt.Errorf("Unknown transport type")
+		constructor, ok := registeredTransports[transportType]
+		if !ok {
+			return nil, fmt.Errorf("Unknown transport type")
+		}
+		return constructor(url)
 	}
 }
@@ -0,0 +1,262 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TOKEN_EXPIRY_DELTA is how long before its expiry a token is considered stale and refreshed.
+const TOKEN_EXPIRY_DELTA = time.Second * 30
+
+// Token represents a bearer token used to authenticate requests to the REST API.
+type Token struct {
+	// AccessToken is the value sent in the Authorization header.
+	AccessToken	string
+	// Expiry is the time at which the token expires. A zero value means the token never expires.
+	Expiry		time.Time
+}
+
+// Valid returns true if the token is present and does not expire within TOKEN_EXPIRY_DELTA.
+func (self *Token) Valid() bool {
+	if self == nil || self.AccessToken == "" {
+		return false
+	}
+
+	if self.Expiry.IsZero() {
+		return true
+	}
+
+	return time.Now().Add(TOKEN_EXPIRY_DELTA).Before(self.Expiry)
+}
+
+// TokenSource is an interface that provides bearer tokens for REST API requests.
+// Token() is consulted for every request, so implementations should cache tokens and only
+// refresh them when required. Implementations must be safe for concurrent use.
+type TokenSource interface {
+	// Token returns a valid token, refreshing it first if necessary.
+	Token() (*Token, error)
+	// Invalidate discards any cached token. It is called when the REST API rejects a token
+	// with HTTP 401, so that the next call to Token() fetches a fresh one.
+	Invalidate()
+}
+
+// StaticTokenSource is a TokenSource that always returns the same token.
+type StaticTokenSource struct {
+	token *Token
+}
+
+// NewStaticTokenSource returns a new StaticTokenSource for the given token.
+func NewStaticTokenSource(accessToken string) *StaticTokenSource {
+	return &StaticTokenSource{token: &Token{AccessToken: accessToken}}
+}
+
+// Token returns the static token.
+func (self *StaticTokenSource) Token() (*Token, error) {
+	return self.token, nil
+}
+
+// Invalidate is a no-op for StaticTokenSource.
+func (self *StaticTokenSource) Invalidate() {}
+
+// FileTokenSource is a TokenSource that reads a token from a file, re-reading the file whenever
+// it changes. This is suitable for tokens that are rotated on disk by another process, such as
+// Kubernetes projected service account tokens.
+type FileTokenSource struct {
+	// Path is the path to the file containing the token.
+	Path		string
+
+	mutex		sync.Mutex
+	token		*Token
+	modTime		time.Time
+	size		int64
+}
+
+// NewFileTokenSource returns a new FileTokenSource for the given path.
+func NewFileTokenSource(path string) *FileTokenSource {
+	return &FileTokenSource{Path: path}
+}
+
+// Token returns the token contained in the file, re-reading it if the file has changed.
+func (self *FileTokenSource) Token() (*Token, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	// Stat follows symlinks, so an atomic swap of a symlinked directory (as done for
+	// Kubernetes projected volumes) is seen as a change.
+	info, err := os.Stat(self.Path)
+	if err != nil {
+		return nil, fmt.Errorf("Could not stat token file (%s) with error: %s", self.Path, err)
+	}
+
+	if self.token != nil && info.ModTime().Equal(self.modTime) && info.Size() == self.size {
+		return self.token, nil
+	}
+
+	data, err := ioutil.ReadFile(self.Path)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read token from file (%s) with error: %s", self.Path, err)
+	}
+
+	accessToken := strings.TrimSpace(string(data))
+	if accessToken == "" {
+		return nil, fmt.Errorf("Token file (%s) is empty", self.Path)
+	}
+
+	self.token = &Token{AccessToken: accessToken}
+	self.modTime = info.ModTime()
+	self.size = info.Size()
+
+	return self.token, nil
+}
+
+// Invalidate discards the cached token so the file is re-read on the next request.
+func (self *FileTokenSource) Invalidate() {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.token = nil
+}
+
+// clientCredentialsResponse represents a reply from an OAuth2 token endpoint.
+type clientCredentialsResponse struct {
+	AccessToken		string		`json:"access_token"`
+	TokenType		string		`json:"token_type"`
+	ExpiresIn		int64		`json:"expires_in"`
+}
+
+// clientCredentialsErrorResponse represents an error reply from an OAuth2 token endpoint.
+type clientCredentialsErrorResponse struct {
+	Error				string		`json:"error"`
+	ErrorDescription	string		`json:"error_description"`
+}
+
+// ClientCredentialsTokenSource is a TokenSource that obtains tokens using the OAuth2 client
+// credentials flow (RFC 6749, section 4.4). Tokens are cached and refreshed shortly before
+// they expire.
+type ClientCredentialsTokenSource struct {
+	// TokenURL is the URL of the token endpoint.
+	TokenURL		string
+	// ClientID is the OAuth2 client id.
+	ClientID		string
+	// ClientSecret is the OAuth2 client secret.
+	ClientSecret	string
+	// Scopes is an optional list of requested scopes.
+	Scopes			[]string
+	// EndpointParams holds any additional parameters to send to the token endpoint.
+	EndpointParams	url.Values
+	// HttpClient is the client used to talk to the token endpoint.
+	HttpClient		*http.Client
+
+	mutex			sync.Mutex
+	token			*Token
+}
+
+// NewClientCredentialsTokenSource returns a new ClientCredentialsTokenSource.
+func NewClientCredentialsTokenSource(tokenUrl string, clientId string, clientSecret string, scopes []string) *ClientCredentialsTokenSource {
+	return &ClientCredentialsTokenSource{
+		TokenURL: tokenUrl,
+		ClientID: clientId,
+		ClientSecret: clientSecret,
+		Scopes: scopes,
+		HttpClient: &http.Client{Timeout: HTTP_TIMEOUT},
+	}
+}
+
+// Token returns the cached token, or requests a new one from the token endpoint if the cached
+// token is missing or about to expire.
+func (self *ClientCredentialsTokenSource) Token() (*Token, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if self.token.Valid() {
+		return self.token, nil
+	}
+
+	token, err := self.fetchToken()
+	if err != nil {
+		return nil, err
+	}
+	self.token = token
+
+	return self.token, nil
+}
+
+// Invalidate discards the cached token so a new one is requested on the next request.
+func (self *ClientCredentialsTokenSource) Invalidate() {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.token = nil
+}
+
+// fetchToken performs the client credentials request against the token endpoint.
+func (self *ClientCredentialsTokenSource) fetchToken() (*Token, error) {
+	form := url.Values{}
+	for key, values := range self.EndpointParams {
+		form[key] = values
+	}
+	form.Set("grant_type", "client_credentials")
+	if len(self.Scopes) > 0 {
+		form.Set("scope", strings.Join(self.Scopes, " "))
+	}
+
+	request, err := http.NewRequest(http.MethodPost, self.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	request.SetBasicAuth(url.QueryEscape(self.ClientID), url.QueryEscape(self.ClientSecret))
+
+	httpClient := self.HttpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("Error requesting token: %s", err)
+	}
+	defer response.Body.Close()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading token response: %s", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		var errorResponse clientCredentialsErrorResponse
+		if json.Unmarshal(data, &errorResponse) == nil && errorResponse.Error != "" {
+			return nil, fmt.Errorf("Error requesting token: status=%d, error=%s, description=%s", response.StatusCode, errorResponse.Error, errorResponse.ErrorDescription)
+		}
+		return nil, fmt.Errorf("Error requesting token: status=%d", response.StatusCode)
+	}
+
+	var tokenResponse clientCredentialsResponse
+	err = json.Unmarshal(data, &tokenResponse)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing token response: %s", err)
+	}
+
+	if tokenResponse.AccessToken == "" {
+		return nil, fmt.Errorf("Token response did not contain an access token")
+	}
+
+	if tokenResponse.TokenType != "" && !strings.EqualFold(tokenResponse.TokenType, "bearer") {
+		return nil, fmt.Errorf("Unsupported token type: %s", tokenResponse.TokenType)
+	}
+
+	token := &Token{AccessToken: tokenResponse.AccessToken}
+	if tokenResponse.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
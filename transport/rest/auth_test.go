@@ -0,0 +1,200 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/conformance"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/rest"
+)
+
+// tokenEndpoint is an OAuth2 token endpoint issuing the tokens token-1, token-2, ... to a single client.
+type tokenEndpoint struct {
+	mutex		sync.Mutex
+	requests	int
+	expiresIn	int
+	tokenType	string
+}
+
+func (self *tokenEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	clientId, clientSecret, ok := r.BasicAuth()
+	if !ok || clientId != "client" || clientSecret != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client", "error_description": "Unknown client"})
+		return
+	}
+	if r.PostFormValue("grant_type") != "client_credentials" || r.PostFormValue("scope") != "read write" || r.PostFormValue("audience") != "sawtooth" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	self.requests++
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": fmt.Sprintf("token-%d", self.requests),
+		"token_type": self.tokenType,
+		"expires_in": self.expiresIn,
+	})
+}
+
+// authorizingApi serves the REST API to requests bearing the accepted token, rejecting others with
+// HTTP 401.
+type authorizingApi struct {
+	api			http.Handler
+	mutex		sync.Mutex
+	accepted	string
+	rejected	int
+}
+
+func (self *authorizingApi) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	self.mutex.Lock()
+	authorized := r.Header.Get("Authorization") == "Bearer " + self.accepted
+	if !authorized {
+		self.rejected++
+	}
+	self.mutex.Unlock()
+
+	if !authorized {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error": {"code": 401, "title": "Unauthorized", "message": "Invalid token"}}`))
+		return
+	}
+
+	self.api.ServeHTTP(w, r)
+}
+
+// newClientCredentials returns a client credentials token source for the endpoint.
+func newClientCredentials(endpoint *httptest.Server, clientSecret string) *rest.ClientCredentialsTokenSource {
+	tokenSource := rest.NewClientCredentialsTokenSource(endpoint.URL, "client", clientSecret, []string{"read", "write"})
+	tokenSource.EndpointParams = url.Values{"audience": {"sawtooth"}}
+
+	return tokenSource
+}
+
+func TestClientCredentialsTokenSource(t *testing.T) {
+	endpoint := &tokenEndpoint{expiresIn: 3600, tokenType: "Bearer"}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	// Tokens are cached until invalidated
+	tokenSource := newClientCredentials(server, "secret")
+	for _, expected := range []string{"token-1", "token-1"} {
+		token, err := tokenSource.Token()
+		if err != nil {
+			t.Fatalf("Token: %s", err)
+		}
+		if token.AccessToken != expected || !token.Valid() {
+			t.Fatalf("Token: got %+v, expected %s", token, expected)
+		}
+	}
+	tokenSource.Invalidate()
+	token, err := tokenSource.Token()
+	if err != nil || token.AccessToken != "token-2" {
+		t.Fatalf("Token(invalidated): got %+v (%v), expected token-2", token, err)
+	}
+
+	// Tokens expiring within TOKEN_EXPIRY_DELTA are refreshed
+	endpoint.expiresIn = int(rest.TOKEN_EXPIRY_DELTA.Seconds()) / 2
+	tokenSource = newClientCredentials(server, "secret")
+	for _, expected := range []string{"token-3", "token-4"} {
+		token, err = tokenSource.Token()
+		if err != nil || token.AccessToken != expected {
+			t.Fatalf("Token(expiring): got %+v (%v), expected %s", token, err, expected)
+		}
+	}
+
+	// Errors of the token endpoint are reported
+	_, err = newClientCredentials(server, "wrong").Token()
+	if err == nil || !strings.Contains(err.Error(), "invalid_client") {
+		t.Fatalf("Token(wrong secret): expected an invalid_client error, got: %v", err)
+	}
+	endpoint.tokenType = "mac"
+	_, err = newClientCredentials(server, "secret").Token()
+	if err == nil {
+		t.Fatalf("Token(mac): expected an error")
+	}
+}
+
+func TestFileTokenSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	tokenSource := rest.NewFileTokenSource(path)
+
+	_, err := tokenSource.Token()
+	if err == nil {
+		t.Fatalf("Token(missing): expected an error")
+	}
+
+	// The file is re-read whenever it changes
+	for _, value := range []string{"first\n", "second-token", "  third  \n"} {
+		err = ioutil.WriteFile(path, []byte(value), 0600)
+		if err != nil {
+			t.Fatalf("WriteFile: %s", err)
+		}
+
+		token, err := tokenSource.Token()
+		if err != nil {
+			t.Fatalf("Token: %s", err)
+		}
+		if token.AccessToken != strings.TrimSpace(value) || !token.Valid() {
+			t.Fatalf("Token: got %q, expected %q", token.AccessToken, strings.TrimSpace(value))
+		}
+	}
+
+	err = ioutil.WriteFile(path, []byte("\n"), 0600)
+	if err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+	_, err = tokenSource.Token()
+	if err == nil {
+		t.Fatalf("Token(empty): expected an error")
+	}
+}
+
+func TestUnauthorizedRetry(t *testing.T) {
+	fixture := conformance.NewFixture()
+	endpoint := &tokenEndpoint{expiresIn: 3600, tokenType: "bearer"}
+	tokenServer := httptest.NewServer(endpoint)
+	defer tokenServer.Close()
+
+	// The API only accepts the second token, so the first request must be retried
+	api := &authorizingApi{api: conformance.NewRestApi(conformance.NewValidator(fixture)), accepted: "token-2"}
+	apiServer := httptest.NewServer(api)
+	defer apiServer.Close()
+	apiUrl, _ := url.Parse(apiServer.URL)
+
+	tokenSource := newClientCredentials(tokenServer, "secret")
+	clientTransport, err := rest.NewSawtoothClientTransportRestWithOptions(apiUrl, &rest.SawtoothClientTransportRestOptions{TokenSource: tokenSource})
+	if err != nil {
+		t.Fatalf("NewSawtoothClientTransportRest: %s", err)
+	}
+	block, err := clientTransport.GetBlock(fixture.Head().HeaderSignature)
+	if err != nil || block.HeaderSignature != fixture.Head().HeaderSignature {
+		t.Fatalf("GetBlock: got %v (%v)", block, err)
+	}
+	if api.rejected != 1 || endpoint.requests != 2 {
+		t.Fatalf("Retry: %d requests rejected and %d tokens issued, expected 1 and 2", api.rejected, endpoint.requests)
+	}
+
+	// A request is only retried once
+	api.rejected = 0
+	_, err = rest.NewSawtoothClientTransportRestWithOptions(apiUrl, &rest.SawtoothClientTransportRestOptions{TokenSource: rest.NewStaticTokenSource("wrong")})
+	if err == nil {
+		t.Fatalf("NewSawtoothClientTransportRest(wrong token): expected an error")
+	}
+	if api.rejected != 2 {
+		t.Fatalf("Retry(wrong token): %d requests rejected, expected 2", api.rejected)
+	}
+}
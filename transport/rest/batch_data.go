@@ -61,8 +61,8 @@ func (self *batchRestIterator) UnmarshalData(bytes []byte) ([]interface{}, error
 	}
 
 	result := make([]interface{}, len(response.Data))
-	for i, item := range response.Data {
-		result[i] = &item
+	for i := range response.Data {
+		result[i] = &response.Data[i]
 	}
 
 	return result, nil
@@ -70,21 +70,13 @@ func (self *batchRestIterator) UnmarshalData(bytes []byte) ([]interface{}, error
 
 // GetBatchIterator returns a types.BatchIterator that can iterate over all batches.
 func (self *SawtoothClientTransportRest) GetBatchIterator(fetch int, reverse bool) types.BatchIterator {
-	relativeUrl := &url.URL{Path: "/batches"}
-
-	query := relativeUrl.Query()
-	if fetch != 0 {
-		query.Add("limit", fmt.Sprintf("%d", fetch))
-	}
-	if reverse {
-		query.Add("reverse", "")
-	} else {
-		query.Add("reverse", "false")
-	}
-	relativeUrl.RawQuery = query.Encode()
+	return self.GetBatchIteratorFromCursor(types.NewCursor(types.CURSOR_BATCHES, fetch, reverse))
+}
 
+// GetBatchIteratorFromCursor returns a types.BatchIterator that resumes the iteration recorded by cursor.
+func (self *SawtoothClientTransportRest) GetBatchIteratorFromCursor(cursor *types.Cursor) types.BatchIterator {
 	iterator := &batchRestIterator{}
-	iterator.commonRestIterator = *NewCommonRestIterator(self, relativeUrl, iterator)
+	iterator.commonRestIterator = *newCursorRestIterator(self, "/batches", types.CURSOR_BATCHES, cursor, iterator)
 
 	return iterator
 }
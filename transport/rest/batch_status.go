@@ -38,7 +38,7 @@ func (self *SawtoothClientTransportRest) GetBatchStatusMultiple(batchIds []strin
 	if wait == 0 {
 		waitParam = "false"
 	} else {
-		waitParam = fmt.Sprint("%d", wait)
+		waitParam = fmt.Sprintf("%d", wait)
 	}
 
 	query := relativeUrl.Query()
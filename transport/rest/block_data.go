@@ -41,6 +41,25 @@ type blockRestIterator struct {
 	commonRestIterator
 }
 
+// Next returns true if a next value is available, ending the iteration at the cursor's stop block.
+func (self *blockRestIterator) Next() bool {
+	if !self.commonRestIterator.Next() {
+		return false
+	}
+
+	past, err := self.cursor.PastStopBlock(self.getCurrent().(*types.Block))
+	if err != nil {
+		self.err = err
+		return false
+	}
+	if past {
+		self.Close()
+		return false
+	}
+
+	return true
+}
+
 // Current returns the "current" block from the iterator.
 func (self *blockRestIterator) Current() (*types.Block, error) {
 	err := self.checkCurrent()
@@ -61,8 +80,8 @@ func (self *blockRestIterator) UnmarshalData(bytes []byte) ([]interface{}, error
 	}
 
 	result := make([]interface{}, len(response.Data))
-	for i, item := range response.Data {
-		result[i] = &item
+	for i := range response.Data {
+		result[i] = &response.Data[i]
 	}
 
 	return result, nil
@@ -70,21 +89,57 @@ func (self *blockRestIterator) UnmarshalData(bytes []byte) ([]interface{}, error
 
 // GetBlockIterator returns a types.BlockIterator that can iterate over all blocks.
 func (self *SawtoothClientTransportRest) GetBlockIterator(fetch int, reverse bool) types.BlockIterator {
-	relativeUrl := &url.URL{Path: "/blocks"}
+	return self.GetBlockIteratorFromCursor(types.NewCursor(types.CURSOR_BLOCKS, fetch, reverse))
+}
 
-	query := relativeUrl.Query()
-	if fetch != 0 {
-		query.Add("limit", fmt.Sprintf("%d", fetch))
+// GetBlockIteratorFromCursor returns a types.BlockIterator that resumes the iteration recorded by cursor.
+func (self *SawtoothClientTransportRest) GetBlockIteratorFromCursor(cursor *types.Cursor) types.BlockIterator {
+	clamped, err := types.ClampBlockCursor(cursor, func() types.BlockIterator {
+		return self.GetBlockIterator(1, false)
+	})
+	if err != nil {
+		return self.newBlockIteratorWithError(err)
 	}
-	if reverse {
-		query.Add("reverse", "")
-	} else {
-		query.Add("reverse", "false")
+
+	iterator := &blockRestIterator{}
+	if clamped == nil {
+		// Nothing to list yet, but the cursor can still be resumed once blocks are added
+		iterator.commonRestIterator = *newCursorRestIterator(self, "/blocks", types.CURSOR_BLOCKS, cursor, iterator)
+		iterator.nextUrl = nil
+		return iterator
 	}
-	relativeUrl.RawQuery = query.Encode()
+	iterator.commonRestIterator = *newCursorRestIterator(self, "/blocks", types.CURSOR_BLOCKS, clamped, iterator)
+
+	return iterator
+}
+
+// GetBlockRangeIterator returns a types.BlockIterator over the blocks numbered first to last
+// (inclusive), newest first unless reverse is true.
+func (self *SawtoothClientTransportRest) GetBlockRangeIterator(first uint64, last uint64, fetch int, reverse bool) types.BlockIterator {
+	cursor, err := types.NewBlockRangeCursor(first, last, fetch, reverse)
+	if err != nil {
+		return self.newBlockIteratorWithError(err)
+	}
+
+	return self.GetBlockIteratorFromCursor(cursor)
+}
+
+// GetBlockIteratorSince returns a types.BlockIterator over the blocks following blockId, oldest
+// first, up to the chain head.
+func (self *SawtoothClientTransportRest) GetBlockIteratorSince(blockId string, fetch int) types.BlockIterator {
+	block, err := self.GetBlock(blockId)
+	if err != nil {
+		return self.newBlockIteratorWithError(err)
+	}
+
+	return self.GetBlockIteratorFromCursor(types.NewBlockSinceCursor(block.Header.BlockNum, fetch))
+}
 
+// newBlockIteratorWithError returns a block iterator that yields no values and reports err (if any).
+func (self *SawtoothClientTransportRest) newBlockIteratorWithError(err error) types.BlockIterator {
 	iterator := &blockRestIterator{}
-	iterator.commonRestIterator = *NewCommonRestIterator(self, relativeUrl, iterator)
+	iterator.commonRestIterator = *NewCommonRestIterator(self, nil, iterator)
+	iterator.err = err
 
 	return iterator
 }
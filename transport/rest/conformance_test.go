@@ -0,0 +1,14 @@
+package rest_test
+
+import (
+	"testing"
+
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, validator *conformance.Validator) transport.SawtoothClientTransport {
+		return conformance.NewRestTransport(t, validator)
+	})
+}
@@ -3,11 +3,13 @@ package rest
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
 	"net/url"
 )
 
 // commonRestPagingData represents the paging data in a REST API reply.
 type commonRestPagingData struct {
+	Head   string `json:"head"`
 	Paging struct {
 		Limit        int    `json:"limit"`
 		Next         string `json:"next"`
@@ -21,6 +23,14 @@ type restIteratorImpl interface {
 	UnmarshalData(bytes []byte) ([]interface{}, error)
 }
 
+// restIteratorPage holds a single page of results.
+type restIteratorPage struct {
+	data	[]interface{}
+	start	string
+	head	string
+	err		error
+}
+
 // commonRestIterator implements an iterator for the REST API that can be extended to be used
 // across multiple object types.
 type commonRestIterator struct {
@@ -31,12 +41,67 @@ type commonRestIterator struct {
 	current		interface{}
 	err			error
 
+	// prefetch is the number of pages to read ahead in the background (0 disables prefetching).
+	prefetch	int
+	pages		chan restIteratorPage
+	stop		chan struct{}
+
+	// cursor holds the query parameters of the iteration; head, pageStart and consumed track
+	// the position of the caller, and skip is the number of values still to be dropped when
+	// resuming from a cursor.
+	cursor		types.Cursor
+	head		string
+	pageStart	string
+	consumed	int
+	skip		int
+
 	impl restIteratorImpl
 }
 
 // NewCommonRestIterator returns a new commonRestIterator for use in composing a usable object iterator.
 func NewCommonRestIterator(transport *SawtoothClientTransportRest, nextUrl *url.URL, impl restIteratorImpl) *commonRestIterator {
-	return &commonRestIterator{transport: transport, nextUrl: nextUrl, impl: impl}
+	return &commonRestIterator{transport: transport, nextUrl: nextUrl, impl: impl, prefetch: transport.Prefetch}
+}
+
+// newCursorRestIterator returns a new commonRestIterator for the resource at path, positioned
+// according to cursor. If the cursor is not valid for kind, the iterator reports the error.
+func newCursorRestIterator(transport *SawtoothClientTransportRest, path string, kind types.CursorKind, cursor *types.Cursor, impl restIteratorImpl) *commonRestIterator {
+	err := cursor.Check(kind)
+	if err != nil {
+		iterator := NewCommonRestIterator(transport, nil, impl)
+		iterator.err = err
+		return iterator
+	}
+
+	relativeUrl := &url.URL{Path: path}
+
+	query := relativeUrl.Query()
+	if cursor.Address != "" || kind == types.CURSOR_STATE {
+		query.Add("address", cursor.Address)
+	}
+	if cursor.Head != "" {
+		query.Add("head", cursor.Head)
+	}
+	if cursor.Start != "" {
+		query.Add("start", cursor.Start)
+	}
+	if cursor.Limit != 0 {
+		query.Add("limit", fmt.Sprintf("%d", cursor.Limit))
+	}
+	if cursor.Reverse {
+		query.Add("reverse", "")
+	} else {
+		query.Add("reverse", "false")
+	}
+	relativeUrl.RawQuery = query.Encode()
+
+	iterator := NewCommonRestIterator(transport, relativeUrl, impl)
+	iterator.cursor = *cursor
+	iterator.head = cursor.Head
+	iterator.pageStart = cursor.Start
+	iterator.skip = cursor.Skip
+
+	return iterator
 }
 
 // Next returns true if a next value is available.
@@ -53,6 +118,7 @@ func (self *commonRestIterator) Next() bool {
 
 	// Pop and shift
 	self.current, self.data = self.data[0], self.data[1:]
+	self.consumed++
 
 	return true
 }
@@ -62,50 +128,184 @@ func (self *commonRestIterator) Error() error {
 	return self.err
 }
 
+// SetPrefetch sets the number of pages to fetch in the background ahead of the caller.
+// It must be called before the first call to Next().
+func (self *commonRestIterator) SetPrefetch(depth int) {
+	self.prefetch = depth
+}
+
+// Close stops any background prefetching. The iterator returns no further values.
+func (self *commonRestIterator) Close() {
+	if self.stop != nil {
+		close(self.stop)
+		self.stop = nil
+	}
+	self.nextUrl = nil
+	self.pages = nil
+	self.data = nil
+}
+
+// Cursor returns a cursor recording the position of the iterator.
+func (self *commonRestIterator) Cursor() (*types.Cursor, error) {
+	if self.err != nil {
+		return nil, self.err
+	}
+
+	cursor := self.cursor
+	if !cursor.Since {
+		cursor.Head = self.head
+	}
+	cursor.Start = self.pageStart
+	cursor.Skip = self.consumed + self.skip
+
+	return &cursor, nil
+}
+
 // fetchNext get the next value or batch of values from the REST API.
 func (self *commonRestIterator) fetchNext() error {
 	if len(self.data) > 0 {
 		return nil
 	}
 
-	if self.nextUrl == nil {
+	if self.err != nil {
 		return nil
 	}
 
-	if self.err != nil {
-		return nil
+	if self.prefetch > 0 || self.pages != nil {
+		return self.fetchNextPrefetched()
 	}
 
+	for len(self.data) == 0 && self.nextUrl != nil {
+		page, nextUrl := self.fetchPage(self.nextUrl)
+		if page.err != nil {
+			return page.err
+		}
+		self.nextUrl = nextUrl
+		self.setPage(page)
+	}
+
+	return nil
+}
+
+// fetchNextPrefetched takes the next page from the background fetcher, starting it if required.
+func (self *commonRestIterator) fetchNextPrefetched() error {
+	if self.pages == nil {
+		if self.nextUrl == nil {
+			return nil
+		}
+		self.startPrefetch()
+	}
+
+	for len(self.data) == 0 {
+		page, ok := <-self.pages
+		if !ok {
+			self.pages = nil
+			return nil
+		}
+
+		if page.err != nil {
+			return page.err
+		}
+		self.setPage(page)
+	}
+
+	return nil
+}
+
+// setPage makes page the current page, dropping any values that were already consumed before
+// the iterator was resumed from a cursor.
+func (self *commonRestIterator) setPage(page restIteratorPage) {
+	if self.head == "" {
+		self.head = page.head
+	}
+	self.pageStart = page.start
+	self.consumed = 0
+	self.data = page.data
+
+	if self.skip > 0 {
+		skip := self.skip
+		if skip > len(self.data) {
+			skip = len(self.data)
+		}
+		self.data = self.data[skip:]
+		self.consumed = skip
+		self.skip -= skip
+	}
+}
+
+// startPrefetch starts a goroutine that fetches pages ahead of the caller, keeping up to
+// self.prefetch pages buffered.
+func (self *commonRestIterator) startPrefetch() {
+	pages := make(chan restIteratorPage, self.prefetch)
+	stop := make(chan struct{})
+	nextUrl := self.nextUrl
+
+	self.pages = pages
+	self.stop = stop
+	self.nextUrl = nil
+
+	go func() {
+		defer close(pages)
+
+		for nextUrl != nil {
+			var page restIteratorPage
+			page, nextUrl = self.fetchPage(nextUrl)
+
+			select {
+			case pages <- page:
+			case <-stop:
+				return
+			}
+
+			if page.err != nil {
+				return
+			}
+		}
+	}()
+}
+
+// fetchPage fetches a single page from the REST API, returning it along with the URL of the
+// next page (nil if there are no more pages).
+func (self *commonRestIterator) fetchPage(pageUrl *url.URL) (restIteratorPage, *url.URL) {
+	page := restIteratorPage{start: pageUrl.Query().Get("start")}
+
 	// Do the request to the api
-	bytes, err := self.transport.doGetRequest(self.nextUrl)
+	bytes, err := self.transport.doGetRequest(pageUrl)
 	if err != nil {
-		return err
+		page.err = err
+		return page, nil
 	}
 
 	// Unmarshal the actual data
-	self.data, err = self.impl.UnmarshalData(bytes)
+	page.data, err = self.impl.UnmarshalData(bytes)
 	if err != nil {
-		return err
+		page.err = err
+		return page, nil
 	}
 
 	// Unmarshal the paging info
 	var pagingData commonRestPagingData
 	err = json.Unmarshal(bytes, &pagingData)
+	page.head = pagingData.Head
 	nextRawUrl := pagingData.Paging.Next
 	if nextRawUrl == "" {
-		self.nextUrl = nil
-		return nil
+		return page, nil
 	}
 
 	// Parse the next url
 	nextUrl, err := url.Parse(nextRawUrl)
 	if err != nil {
-		self.nextUrl = nil
-	} else {
-		self.nextUrl = nextUrl
+		return page, nil
 	}
 
-	return nil
+	// Make sure the following pages are read from the same head
+	if page.head != "" && nextUrl.Query().Get("head") == "" {
+		query := nextUrl.Query()
+		query.Set("head", page.head)
+		nextUrl.RawQuery = query.Encode()
+	}
+
+	return page, nextUrl
 }
 
 // checkCurrent checks to make sure there is a current value in the iterator. If no current
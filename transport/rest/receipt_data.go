@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+	"net/url"
+)
+
+// receiptRestResponse represents a REST API reply when transaction receipts are requested.
+type receiptRestResponse struct {
+	Data	[]types.TransactionReceipt	`json:"data"`
+}
+
+// GetTransactionReceipt returns the receipt of the committed transaction represented by transactionId.
+func (self *SawtoothClientTransportRest) GetTransactionReceipt(transactionId string) (*types.TransactionReceipt, error) {
+	receipts, err := self.GetTransactionReceipts([]string{transactionId})
+	if err != nil {
+		return nil, err
+	}
+
+	return receipts[transactionId], nil
+}
+
+// GetTransactionReceipts returns the receipts of a list of committed transactions. If any of the
+// transactions has no receipt, an error with code TRANSACTION_RECEIPT_NOT_FOUND is returned.
+func (self *SawtoothClientTransportRest) GetTransactionReceipts(transactionIds []string) (map[string]*types.TransactionReceipt, error) {
+	// The ids are posted, as a long list may not fit in a query string
+	relativeUrl := &url.URL{Path: "/receipts"}
+
+	body, err := json.Marshal(transactionIds)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := self.doPostRequestJson(relativeUrl, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var response receiptRestResponse
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	resultMap := make(map[string]*types.TransactionReceipt, len(response.Data))
+	for i := range response.Data {
+		resultMap[response.Data[i].TransactionId] = &response.Data[i]
+	}
+
+	return resultMap, nil
+}
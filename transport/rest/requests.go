@@ -23,9 +23,8 @@ func (self *SawtoothClientTransportRest) resolveReference(relativeUrl *url.URL)
 }
 
 // buildRequest wraps http.NewRequest and sets up the headers as we require them.
-// In particular we set Accept to "application/json", and check the URL for authentication
-// information. If the username specified is "bearer", we add an Authorization header set to
-// "Bearer <value_of_password_field>".
+// In particular we set Accept to "application/json", and if a TokenSource is configured,
+// we add an Authorization header set to "Bearer <token>".
 func (self *SawtoothClientTransportRest) buildRequest(method string, url *url.URL, body io.Reader) (*http.Request, error){
 	urlString := url.String()
 
@@ -35,27 +34,56 @@ func (self *SawtoothClientTransportRest) buildRequest(method string, url *url.UR
 	}
 	request.Header.Set("Accept", "application/json")
 
-	authMethod := url.User.Username()
-	authSecret, authSecretPresent := url.User.Password()
-
-	if authMethod == "bearer" && authSecretPresent {
-		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", authSecret))
+	if self.TokenSource != nil {
+		token, err := self.TokenSource.Token()
+		if err != nil {
+			return nil, fmt.Errorf("Error obtaining bearer token: %s", err)
+		}
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
 	}
 
 	return request, nil
 }
 
+// doRequest builds and sends a request. If the REST API responds with 401 Unauthorized and a
+// TokenSource is configured, the token is invalidated and the request is retried once.
+func (self *SawtoothClientTransportRest) doRequest(method string, fullUrl *url.URL, data []byte, contentType string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		var body io.Reader
+		if data != nil {
+			body = bytes.NewReader(data)
+		}
+
+		request, err := self.buildRequest(method, fullUrl, body)
+		if err != nil {
+			return nil, err
+		}
+		if contentType != "" {
+			request.Header.Set("Content-Type", contentType)
+		}
+
+		response, err := self.HttpClient.Do(request)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode == http.StatusUnauthorized && self.TokenSource != nil && attempt == 0 {
+			_, _ = io.Copy(ioutil.Discard, response.Body)
+			response.Body.Close()
+			self.TokenSource.Invalidate()
+			continue
+		}
+
+		return response, nil
+	}
+}
+
 // doGetRequest provides a generalized GET call to the REST API. Returns the response as
 // a []byte slice, or an error if something goes wrong.
 func (self *SawtoothClientTransportRest) doGetRequest(relativeUrl *url.URL) ([]byte, error) {
 	fullUrl := self.resolveReference(relativeUrl)
 
-	request, err := self.buildRequest(http.MethodGet, fullUrl, nil)
-	if err != nil {
-		return nil, errors.NewSawtoothClientTransportRequestError(err)
-	}
-
-	response, err := self.HttpClient.Do(request)
+	response, err := self.doRequest(http.MethodGet, fullUrl, nil, "")
 	if err != nil {
 		return nil, errors.NewSawtoothClientTransportRequestError(err)
 	}
@@ -94,13 +122,7 @@ func (self *SawtoothClientTransportRest) doPostRequestJson(relativeUrl *url.URL,
 func (self *SawtoothClientTransportRest) doPostRequest(relativeUrl *url.URL, data []byte, contentType string) ([]byte, error) {
 	fullUrl := self.resolveReference(relativeUrl)
 
-	request, err := self.buildRequest(http.MethodPost, fullUrl, bytes.NewBuffer(data))
-	if err != nil {
-		return nil, errors.NewSawtoothClientTransportRequestError(err)
-	}
-	request.Header.Set("Content-Type", contentType)
-
-	response, err := self.HttpClient.Do(request)
+	response, err := self.doRequest(http.MethodPost, fullUrl, data, contentType)
 	if err != nil {
 		return nil, errors.NewSawtoothClientTransportRequestError(err)
 	}
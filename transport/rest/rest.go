@@ -4,26 +4,94 @@ package rest
 import (
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 )
 
 // HTTP_TIMEOUT is the default timeout.
 const HTTP_TIMEOUT = time.Second * 60
 
+// DEFAULT_MAX_CONCURRENCY is the default number of parallel requests made by bulk operations.
+const DEFAULT_MAX_CONCURRENCY = 8
+
+// STATE_ROOT_SEARCH_FETCH is the page size used when searching blocks for a state root.
+const STATE_ROOT_SEARCH_FETCH = 100
+
+// STATE_ROOT_SEARCH_DEPTH is the number of blocks, back from the chain head, searched for a state root.
+const STATE_ROOT_SEARCH_DEPTH = 1000
+
 // SawtoothClientTransportRest represents a connection to the REST API.
 type SawtoothClientTransportRest struct {
 	// URL is the URL to the REST API.
 	URL			*url.URL
 	// HttpClient is the client that is maintained throughout the life of this object.
 	HttpClient	*http.Client
+	// TokenSource provides bearer tokens for requests (nil if no authentication is required).
+	TokenSource	TokenSource
+	// MaxConcurrency is the number of parallel requests made by bulk operations such as GetStates.
+	MaxConcurrency	int
+	// Prefetch is the default number of pages iterators read ahead in the background (0 disables).
+	Prefetch		int
+
+	// stateRootHeads caches the block ids found for state roots by headForStateRoot.
+	stateRootHeads	map[string]string
+	stateRootMutex	sync.Mutex
+}
+
+// SawtoothClientTransportRestOptions holds optional settings for SawtoothClientTransportRest.
+type SawtoothClientTransportRestOptions struct {
+	// HttpClient overrides the default HTTP client.
+	HttpClient	*http.Client
+	// TokenSource provides bearer tokens used to authenticate each request.
+	TokenSource	TokenSource
+	// MaxConcurrency overrides DEFAULT_MAX_CONCURRENCY.
+	MaxConcurrency	int
+	// Prefetch is the default number of pages iterators read ahead in the background (0 disables).
+	Prefetch		int
 }
 
 // NewSawtoothClientTransportRest returns a new SawtoothClientTransportRest for the given URL.
 // Returns an error if a test request to the API does not succeed.
 func NewSawtoothClientTransportRest(url *url.URL) (*SawtoothClientTransportRest, error) {
+	return NewSawtoothClientTransportRestWithOptions(url, nil)
+}
+
+// NewSawtoothClientTransportRestWithOptions returns a new SawtoothClientTransportRest for the given
+// URL, configured with the given options (which may be nil).
+//
+// For backwards compatibility, if no TokenSource is configured and the URL contains the username
+// "bearer" with a password, the password is used as a static bearer token. Configuring a
+// TokenSource is preferred, as it keeps the secret out of the URL.
+// Returns an error if a test request to the API does not succeed.
+func NewSawtoothClientTransportRestWithOptions(url *url.URL, options *SawtoothClientTransportRestOptions) (*SawtoothClientTransportRest, error) {
 	client := &SawtoothClientTransportRest{
 		URL: url,
-		HttpClient: &http.Client{Timeout: HTTP_TIMEOUT},
+		MaxConcurrency: DEFAULT_MAX_CONCURRENCY,
+	}
+
+	if options != nil {
+		if options.HttpClient != nil {
+			client.HttpClient = options.HttpClient
+		}
+		client.TokenSource = options.TokenSource
+		if options.MaxConcurrency > 0 {
+			client.MaxConcurrency = options.MaxConcurrency
+		}
+		client.Prefetch = options.Prefetch
+	}
+
+	// Keep enough idle connections around to serve parallel bulk requests
+	if client.HttpClient == nil {
+		httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+		httpTransport.MaxIdleConnsPerHost = client.MaxConcurrency
+		client.HttpClient = &http.Client{Timeout: HTTP_TIMEOUT, Transport: httpTransport}
+	}
+
+	if client.TokenSource == nil && url.User != nil {
+		authSecret, authSecretPresent := url.User.Password()
+		if url.User.Username() == "bearer" && authSecretPresent {
+			client.TokenSource = NewStaticTokenSource(authSecret)
+		}
 	}
 
 	err := client.testConnection()
@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/errors"
 	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
 	"net/url"
 )
@@ -28,8 +29,37 @@ type stateRestResponseMultiple struct {
 
 // GetState returns the state at the given address.
 func (self *SawtoothClientTransportRest) GetState(address string) (*types.State, error) {
+	return self.getState(address, "")
+}
+
+// GetStateAtHead returns the state at the given address, at the given head.
+func (self *SawtoothClientTransportRest) GetStateAtHead(address string, head string) (*types.State, error) {
+	return self.getState(address, head)
+}
+
+// GetStateAtRoot returns the state at the given address, at the given state root.
+// The REST API can only query state by head, so the state root is first resolved to a block
+// (see headForStateRoot).
+func (self *SawtoothClientTransportRest) GetStateAtRoot(address string, stateRoot string) (*types.State, error) {
+	head, err := self.headForStateRoot(stateRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.getState(address, head)
+}
+
+// getState is used to implement GetState(), GetStateAtHead() and GetStateAtRoot(). If head is
+// empty, the REST API uses the current chain head.
+func (self *SawtoothClientTransportRest) getState(address string, head string) (*types.State, error) {
 	relativeUrl := &url.URL{Path: fmt.Sprintf("/state/%s", address)}
 
+	if head != "" {
+		query := relativeUrl.Query()
+		query.Add("head", head)
+		relativeUrl.RawQuery = query.Encode()
+	}
+
 	data, err := self.doGetRequest(relativeUrl)
 	if err != nil {
 		return nil, err
@@ -49,31 +79,47 @@ func (self *SawtoothClientTransportRest) GetState(address string) (*types.State,
 	return &types.State{Data: dataBytes, Address: address, Head: response.Head}, nil
 }
 
-// GetStateAtHead returns the state at the given address, at the given head.
-func (self *SawtoothClientTransportRest) GetStateAtHead(address string, head string) (*types.State, error) {
-	relativeUrl := &url.URL{Path: fmt.Sprintf("/state/%s", address)}
+// headForStateRoot finds the id of a block whose header has the given state root, searching
+// backwards from the chain head up to STATE_ROOT_SEARCH_DEPTH blocks. Results are cached, since
+// state roots never change blocks.
+func (self *SawtoothClientTransportRest) headForStateRoot(stateRoot string) (string, error) {
+	self.stateRootMutex.Lock()
+	head, ok := self.stateRootHeads[stateRoot]
+	self.stateRootMutex.Unlock()
+	if ok {
+		return head, nil
+	}
 
-	query := relativeUrl.Query()
-	query.Add("head", head)
-	relativeUrl.RawQuery = query.Encode()
+	iterator := self.GetBlockIterator(STATE_ROOT_SEARCH_FETCH, false)
+	defer iterator.Close()
 
-	data, err := self.doGetRequest(relativeUrl)
-	if err != nil {
-		return nil, err
-	}
+	for searched := 0; searched < STATE_ROOT_SEARCH_DEPTH && iterator.Next(); searched++ {
+		block, err := iterator.Current()
+		if err != nil {
+			return "", err
+		}
 
-	var response stateRestResponseSingle
-	err = json.Unmarshal(data, &response)
-	if err != nil {
-		return nil, err
+		if block.Header.StateRootHash == stateRoot {
+			self.stateRootMutex.Lock()
+			if self.stateRootHeads == nil {
+				self.stateRootHeads = make(map[string]string)
+			}
+			self.stateRootHeads[stateRoot] = block.HeaderSignature
+			self.stateRootMutex.Unlock()
+
+			return block.HeaderSignature, nil
+		}
 	}
 
-	dataBytes, err := base64.StdEncoding.DecodeString(response.Data)
+	err := iterator.Error()
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return &types.State{Data: dataBytes, Address: address, Head: response.Head}, nil
+	return "", &errors.SawtoothClientTransportError{
+		ErrorCode: errors.INVALID_HEAD,
+		ErrorObject: fmt.Errorf("No block found with state root %s in the last %d blocks", stateRoot, STATE_ROOT_SEARCH_DEPTH),
+	}
 }
 
 // stateRestIterator extends commonRestIterator and implements the types.StateIterator interface.
@@ -112,24 +158,60 @@ func (self *stateRestIterator) UnmarshalData(bytes []byte) ([]interface{}, error
 	return result, nil
 }
 
-// GetStateData returns a types.StateIterator that can iterate over all state matching the given prefix.
+// GetStateIterator returns a types.StateIterator that can iterate over all state matching the given prefix.
 func (self *SawtoothClientTransportRest) GetStateIterator(addressPrefix string, fetch int, reverse bool) types.StateIterator {
-	relativeUrl := &url.URL{Path: "/state"}
+	return self.getStateIterator(addressPrefix, "", fetch, reverse)
+}
 
-	query := relativeUrl.Query()
-	query.Add("address", addressPrefix)
-	if fetch != 0 {
-		query.Add("limit", fmt.Sprintf("%d", fetch))
+// GetStateIteratorAtHead returns a types.StateIterator that can iterate over all state matching the
+// given prefix, at the given head.
+func (self *SawtoothClientTransportRest) GetStateIteratorAtHead(addressPrefix string, head string, fetch int, reverse bool) types.StateIterator {
+	return self.getStateIterator(addressPrefix, head, fetch, reverse)
+}
+
+// GetStateIteratorAtRoot returns a types.StateIterator that can iterate over all state matching the
+// given prefix, at the given state root.
+func (self *SawtoothClientTransportRest) GetStateIteratorAtRoot(addressPrefix string, stateRoot string, fetch int, reverse bool) types.StateIterator {
+	head, err := self.headForStateRoot(stateRoot)
+	if err != nil {
+		iterator := &stateRestIterator{}
+		iterator.commonRestIterator = *NewCommonRestIterator(self, nil, iterator)
+		iterator.err = err
+		return iterator
 	}
-	if reverse {
-		query.Add("reverse", "")
-	} else {
-		query.Add("reverse", "false")
+
+	return self.getStateIterator(addressPrefix, head, fetch, reverse)
+}
+
+// GetStateIteratorFromCursor returns a types.StateIterator that resumes the iteration recorded by cursor.
+func (self *SawtoothClientTransportRest) GetStateIteratorFromCursor(cursor *types.Cursor) types.StateIterator {
+	// Cursors created from a state root (over ZMQ) may not have a head
+	if cursor != nil && cursor.Head == "" && cursor.StateRoot != "" {
+		head, err := self.headForStateRoot(cursor.StateRoot)
+		if err != nil {
+			iterator := &stateRestIterator{}
+			iterator.commonRestIterator = *NewCommonRestIterator(self, nil, iterator)
+			iterator.err = err
+			return iterator
+		}
+
+		resolved := *cursor
+		resolved.Head = head
+		cursor = &resolved
 	}
-	relativeUrl.RawQuery = query.Encode()
 
 	iterator := &stateRestIterator{}
-	iterator.commonRestIterator = *NewCommonRestIterator(self, relativeUrl, iterator)
+	iterator.commonRestIterator = *newCursorRestIterator(self, "/state", types.CURSOR_STATE, cursor, iterator)
 
 	return iterator
 }
+
+// getStateIterator is used to implement the state iterator methods. If head is empty, the REST
+// API uses the current chain head.
+func (self *SawtoothClientTransportRest) getStateIterator(addressPrefix string, head string, fetch int, reverse bool) types.StateIterator {
+	cursor := types.NewCursor(types.CURSOR_STATE, fetch, reverse)
+	cursor.Address = addressPrefix
+	cursor.Head = head
+
+	return self.GetStateIteratorFromCursor(cursor)
+}
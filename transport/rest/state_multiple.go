@@ -0,0 +1,83 @@
+package rest
+
+import (
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/errors"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+	"sync"
+)
+
+// GetStates returns the state at each of the given addresses, all read at the current chain head.
+// Addresses that have no state are present in the result with a nil value.
+func (self *SawtoothClientTransportRest) GetStates(addresses []string) (map[string]*types.State, error) {
+	head, err := types.HeadBlock(self.GetBlockIterator(1, false))
+	if err != nil {
+		return nil, err
+	}
+
+	return self.GetStatesAtHead(addresses, head.HeaderSignature)
+}
+
+// GetStatesAtHead returns the state at each of the given addresses, at the given head. Requests are
+// made in parallel, with at most MaxConcurrency requests in flight.
+// Addresses that have no state are present in the result with a nil value.
+func (self *SawtoothClientTransportRest) GetStatesAtHead(addresses []string, head string) (map[string]*types.State, error) {
+	result := make(map[string]*types.State, len(addresses))
+	var resultMutex sync.Mutex
+	var firstErr error
+
+	concurrency := self.MaxConcurrency
+	if concurrency <= 0 {
+		concurrency = DEFAULT_MAX_CONCURRENCY
+	}
+
+	// Feed the (deduplicated) addresses to the workers
+	addressChannel := make(chan string)
+	done := make(chan struct{})
+	go func() {
+		defer close(addressChannel)
+		seen := make(map[string]bool, len(addresses))
+		for _, address := range addresses {
+			if seen[address] {
+				continue
+			}
+			seen[address] = true
+
+			select {
+			case addressChannel <- address:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for address := range addressChannel {
+				state, err := self.getState(address, head)
+				if err != nil && !errors.HasErrorCode(err, errors.STATE_NOT_FOUND) {
+					resultMutex.Lock()
+					if firstErr == nil {
+						firstErr = err
+						close(done)
+					}
+					resultMutex.Unlock()
+					return
+				}
+
+				resultMutex.Lock()
+				result[address] = state
+				resultMutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return result, nil
+}
@@ -61,8 +61,8 @@ func (self *transactionRestIterator) UnmarshalData(bytes []byte) ([]interface{},
 	}
 
 	result := make([]interface{}, len(response.Data))
-	for i, item := range response.Data {
-		result[i] = &item
+	for i := range response.Data {
+		result[i] = &response.Data[i]
 	}
 
 	return result, nil
@@ -70,21 +70,13 @@ func (self *transactionRestIterator) UnmarshalData(bytes []byte) ([]interface{},
 
 // GetTransactionIterator returns a types.TransactionIterator that can iterate over all transactions.
 func (self *SawtoothClientTransportRest) GetTransactionIterator(fetch int, reverse bool) types.TransactionIterator {
-	relativeUrl := &url.URL{Path: "/transactions"}
-
-	query := relativeUrl.Query()
-	if fetch != 0 {
-		query.Add("limit", fmt.Sprintf("%d", fetch))
-	}
-	if reverse {
-		query.Add("reverse", "")
-	} else {
-		query.Add("reverse", "false")
-	}
-	relativeUrl.RawQuery = query.Encode()
+	return self.GetTransactionIteratorFromCursor(types.NewCursor(types.CURSOR_TRANSACTIONS, fetch, reverse))
+}
 
+// GetTransactionIteratorFromCursor returns a types.TransactionIterator that resumes the iteration recorded by cursor.
+func (self *SawtoothClientTransportRest) GetTransactionIteratorFromCursor(cursor *types.Cursor) types.TransactionIterator {
 	iterator := &transactionRestIterator{}
-	iterator.commonRestIterator = *NewCommonRestIterator(self, relativeUrl, iterator)
+	iterator.commonRestIterator = *newCursorRestIterator(self, "/transactions", types.CURSOR_TRANSACTIONS, cursor, iterator)
 
 	return iterator
 }
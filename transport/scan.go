@@ -0,0 +1,233 @@
+package transport
+
+import (
+	"fmt"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+	"sync"
+)
+
+// DEFAULT_SCAN_CONCURRENCY is the default number of sub-prefixes scanned concurrently by ScanState.
+const DEFAULT_SCAN_CONCURRENCY = 8
+
+// DEFAULT_SCAN_SPLIT_DEPTH is the default number of hex characters appended to the prefix to
+// produce the sub-prefixes scanned by ScanState (2 gives 256 sub-prefixes).
+const DEFAULT_SCAN_SPLIT_DEPTH = 2
+
+// ScanStateOptions holds optional settings for ScanState.
+type ScanStateOptions struct {
+	// Head pins the scan to a block id. If empty, the current chain head is used.
+	Head			string
+	// Concurrency is the number of sub-prefixes paged concurrently.
+	Concurrency		int
+	// SplitDepth is the number of hex characters appended to the prefix to form sub-prefixes. It
+	// is rounded up to an even number, as the validator only lists whole two-character tokens.
+	SplitDepth		int
+	// Fetch is the page size used for each sub-prefix (0 uses the transport default).
+	Fetch			int
+	// Prefetch is the number of pages each sub-prefix iterator reads ahead.
+	Prefetch		int
+}
+
+// ScanState returns a types.StateIterator over all state matching addressPrefix. The prefix is
+// split into sub-prefixes which are paged concurrently, all at the same head, which makes this
+// much faster than GetStateIterator for large namespaces.
+//
+// Unlike GetStateIterator, states are returned in no particular order.
+func ScanState(transport SawtoothClientTransport, addressPrefix string, options *ScanStateOptions) types.StateIterator {
+	scanOptions := ScanStateOptions{Concurrency: DEFAULT_SCAN_CONCURRENCY, SplitDepth: DEFAULT_SCAN_SPLIT_DEPTH}
+	if options != nil {
+		scanOptions.Head = options.Head
+		scanOptions.Fetch = options.Fetch
+		scanOptions.Prefetch = options.Prefetch
+		if options.Concurrency > 0 {
+			scanOptions.Concurrency = options.Concurrency
+		}
+		if options.SplitDepth > 0 {
+			scanOptions.SplitDepth = options.SplitDepth
+		}
+	}
+
+	iterator := &scanStateIterator{
+		results: make(chan *types.State, scanOptions.Concurrency * 64),
+		stop: make(chan struct{}),
+	}
+
+	// Pin the scan to a single head
+	if scanOptions.Head == "" {
+		head, err := CurrentHead(transport)
+		if err != nil {
+			iterator.err = err
+			close(iterator.results)
+			return iterator
+		}
+		scanOptions.Head = head
+	}
+
+	go iterator.run(transport, splitAddressPrefix(addressPrefix, scanOptions.SplitDepth), &scanOptions)
+
+	return iterator
+}
+
+// splitAddressPrefix returns the sub-prefixes formed by appending every combination of depth hex
+// characters to prefix. An odd depth is rounded up, and the depth is reduced if the sub-prefixes
+// would exceed a full address.
+func splitAddressPrefix(prefix string, depth int) []string {
+	if depth % 2 != 0 {
+		depth++
+	}
+	if len(prefix) + depth > types.ADDRESS_LENGTH {
+		depth = types.ADDRESS_LENGTH - len(prefix)
+	}
+
+	prefixes := []string{prefix}
+	for i := 0; i < depth; i++ {
+		next := make([]string, 0, len(prefixes) * 16)
+		for _, p := range prefixes {
+			for _, c := range "0123456789abcdef" {
+				next = append(next, p + string(c))
+			}
+		}
+		prefixes = next
+	}
+
+	return prefixes
+}
+
+// CurrentHead returns the id of the block at the current chain head.
+func CurrentHead(transport SawtoothClientTransport) (string, error) {
+	block, err := types.HeadBlock(transport.GetBlockIterator(1, false))
+	if err != nil {
+		return "", err
+	}
+
+	return block.HeaderSignature, nil
+}
+
+// scanStateIterator implements types.StateIterator for ScanState.
+type scanStateIterator struct {
+	results		chan *types.State
+	stop		chan struct{}
+	stopOnce	sync.Once
+
+	current		*types.State
+	err			error
+	errMutex	sync.Mutex
+}
+
+// run scans the sub-prefixes using a pool of workers and closes the results channel when done.
+func (self *scanStateIterator) run(transport SawtoothClientTransport, prefixes []string, options *ScanStateOptions) {
+	defer close(self.results)
+
+	prefixChannel := make(chan string)
+	go func() {
+		defer close(prefixChannel)
+		for _, prefix := range prefixes {
+			select {
+			case prefixChannel <- prefix:
+			case <-self.stop:
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < options.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for prefix := range prefixChannel {
+				err := self.scanPrefix(transport, prefix, options)
+				if err != nil {
+					self.setError(err)
+					self.Close()
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// scanPrefix pages through a single sub-prefix, sending the results to the results channel.
+func (self *scanStateIterator) scanPrefix(transport SawtoothClientTransport, prefix string, options *ScanStateOptions) error {
+	iterator := transport.GetStateIteratorAtHead(prefix, options.Head, options.Fetch, false)
+	defer iterator.Close()
+
+	if options.Prefetch > 0 {
+		iterator.SetPrefetch(options.Prefetch)
+	}
+
+	for iterator.Next() {
+		state, err := iterator.Current()
+		if err != nil {
+			return err
+		}
+
+		select {
+		case self.results <- state:
+		case <-self.stop:
+			return nil
+		}
+	}
+
+	return iterator.Error()
+}
+
+// setError records the first error encountered by a worker.
+func (self *scanStateIterator) setError(err error) {
+	self.errMutex.Lock()
+	defer self.errMutex.Unlock()
+
+	if self.err == nil {
+		self.err = err
+	}
+}
+
+// Next returns true if a next value is available.
+func (self *scanStateIterator) Next() bool {
+	select {
+	case <-self.stop:
+		return false
+	default:
+	}
+
+	state, ok := <-self.results
+	if !ok || self.Error() != nil {
+		return false
+	}
+
+	self.current = state
+	return true
+}
+
+// Current returns the "current" state from the iterator.
+func (self *scanStateIterator) Current() (*types.State, error) {
+	if self.current == nil {
+		return nil, fmt.Errorf("No current value in iterator...")
+	}
+
+	return self.current, nil
+}
+
+// Error returns the error (if any) contained in the iterator.
+func (self *scanStateIterator) Error() error {
+	self.errMutex.Lock()
+	defer self.errMutex.Unlock()
+
+	return self.err
+}
+
+// SetPrefetch has no effect; use ScanStateOptions.Prefetch instead.
+func (self *scanStateIterator) SetPrefetch(depth int) {}
+
+// Cursor is not supported for parallel scans, as the sub-prefixes are read in no particular order.
+func (self *scanStateIterator) Cursor() (*types.Cursor, error) {
+	return nil, fmt.Errorf("Cursors are not supported for parallel state scans")
+}
+
+// Close stops the scan.
+func (self *scanStateIterator) Close() {
+	self.stopOnce.Do(func() {
+		close(self.stop)
+	})
+}
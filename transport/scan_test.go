@@ -0,0 +1,79 @@
+package transport_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/conformance"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/errors"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+func TestScanState(t *testing.T) {
+	fixture := conformance.NewFixture()
+	clientTransport := conformance.NewRestTransport(t, conformance.NewValidator(fixture))
+	head := conformance.FIXTURE_BLOCKS - 1
+
+	// checkScan checks that a scan returns all state under prefix at block, in any order
+	checkScan := func(description string, iterator types.StateIterator, block int, prefix string) {
+		t.Helper()
+
+		states, err := types.Collect(types.NewIterator(iterator))
+		if err != nil {
+			t.Fatalf("%s: %s", description, err)
+		}
+		sort.Slice(states, func(i, j int) bool {
+			return states[i].Address < states[j].Address
+		})
+
+		addresses := fixture.AddressesAt(block, prefix)
+		if len(states) != len(addresses) {
+			t.Fatalf("%s: got %d states, expected %d", description, len(states), len(addresses))
+		}
+		for i, address := range addresses {
+			if states[i].Address != address || string(states[i].Data) != string(fixture.States[block][address]) {
+				t.Fatalf("%s: got %s=%q, expected %s=%q", description, states[i].Address, states[i].Data, address, fixture.States[block][address])
+			}
+		}
+	}
+
+	// An odd split depth is rounded up, as the validator rejects odd-length prefixes
+	for _, prefix := range []string{conformance.FIXTURE_NAMESPACE, ""} {
+		for _, options := range []*transport.ScanStateOptions{
+			nil,
+			{SplitDepth: 1, Concurrency: 1},
+			{SplitDepth: 1, Fetch: 1, Prefetch: 2},
+		} {
+			description := fmt.Sprintf("ScanState(%q, %+v)", prefix, options)
+			checkScan(description, transport.ScanState(clientTransport, prefix, options), head, prefix)
+		}
+	}
+
+	// A scan can be pinned to an earlier block
+	past := 4
+	options := &transport.ScanStateOptions{Head: fixture.Blocks[past].HeaderSignature}
+	checkScan("ScanState(past)", transport.ScanState(clientTransport, conformance.FIXTURE_NAMESPACE, options), past, conformance.FIXTURE_NAMESPACE)
+
+	// Closing a scan part way through stops all of its workers
+	iterator := transport.ScanState(clientTransport, conformance.FIXTURE_NAMESPACE, &transport.ScanStateOptions{Fetch: 1, Concurrency: 4})
+	if !iterator.Next() {
+		t.Fatalf("ScanState(close): %v", iterator.Error())
+	}
+	iterator.Close()
+	if iterator.Next() {
+		t.Fatalf("ScanState(close): got a value after Close")
+	}
+	conformance.WaitForGoroutines(t, "scanStateIterator")
+
+	// The first error ends the scan
+	iterator = transport.ScanState(clientTransport, conformance.FIXTURE_NAMESPACE, &transport.ScanStateOptions{Head: conformance.UnknownId("head")})
+	if iterator.Next() {
+		t.Fatalf("ScanState(unknown head): expected no values")
+	}
+	if !errors.HasErrorCode(iterator.Error(), errors.INVALID_HEAD) {
+		t.Fatalf("ScanState(unknown head): expected error code %d, got: %v", errors.INVALID_HEAD, iterator.Error())
+	}
+	conformance.WaitForGoroutines(t, "scanStateIterator")
+}
@@ -1,5 +1,9 @@
 package types
 
+import (
+	"encoding/json"
+)
+
 // BatchHeader represents a Sawtooth batch header.
 type BatchHeader struct {
 	SignerPublicKey	string			`json:"signer_public_key"`
@@ -12,6 +16,38 @@ type Batch struct {
 	HeaderSignature	string     		`json:"header_signature"`
 	Trace			bool         	`json:"trace"`
 	Transactions	[]Transaction 	`json:"transactions"`
+
+	// HeaderBytes holds the serialized header that was signed. It is not part of the JSON form,
+	// and is reconstructed from Header when decoding JSON.
+	HeaderBytes		[]byte			`json:"-"`
+}
+
+// batchHeaderJson has the same fields as BatchHeader, without its methods.
+type batchHeaderJson BatchHeader
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (self *BatchHeader) UnmarshalJSON(data []byte) error {
+	err := json.Unmarshal(data, (*batchHeaderJson)(self))
+	if err != nil {
+		return err
+	}
+
+	self.TransactionIds = nilIfEmpty(self.TransactionIds)
+	return nil
+}
+
+// batchJson has the same fields as Batch, without its methods.
+type batchJson Batch
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (self *Batch) UnmarshalJSON(data []byte) error {
+	err := json.Unmarshal(data, (*batchJson)(self))
+	if err != nil {
+		return err
+	}
+
+	self.HeaderBytes, err = marshalHeader(self.Header.ToProto())
+	return err
 }
 
 // BatchStatus represents a Sawtooth batch status.
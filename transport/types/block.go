@@ -1,9 +1,17 @@
 package types
 
-// BlockHeader represents a Sawtooth block header.
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BlockHeader represents a Sawtooth block header. BlockNum was a decimal string in earlier
+// versions of this package; it is still encoded as one in JSON.
 type BlockHeader struct {
-	BatchIds			[]string	`json:"block_ids"`
-	BlockNum			string		`json:"block_num"`
+	BatchIds			[]string	`json:"batch_ids"`
+	BlockNum			uint64		`json:"block_num,string"`
 	Consensus			[]byte		`json:"consensus"`
 	PreviousBlockId		string		`json:"previous_block_id"`
 	SignerPublicKey		string		`json:"signer_public_key"`
@@ -15,4 +23,133 @@ type Block struct {
 	Batches         []Batch       `json:"batches"`
 	Header          BlockHeader `json:"header"`
 	HeaderSignature string      `json:"header_signature"`
+
+	// HeaderBytes holds the serialized header that was signed. It is not part of the JSON form,
+	// and is reconstructed from Header when decoding JSON.
+	HeaderBytes		[]byte		`json:"-"`
+}
+
+// blockHeaderJson is used to decode block headers. It also accepts the "block_ids" tag and the
+// numeric block_num written by earlier versions of this package.
+type blockHeaderJson struct {
+	BatchIds			[]string	`json:"batch_ids"`
+	LegacyBatchIds		[]string	`json:"block_ids"`
+	BlockNum			json.Number	`json:"block_num"`
+	Consensus			[]byte		`json:"consensus"`
+	PreviousBlockId		string		`json:"previous_block_id"`
+	SignerPublicKey		string		`json:"signer_public_key"`
+	StateRootHash		string		`json:"state_root_hash"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (self *BlockHeader) UnmarshalJSON(data []byte) error {
+	var header blockHeaderJson
+	err := json.Unmarshal(data, &header)
+	if err != nil {
+		return err
+	}
+
+	var blockNum uint64
+	if header.BlockNum != "" {
+		blockNum, err = strconv.ParseUint(string(header.BlockNum), 10, 64)
+		if err != nil {
+			return fmt.Errorf("Invalid block number (%s): %s", header.BlockNum, err)
+		}
+	}
+
+	batchIds := header.BatchIds
+	if batchIds == nil {
+		batchIds = header.LegacyBatchIds
+	}
+
+	*self = BlockHeader{
+		BatchIds: nilIfEmpty(batchIds),
+		BlockNum: blockNum,
+		Consensus: nilIfEmpty(header.Consensus),
+		PreviousBlockId: header.PreviousBlockId,
+		SignerPublicKey: header.SignerPublicKey,
+		StateRootHash: header.StateRootHash,
+	}
+
+	return nil
+}
+
+// blockJson has the same fields as Block, without its methods.
+type blockJson Block
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (self *Block) UnmarshalJSON(data []byte) error {
+	err := json.Unmarshal(data, (*blockJson)(self))
+	if err != nil {
+		return err
+	}
+
+	self.HeaderBytes, err = marshalHeader(self.Header.ToProto())
+	return err
+}
+
+// BlockPagingStart returns the paging token that starts a block listing at the given block number.
+func BlockPagingStart(blockNum uint64) string {
+	return fmt.Sprintf("0x%016x", blockNum)
+}
+
+// HeadBlock returns the first block of a newest-first block iterator (the chain head) and closes
+// the iterator.
+func HeadBlock(iterator BlockIterator) (*Block, error) {
+	defer iterator.Close()
+
+	if !iterator.Next() {
+		err := iterator.Error()
+		if err == nil {
+			err = fmt.Errorf("No blocks available")
+		}
+		return nil, err
+	}
+
+	return iterator.Current()
+}
+
+// ClampBlockCursor prepares a block cursor that is not pinned to a head for a listing, as the
+// validator rejects listings starting past the chain head. headIterator returns a newest-first
+// block iterator, and is only called if the head is needed. A newest-first listing starting past
+// the head is clamped to start at the head. ClampBlockCursor returns nil if no block is to be
+// listed yet; the cursor can still be resumed once blocks are added.
+func ClampBlockCursor(cursor *Cursor, headIterator func() BlockIterator) (*Cursor, error) {
+	if cursor == nil || cursor.Kind != CURSOR_BLOCKS || cursor.Head != "" || cursor.Start == "" {
+		return cursor, nil
+	}
+
+	// Paging tokens not produced by BlockPagingStart are left for the transport to check
+	start, err := strconv.ParseUint(strings.TrimPrefix(cursor.Start, "0x"), 16, 64)
+	if err != nil {
+		return cursor, nil
+	}
+
+	head, err := HeadBlock(headIterator())
+	if err != nil {
+		return nil, err
+	}
+	headNum := head.Header.BlockNum
+	if start <= headNum {
+		return cursor, nil
+	}
+
+	if cursor.Reverse || (cursor.StopBlock != nil && *cursor.StopBlock > headNum) {
+		return nil, nil
+	}
+
+	clamped := *cursor
+	clamped.Start, clamped.Skip = BlockPagingStart(headNum), 0
+
+	return &clamped, nil
+}
+
+// nilIfEmpty returns nil for an empty slice. The protobuf decoder leaves empty fields nil while the
+// JSON decoder allocates them, so headers are normalized to compare equal across transports.
+func nilIfEmpty[T any](slice []T) []T {
+	if len(slice) == 0 {
+		return nil
+	}
+
+	return slice
 }
@@ -0,0 +1,173 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// CURSOR_VERSION is the version of the cursor encoding.
+const CURSOR_VERSION = 1
+
+// CursorKind identifies the type of object a cursor iterates over.
+type CursorKind string
+
+const (
+	CURSOR_BLOCKS			CursorKind = "blocks"
+	CURSOR_BATCHES			CursorKind = "batches"
+	CURSOR_TRANSACTIONS		CursorKind = "transactions"
+	CURSOR_STATE			CursorKind = "state"
+)
+
+// Cursor records the position of an iterator so it can be reconstructed later, on either
+// transport, and continue exactly where it stopped. A cursor pins the head the iterator was
+// reading from, so a resumed iterator sees the same data, except for cursors of iterations over
+// the blocks following a given block, which also return the blocks added since.
+//
+// Cursors should be treated as opaque: use Encode() and DecodeCursor() to persist them.
+type Cursor struct {
+	Version		int			`json:"v"`
+	Kind		CursorKind	`json:"kind"`
+	Head		string		`json:"head,omitempty"`
+	StateRoot	string		`json:"state_root,omitempty"`
+	Address		string		`json:"address,omitempty"`
+	Limit		int			`json:"limit,omitempty"`
+	Reverse		bool		`json:"reverse,omitempty"`
+
+	// Start is the paging token of the page containing the next value ("" for the first page).
+	Start		string		`json:"start,omitempty"`
+	// Skip is the number of values of that page that have already been consumed.
+	Skip		int			`json:"skip,omitempty"`
+
+	// StopBlock, if set, ends a block iteration after the block with this number.
+	StopBlock	*uint64		`json:"stop_block,omitempty"`
+	// Since marks an iteration over the blocks following a given block, which is not pinned to a head.
+	Since		bool		`json:"since,omitempty"`
+}
+
+// cursorJson has the same fields as Cursor, without its methods.
+type cursorJson Cursor
+
+// Encode serializes the cursor into an opaque, URL-safe string.
+func (self *Cursor) Encode() (string, error) {
+	data, err := json.Marshal((*cursorJson)(self))
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor parses a cursor produced by Encode().
+func DecodeCursor(encoded string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding cursor: %s", err)
+	}
+
+	var cursor Cursor
+	err = json.Unmarshal(data, (*cursorJson)(&cursor))
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding cursor: %s", err)
+	}
+
+	if cursor.Version != CURSOR_VERSION {
+		return nil, fmt.Errorf("Unsupported cursor version: %d", cursor.Version)
+	}
+
+	return &cursor, nil
+}
+
+// MarshalText implements encoding.TextMarshaler, so cursors can be embedded in JSON documents.
+func (self Cursor) MarshalText() ([]byte, error) {
+	encoded, err := self.Encode()
+	return []byte(encoded), err
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (self *Cursor) UnmarshalText(text []byte) error {
+	cursor, err := DecodeCursor(string(text))
+	if err != nil {
+		return err
+	}
+
+	*self = *cursor
+	return nil
+}
+
+// Check returns an error if the cursor cannot be used to resume an iterator of the given kind.
+func (self *Cursor) Check(kind CursorKind) error {
+	if self == nil {
+		return fmt.Errorf("Cursor is nil")
+	}
+
+	if self.Version != CURSOR_VERSION {
+		return fmt.Errorf("Unsupported cursor version: %d", self.Version)
+	}
+
+	if self.Kind != kind {
+		return fmt.Errorf("Cursor is for %s, not %s", self.Kind, kind)
+	}
+
+	if self.Skip < 0 {
+		return fmt.Errorf("Cursor has invalid skip: %d", self.Skip)
+	}
+
+	if self.StopBlock != nil && kind != CURSOR_BLOCKS {
+		return fmt.Errorf("Cursor has a stop block, but is not for blocks")
+	}
+
+	if self.Since && (kind != CURSOR_BLOCKS || !self.Reverse) {
+		return fmt.Errorf("Cursor follows the chain, but is not for blocks in order")
+	}
+
+	return nil
+}
+
+// NewCursor returns a cursor positioned at the start of an iteration.
+func NewCursor(kind CursorKind, fetch int, reverse bool) *Cursor {
+	return &Cursor{Version: CURSOR_VERSION, Kind: kind, Limit: fetch, Reverse: reverse}
+}
+
+// NewBlockRangeCursor returns a cursor positioned at the start of an iteration over the blocks
+// numbered first to last (inclusive). As with GetBlockIterator, blocks are returned newest first
+// unless reverse is true.
+func NewBlockRangeCursor(first uint64, last uint64, fetch int, reverse bool) (*Cursor, error) {
+	if first > last {
+		return nil, fmt.Errorf("Invalid block range: %d > %d", first, last)
+	}
+
+	cursor := NewCursor(CURSOR_BLOCKS, fetch, reverse)
+	if reverse {
+		cursor.Start = BlockPagingStart(first)
+		cursor.StopBlock = &last
+	} else {
+		cursor.Start = BlockPagingStart(last)
+		cursor.StopBlock = &first
+	}
+
+	return cursor, nil
+}
+
+// NewBlockSinceCursor returns a cursor positioned at the start of an iteration over the blocks
+// following the block numbered blockNum, oldest first, up to the chain head.
+func NewBlockSinceCursor(blockNum uint64, fetch int) *Cursor {
+	cursor := NewCursor(CURSOR_BLOCKS, fetch, true)
+	cursor.Start = BlockPagingStart(blockNum + 1)
+	cursor.Since = true
+
+	return cursor
+}
+
+// PastStopBlock returns true if block lies beyond the cursor's StopBlock, in the direction of
+// the iteration.
+func (self *Cursor) PastStopBlock(block *Block) (bool, error) {
+	if self.StopBlock == nil {
+		return false, nil
+	}
+
+	if self.Reverse {
+		return block.Header.BlockNum > *self.StopBlock, nil
+	}
+	return block.Header.BlockNum < *self.StopBlock, nil
+}
@@ -0,0 +1,186 @@
+package types
+
+import (
+	"fmt"
+	"iter"
+)
+
+// Iterator is a generic iterator over values of type T.
+type Iterator[T any] interface {
+	// Next advances the iterator, returning true if a next value is available.
+	Next() bool
+	// Current returns the current value.
+	Current() (T, error)
+	// Error returns the error (if any) that stopped the iteration.
+	Error() error
+	// Close releases the resources held by the iterator.
+	Close()
+}
+
+// typedIterator is satisfied by BlockIterator, BatchIterator, TransactionIterator and StateIterator.
+type typedIterator[T any] interface {
+	CommonIterator
+	Current() (T, error)
+}
+
+// NewIterator returns one of the typed transport iterators (e.g. a StateIterator) as an Iterator.
+// The typed iterators implement Iterator, so they can also be passed directly.
+func NewIterator[T any](iterator typedIterator[T]) Iterator[T] {
+	return iterator
+}
+
+// All returns an iter.Seq2 that yields each value of the iterator with a nil error, for use with
+// "for range". If the iterator fails, the error is yielded with a zero value as the final
+// element. The iterator is closed when the loop ends, including when it ends early.
+func All[T any](iterator Iterator[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer iterator.Close()
+
+		for iterator.Next() {
+			value, err := iterator.Current()
+			if !yield(value, err) || err != nil {
+				return
+			}
+		}
+
+		err := iterator.Error()
+		if err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// Collect reads all remaining values of the iterator into a slice and closes it.
+func Collect[T any](iterator Iterator[T]) ([]T, error) {
+	var result []T
+
+	for value, err := range All(iterator) {
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+
+	return result, nil
+}
+
+// ForEach calls fn for each value of the iterator, stopping early if fn returns false or an
+// error. The iterator is closed when ForEach returns.
+func ForEach[T any](iterator Iterator[T], fn func(T) (bool, error)) error {
+	for value, err := range All(iterator) {
+		if err != nil {
+			return err
+		}
+
+		more, err := fn(value)
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// Filter returns an Iterator that yields only the values for which keep returns true.
+func Filter[T any](iterator Iterator[T], keep func(T) bool) Iterator[T] {
+	return &funcIterator[T, T]{
+		source: iterator,
+		next: func(value T) (T, bool, error) {
+			return value, keep(value), nil
+		},
+	}
+}
+
+// Map returns an Iterator that yields the result of calling fn on each value. An error returned
+// by fn stops the iteration and is reported by Error().
+func Map[T any, U any](iterator Iterator[T], fn func(T) (U, error)) Iterator[U] {
+	return &funcIterator[T, U]{
+		source: iterator,
+		next: func(value T) (U, bool, error) {
+			result, err := fn(value)
+			return result, true, err
+		},
+	}
+}
+
+// Take returns an Iterator that yields at most n values.
+func Take[T any](iterator Iterator[T], n int) Iterator[T] {
+	count := 0
+	return &funcIterator[T, T]{
+		source: iterator,
+		next: func(value T) (T, bool, error) {
+			count++
+			return value, true, nil
+		},
+		done: func() bool {
+			return count >= n
+		},
+	}
+}
+
+// funcIterator implements Filter, Map and Take on top of a source Iterator.
+type funcIterator[T any, U any] struct {
+	source		Iterator[T]
+	// next converts a source value, returning false if the value should be skipped.
+	next		func(T) (U, bool, error)
+	// done optionally reports that no further values should be returned.
+	done		func() bool
+
+	current		U
+	valid		bool
+	err			error
+}
+
+func (self *funcIterator[T, U]) Next() bool {
+	self.valid = false
+	if self.err != nil || (self.done != nil && self.done()) {
+		return false
+	}
+
+	for self.source.Next() {
+		value, err := self.source.Current()
+		if err != nil {
+			self.err = err
+			return false
+		}
+
+		result, keep, err := self.next(value)
+		if err != nil {
+			self.err = err
+			return false
+		}
+
+		if keep {
+			self.current = result
+			self.valid = true
+			return true
+		}
+	}
+
+	return false
+}
+
+func (self *funcIterator[T, U]) Current() (U, error) {
+	if !self.valid {
+		var zero U
+		return zero, fmt.Errorf("No current value in iterator...")
+	}
+
+	return self.current, nil
+}
+
+func (self *funcIterator[T, U]) Error() error {
+	if self.err != nil {
+		return self.err
+	}
+
+	return self.source.Error()
+}
+
+func (self *funcIterator[T, U]) Close() {
+	self.source.Close()
+}
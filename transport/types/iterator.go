@@ -4,6 +4,15 @@ package types
 type CommonIterator interface {
 	Next() bool
 	Error() error
+
+	// SetPrefetch sets the number of pages fetched in the background ahead of the caller
+	// (0 disables prefetching). It must be called before the first call to Next().
+	SetPrefetch(depth int)
+	// Close releases the resources held by the iterator, including any background prefetching.
+	Close()
+	// Cursor returns a cursor recording the iterator's position, from which the iteration can
+	// later be resumed exactly where it stopped.
+	Cursor() (*Cursor, error)
 }
 
 // BlockIterator is an interface that represents an iterator over blocks.
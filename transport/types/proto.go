@@ -1,10 +1,13 @@
 package types
 
 import (
+	"fmt"
+
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/block_pb2"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	transaction_receipt_pb2 "github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_receipt_pb2"
 )
 
 // TransactionFromProto converts a Transaction protobuf into our own Transaction object.
@@ -20,23 +23,24 @@ func TransactionFromProto(transactionProto *transaction_pb2.Transaction) (*Trans
 	transaction := Transaction{
 		Header: TransactionHeader{
 			BatcherPublicKey: headerProto.BatcherPublicKey,
-			Dependencies:     headerProto.Dependencies,
+			Dependencies:     nilIfEmpty(headerProto.Dependencies),
 			FamilyName:       headerProto.FamilyName,
 			FamilyVersion:    headerProto.FamilyVersion,
-			Inputs:           headerProto.Inputs,
+			Inputs:           nilIfEmpty(headerProto.Inputs),
 			Nonce:            headerProto.Nonce,
-			Outputs:          headerProto.Outputs,
-			PayloadSHA256:    headerProto.PayloadSha512,
+			Outputs:          nilIfEmpty(headerProto.Outputs),
+			PayloadSha512:    headerProto.PayloadSha512,
 			SignerPublicKey:  headerProto.SignerPublicKey,
+			PayloadSHA256:    headerProto.PayloadSha512,
 		},
 		HeaderSignature: transactionProto.HeaderSignature,
-		Payload: transactionProto.Payload,
+		Payload: nilIfEmpty(transactionProto.Payload),
+		HeaderBytes: transactionProto.Header,
 	}
 
 	return &transaction, nil
 }
 
-
 // BatchFromProto converts a Batch protobuf into our own Batch object.
 func BatchFromProto(batchProto *batch_pb2.Batch) (*Batch, error) {
 	// Parse out the batch header
@@ -50,11 +54,12 @@ func BatchFromProto(batchProto *batch_pb2.Batch) (*Batch, error) {
 	batch := Batch{
 		Header: BatchHeader{
 			SignerPublicKey: headerProto.SignerPublicKey,
-			TransactionIds:  headerProto.TransactionIds,
+			TransactionIds:  nilIfEmpty(headerProto.TransactionIds),
 		},
 		HeaderSignature: batchProto.HeaderSignature,
 		Transactions: make([]Transaction, len(batchProto.Transactions)),
 		Trace: batchProto.Trace,
+		HeaderBytes: batchProto.Header,
 	}
 
 	// Parse out the transactions
@@ -81,15 +86,16 @@ func BlockFromProto(blockProto *block_pb2.Block) (*Block, error) {
 	// Construct the block object
 	block := Block{
 		Header: BlockHeader{
-			BatchIds:        headerProto.BatchIds,
-			BlockNum:        string(headerProto.BlockNum),
-			Consensus:       headerProto.Consensus,
+			BatchIds:        nilIfEmpty(headerProto.BatchIds),
+			BlockNum:        headerProto.BlockNum,
+			Consensus:       nilIfEmpty(headerProto.Consensus),
 			PreviousBlockId: headerProto.PreviousBlockId,
 			SignerPublicKey: headerProto.SignerPublicKey,
 			StateRootHash:   headerProto.StateRootHash,
 		},
 		HeaderSignature: blockProto.HeaderSignature,
 		Batches: make([]Batch, len(blockProto.Batches)),
+		HeaderBytes: blockProto.Header,
 	}
 
 	// Parse out the batches
@@ -103,3 +109,148 @@ func BlockFromProto(blockProto *block_pb2.Block) (*Block, error) {
 
 	return &block, nil
 }
+
+// TransactionReceiptFromProto converts a TransactionReceipt protobuf into our own TransactionReceipt object.
+func TransactionReceiptFromProto(receiptProto *transaction_receipt_pb2.TransactionReceipt) *TransactionReceipt {
+	receipt := TransactionReceipt{
+		TransactionId: receiptProto.TransactionId,
+		Data: nilIfEmpty(receiptProto.Data),
+	}
+
+	for _, change := range receiptProto.StateChanges {
+		receipt.StateChanges = append(receipt.StateChanges, StateChange{
+			Address: change.Address,
+			Value: nilIfEmpty(change.Value),
+			Type: StateChangeType(change.Type.String()),
+		})
+	}
+
+	for _, eventProto := range receiptProto.Events {
+		event := Event{EventType: eventProto.EventType, Data: nilIfEmpty(eventProto.Data)}
+		for _, attribute := range eventProto.Attributes {
+			event.Attributes = append(event.Attributes, EventAttribute{Key: attribute.Key, Value: attribute.Value})
+		}
+		receipt.Events = append(receipt.Events, event)
+	}
+
+	return &receipt
+}
+
+// ToProto converts the transaction header into a TransactionHeader protobuf.
+func (self *TransactionHeader) ToProto() *transaction_pb2.TransactionHeader {
+	return &transaction_pb2.TransactionHeader{
+		BatcherPublicKey: self.BatcherPublicKey,
+		Dependencies: self.Dependencies,
+		FamilyName: self.FamilyName,
+		FamilyVersion: self.FamilyVersion,
+		Inputs: self.Inputs,
+		Nonce: self.Nonce,
+		Outputs: self.Outputs,
+		PayloadSha512: self.PayloadSha512,
+		SignerPublicKey: self.SignerPublicKey,
+	}
+}
+
+// ToProto converts the transaction into a Transaction protobuf. The original header bytes are
+// used if known, so that the result is byte-identical to the transaction that was signed.
+func (self *Transaction) ToProto() (*transaction_pb2.Transaction, error) {
+	headerBytes, err := headerBytesOrMarshal(self.HeaderBytes, self.Header.ToProto())
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction_pb2.Transaction{
+		Header: headerBytes,
+		HeaderSignature: self.HeaderSignature,
+		Payload: self.Payload,
+	}, nil
+}
+
+// ToProto converts the batch header into a BatchHeader protobuf.
+func (self *BatchHeader) ToProto() *batch_pb2.BatchHeader {
+	return &batch_pb2.BatchHeader{
+		SignerPublicKey: self.SignerPublicKey,
+		TransactionIds: self.TransactionIds,
+	}
+}
+
+// ToProto converts the batch (and its transactions) into a Batch protobuf. The original header
+// bytes are used if known, so that the result is byte-identical to the batch that was signed.
+func (self *Batch) ToProto() (*batch_pb2.Batch, error) {
+	headerBytes, err := headerBytesOrMarshal(self.HeaderBytes, self.Header.ToProto())
+	if err != nil {
+		return nil, err
+	}
+
+	batch := batch_pb2.Batch{
+		Header: headerBytes,
+		HeaderSignature: self.HeaderSignature,
+		Transactions: make([]*transaction_pb2.Transaction, len(self.Transactions)),
+		Trace: self.Trace,
+	}
+
+	for i := range self.Transactions {
+		batch.Transactions[i], err = self.Transactions[i].ToProto()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &batch, nil
+}
+
+// ToProto converts the block header into a BlockHeader protobuf.
+func (self *BlockHeader) ToProto() *block_pb2.BlockHeader {
+	return &block_pb2.BlockHeader{
+		BatchIds: self.BatchIds,
+		BlockNum: self.BlockNum,
+		Consensus: self.Consensus,
+		PreviousBlockId: self.PreviousBlockId,
+		SignerPublicKey: self.SignerPublicKey,
+		StateRootHash: self.StateRootHash,
+	}
+}
+
+// ToProto converts the block (and its batches) into a Block protobuf. The original header bytes
+// are used if known, so that the result is byte-identical to the block that was signed.
+func (self *Block) ToProto() (*block_pb2.Block, error) {
+	headerBytes, err := headerBytesOrMarshal(self.HeaderBytes, self.Header.ToProto())
+	if err != nil {
+		return nil, err
+	}
+
+	block := block_pb2.Block{
+		Header: headerBytes,
+		HeaderSignature: self.HeaderSignature,
+		Batches: make([]*batch_pb2.Batch, len(self.Batches)),
+	}
+
+	for i := range self.Batches {
+		block.Batches[i], err = self.Batches[i].ToProto()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &block, nil
+}
+
+// marshalHeader serializes a header protobuf. Sawtooth serializes headers with fields in order
+// and without unknown fields, which proto.Marshal reproduces exactly.
+func marshalHeader(header proto.Message) ([]byte, error) {
+	headerBytes, err := proto.Marshal(header)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to serialize header: %s", err)
+	}
+
+	return headerBytes, nil
+}
+
+// headerBytesOrMarshal returns headerBytes if set, otherwise the serialized header.
+func headerBytesOrMarshal(headerBytes []byte, header proto.Message) ([]byte, error) {
+	if headerBytes != nil {
+		return headerBytes, nil
+	}
+
+	return marshalHeader(header)
+}
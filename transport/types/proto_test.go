@@ -0,0 +1,65 @@
+package types_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/conformance"
+)
+
+func TestProtoRoundTrip(t *testing.T) {
+	transports := map[string]func(testing.TB, *conformance.Validator) transport.SawtoothClientTransport{
+		"rest": conformance.NewRestTransport,
+		"zmq": conformance.NewZmqTransport,
+	}
+
+	// equal reports whether two protobufs serialize identically
+	equal := func(got proto.Message, expected proto.Message) bool {
+		gotBytes, err := proto.Marshal(got)
+		if err != nil {
+			return false
+		}
+		expectedBytes, err := proto.Marshal(expected)
+		return err == nil && bytes.Equal(gotBytes, expectedBytes)
+	}
+
+	// Objects returned by a transport must convert back into the exact protobufs that were signed
+	for name, newTransport := range transports {
+		t.Run(name, func(t *testing.T) {
+			fixture := conformance.NewFixture()
+			clientTransport := newTransport(t, conformance.NewValidator(fixture))
+
+			for i, expected := range fixture.Blocks {
+				block, err := clientTransport.GetBlock(expected.HeaderSignature)
+				if err != nil {
+					t.Fatalf("GetBlock(%d): %s", i, err)
+				}
+
+				got, err := block.ToProto()
+				if err != nil {
+					t.Fatalf("Block(%d).ToProto(): %s", i, err)
+				}
+				if !equal(got, expected) {
+					t.Fatalf("Block(%d).ToProto(): serialized block differs", i)
+				}
+			}
+
+			for i, expected := range fixture.TransactionsAt(conformance.FIXTURE_BLOCKS - 1) {
+				transaction, err := clientTransport.GetTransaction(expected.HeaderSignature)
+				if err != nil {
+					t.Fatalf("GetTransaction(%d): %s", i, err)
+				}
+
+				got, err := transaction.ToProto()
+				if err != nil {
+					t.Fatalf("Transaction(%d).ToProto(): %s", i, err)
+				}
+				if !equal(got, expected) {
+					t.Fatalf("Transaction(%d).ToProto(): serialized transaction differs", i)
+				}
+			}
+		})
+	}
+}
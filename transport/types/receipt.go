@@ -0,0 +1,67 @@
+package types
+
+import (
+	"encoding/json"
+)
+
+// StateChangeType represents the kind of change a transaction made to a state address.
+type StateChangeType string
+
+const (
+	STATE_CHANGE_SET    StateChangeType = "SET"
+	STATE_CHANGE_DELETE StateChangeType = "DELETE"
+)
+
+// StateChange represents a change to a single state address made by a transaction.
+type StateChange struct {
+	Address		string			`json:"address"`
+	Value		[]byte			`json:"value"`
+	Type		StateChangeType	`json:"type"`
+}
+
+// EventAttribute represents an attribute of an event.
+type EventAttribute struct {
+	Key			string		`json:"key"`
+	Value		string		`json:"value"`
+}
+
+// Event represents an event emitted by a transaction processor.
+type Event struct {
+	EventType	string				`json:"event_type"`
+	Attributes	[]EventAttribute	`json:"attributes"`
+	Data		[]byte				`json:"data"`
+}
+
+// TransactionReceipt represents the result of applying a committed transaction: the state
+// changes it made, the events it emitted and any data added by the transaction processor.
+type TransactionReceipt struct {
+	TransactionId	string			`json:"transaction_id"`
+	StateChanges	[]StateChange	`json:"state_changes"`
+	Events			[]Event			`json:"events"`
+	Data			[][]byte		`json:"data"`
+}
+
+// transactionReceiptJson has the same fields as TransactionReceipt, without its methods.
+type transactionReceiptJson TransactionReceipt
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (self *TransactionReceipt) UnmarshalJSON(data []byte) error {
+	err := json.Unmarshal(data, (*transactionReceiptJson)(self))
+	if err != nil {
+		return err
+	}
+
+	// Empty lists are represented as nil, as when decoding protobufs
+	self.StateChanges = nilIfEmpty(self.StateChanges)
+	self.Events = nilIfEmpty(self.Events)
+	self.Data = nilIfEmpty(self.Data)
+	for i := range self.StateChanges {
+		self.StateChanges[i].Value = nilIfEmpty(self.StateChanges[i].Value)
+	}
+	for i := range self.Events {
+		self.Events[i].Attributes = nilIfEmpty(self.Events[i].Attributes)
+		self.Events[i].Data = nilIfEmpty(self.Events[i].Data)
+	}
+
+	return nil
+}
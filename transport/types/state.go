@@ -1,5 +1,8 @@
 package types
 
+// ADDRESS_LENGTH is the length of a full Sawtooth state address in hex characters.
+const ADDRESS_LENGTH = 70
+
 // State represents a  Sawtooth state item.
 type State struct {
 	Data    []byte
@@ -1,5 +1,9 @@
 package types
 
+import (
+	"encoding/json"
+)
+
 // TransactionHeader represents a Sawtooth transaction header.
 type TransactionHeader struct {
 	BatcherPublicKey	string		`json:"batcher_public_key"`
@@ -9,8 +13,12 @@ type TransactionHeader struct {
 	Inputs				[]string	`json:"inputs"`
 	Nonce				string		`json:"nonce"`
 	Outputs				[]string	`json:"outputs"`
-	PayloadSHA256		string		`json:"payload_sha256"`
+	PayloadSha512		string		`json:"payload_sha512"`
 	SignerPublicKey		string		`json:"signer_public_key"`
+
+	// Deprecated: PayloadSHA256 holds the same value as PayloadSha512 (the field was misnamed)
+	// and will be removed in a future release.
+	PayloadSHA256		string		`json:"-"`
 }
 
 // Transaction represents a Sawtooth transaction.
@@ -18,4 +26,68 @@ type Transaction struct {
 	Header				TransactionHeader	`json:"header"`
 	HeaderSignature		string				`json:"header_signature"`
 	Payload				[]byte				`json:"payload"`
+
+	// HeaderBytes holds the serialized header that was signed. It is not part of the JSON form,
+	// and is reconstructed from Header when decoding JSON.
+	HeaderBytes			[]byte				`json:"-"`
+}
+
+// transactionHeaderJson is used to decode transaction headers. It also accepts the
+// "payload_sha256" tag written by earlier versions of this package.
+type transactionHeaderJson struct {
+	BatcherPublicKey	string		`json:"batcher_public_key"`
+	Dependencies		[]string	`json:"dependencies"`
+	FamilyName			string		`json:"family_name"`
+	FamilyVersion		string		`json:"family_version"`
+	Inputs				[]string	`json:"inputs"`
+	Nonce				string		`json:"nonce"`
+	Outputs				[]string	`json:"outputs"`
+	PayloadSha512		string		`json:"payload_sha512"`
+	LegacyPayloadSha512	string		`json:"payload_sha256"`
+	SignerPublicKey		string		`json:"signer_public_key"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (self *TransactionHeader) UnmarshalJSON(data []byte) error {
+	var header transactionHeaderJson
+	err := json.Unmarshal(data, &header)
+	if err != nil {
+		return err
+	}
+
+	payloadSha512 := header.PayloadSha512
+	if payloadSha512 == "" {
+		payloadSha512 = header.LegacyPayloadSha512
+	}
+
+	*self = TransactionHeader{
+		BatcherPublicKey: header.BatcherPublicKey,
+		Dependencies: nilIfEmpty(header.Dependencies),
+		FamilyName: header.FamilyName,
+		FamilyVersion: header.FamilyVersion,
+		Inputs: nilIfEmpty(header.Inputs),
+		Nonce: header.Nonce,
+		Outputs: nilIfEmpty(header.Outputs),
+		PayloadSha512: payloadSha512,
+		SignerPublicKey: header.SignerPublicKey,
+		PayloadSHA256: payloadSha512,
+	}
+
+	return nil
+}
+
+// transactionJson has the same fields as Transaction, without its methods.
+type transactionJson Transaction
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (self *Transaction) UnmarshalJSON(data []byte) error {
+	err := json.Unmarshal(data, (*transactionJson)(self))
+	if err != nil {
+		return err
+	}
+
+	self.Payload = nilIfEmpty(self.Payload)
+
+	self.HeaderBytes, err = marshalHeader(self.Header.ToProto())
+	return err
 }
@@ -0,0 +1,174 @@
+package types
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/block_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+)
+
+// VerificationError is returned by Verify when an object fails verification.
+type VerificationError struct {
+	// Kind is the kind of object which failed verification ("transaction", "batch" or "block").
+	Kind	string
+	// Id is the header signature of the object.
+	Id		string
+	// Reason describes why verification failed.
+	Reason	string
+}
+
+// Error implements the error interface for VerificationError.
+func (self *VerificationError) Error() string {
+	return fmt.Sprintf("Verification of %s %s failed: %s", self.Kind, self.Id, self.Reason)
+}
+
+// Verify checks the header signature of the transaction against its SignerPublicKey, and that
+// PayloadSha512 is the hash of the payload.
+func (self *Transaction) Verify() error {
+	fail := func(format string, args ...interface{}) error {
+		return &VerificationError{Kind: "transaction", Id: self.HeaderSignature, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	var headerProto transaction_pb2.TransactionHeader
+	headerBytes, err := verifyHeaderBytes(self.HeaderBytes, self.Header.ToProto(), &headerProto)
+	if err != nil {
+		return fail("%s", err)
+	}
+
+	err = VerifySignature(headerBytes, self.HeaderSignature, self.Header.SignerPublicKey)
+	if err != nil {
+		return fail("%s", err)
+	}
+
+	payloadHash := sha512.Sum512(self.Payload)
+	if !strings.EqualFold(hex.EncodeToString(payloadHash[:]), self.Header.PayloadSha512) {
+		return fail("Payload does not match payload_sha512")
+	}
+
+	return nil
+}
+
+// Verify checks the header signature of the batch against its SignerPublicKey, that
+// TransactionIds lists the batch's transactions in order, and verifies each transaction.
+func (self *Batch) Verify() error {
+	fail := func(format string, args ...interface{}) error {
+		return &VerificationError{Kind: "batch", Id: self.HeaderSignature, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	var headerProto batch_pb2.BatchHeader
+	headerBytes, err := verifyHeaderBytes(self.HeaderBytes, self.Header.ToProto(), &headerProto)
+	if err != nil {
+		return fail("%s", err)
+	}
+
+	err = VerifySignature(headerBytes, self.HeaderSignature, self.Header.SignerPublicKey)
+	if err != nil {
+		return fail("%s", err)
+	}
+
+	if len(self.Header.TransactionIds) != len(self.Transactions) {
+		return fail("Header lists %d transactions, batch has %d", len(self.Header.TransactionIds), len(self.Transactions))
+	}
+	for i := range self.Transactions {
+		if self.Header.TransactionIds[i] != self.Transactions[i].HeaderSignature {
+			return fail("Transaction %d is %s, header lists %s", i, self.Transactions[i].HeaderSignature, self.Header.TransactionIds[i])
+		}
+
+		err = self.Transactions[i].Verify()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Verify checks the header signature of the block against its SignerPublicKey, that BatchIds
+// lists the block's batches in order, and verifies each batch.
+func (self *Block) Verify() error {
+	fail := func(format string, args ...interface{}) error {
+		return &VerificationError{Kind: "block", Id: self.HeaderSignature, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	var headerProto block_pb2.BlockHeader
+	headerBytes, err := verifyHeaderBytes(self.HeaderBytes, self.Header.ToProto(), &headerProto)
+	if err != nil {
+		return fail("%s", err)
+	}
+
+	err = VerifySignature(headerBytes, self.HeaderSignature, self.Header.SignerPublicKey)
+	if err != nil {
+		return fail("%s", err)
+	}
+
+	if len(self.Header.BatchIds) != len(self.Batches) {
+		return fail("Header lists %d batches, block has %d", len(self.Header.BatchIds), len(self.Batches))
+	}
+	for i := range self.Batches {
+		if self.Header.BatchIds[i] != self.Batches[i].HeaderSignature {
+			return fail("Batch %d is %s, header lists %s", i, self.Batches[i].HeaderSignature, self.Header.BatchIds[i])
+		}
+
+		err = self.Batches[i].Verify()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyHeaderBytes returns the header bytes that were signed. If the raw bytes are known, they
+// must decode to the same header as the (decoded) Header field, since that is what callers see.
+func verifyHeaderBytes(headerBytes []byte, header proto.Message, decoded proto.Message) ([]byte, error) {
+	if headerBytes == nil {
+		return marshalHeader(header)
+	}
+
+	err := proto.Unmarshal(headerBytes, decoded)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode header: %s", err)
+	}
+	if !proto.Equal(header, decoded) {
+		return nil, fmt.Errorf("Header does not match the signed header bytes")
+	}
+
+	return headerBytes, nil
+}
+
+// VerifySignature checks a hex encoded secp256k1 signature of the SHA-256 hash of message, as
+// created by the Sawtooth signing library.
+func VerifySignature(message []byte, signature string, publicKey string) error {
+	signatureBytes, err := hex.DecodeString(signature)
+	if err != nil || len(signatureBytes) != 64 {
+		return fmt.Errorf("Malformed header signature")
+	}
+
+	publicKeyBytes, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return fmt.Errorf("Malformed signer public key: %s", err)
+	}
+	key, err := btcec.ParsePubKey(publicKeyBytes, btcec.S256())
+	if err != nil {
+		return fmt.Errorf("Malformed signer public key: %s", err)
+	}
+
+	sig := btcec.Signature{
+		R: new(big.Int).SetBytes(signatureBytes[:32]),
+		S: new(big.Int).SetBytes(signatureBytes[32:]),
+	}
+	hash := sha256.Sum256(message)
+	if !sig.Verify(hash[:], key) {
+		return fmt.Errorf("Header signature is not valid for signer %s", publicKey)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package types_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/conformance"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+func TestVerify(t *testing.T) {
+	fixture := conformance.NewFixture()
+
+	// Verifying a block also verifies its batches and transactions
+	for i, expected := range fixture.Blocks {
+		block, err := types.BlockFromProto(expected)
+		if err != nil {
+			t.Fatalf("BlockFromProto(%d): %s", i, err)
+		}
+
+		err = block.Verify()
+		if err != nil {
+			t.Fatalf("Block(%d).Verify(): %s", i, err)
+		}
+	}
+
+	// The first batch of the block before the head holds several transactions
+	batch, err := types.BatchFromProto(fixture.Blocks[conformance.FIXTURE_BLOCKS - 2].Batches[0])
+	if err != nil {
+		t.Fatalf("BatchFromProto: %s", err)
+	}
+
+	// Tampering with any part of a batch must be detected
+	tamper := []struct {
+		name	string
+		modify	func(*types.Batch)
+	}{
+		{"payload", func(batch *types.Batch) { batch.Transactions[0].Payload = []byte("tampered") }},
+		{"transaction header", func(batch *types.Batch) { batch.Transactions[0].Header.Nonce = "tampered" }},
+		{"batch header", func(batch *types.Batch) { batch.Header.SignerPublicKey = fixture.SignerPublicKey[:2] }},
+		{"transaction order", func(batch *types.Batch) { slices.Reverse(batch.Transactions) }},
+		{"signature", func(batch *types.Batch) { batch.HeaderSignature = conformance.UnknownId("signature") }},
+	}
+	for _, test := range tamper {
+		tampered := *batch
+		tampered.Transactions = append([]types.Transaction(nil), batch.Transactions...)
+		test.modify(&tampered)
+
+		err = tampered.Verify()
+		if _, ok := err.(*types.VerificationError); !ok {
+			t.Fatalf("Verify() with tampered %s: expected a VerificationError, got: %v", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package transport
+
+import (
+	"fmt"
+
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// GENESIS_PREVIOUS_BLOCK_ID is the PreviousBlockId of the genesis block.
+const GENESIS_PREVIOUS_BLOCK_ID = "0000000000000000"
+
+// VerifyChainOptions holds optional settings for VerifyChain.
+type VerifyChainOptions struct {
+	// Checkpoint is the id of a trusted block to start from. If empty, the chain is verified
+	// from the genesis block.
+	Checkpoint		string
+	// Head is the id of the last block to verify. If empty, the current chain head is used.
+	Head			string
+	// Fetch is the page size used to read blocks (0 uses the transport default).
+	Fetch			int
+	// Progress is called (if set) after each block has been verified.
+	Progress		func(block *types.Block)
+}
+
+// ChainVerificationResult summarizes the part of the chain checked by VerifyChain.
+type ChainVerificationResult struct {
+	// FirstBlockNum and LastBlockNum are the numbers of the first and last blocks verified.
+	FirstBlockNum	uint64
+	LastBlockNum	uint64
+	// Head is the id of the last block verified.
+	Head			string
+
+	Blocks			int
+	Batches			int
+	Transactions	int
+}
+
+// ChainVerificationError describes the first inconsistency found by VerifyChain.
+type ChainVerificationError struct {
+	// BlockNum and BlockId identify the block at which the inconsistency was found.
+	BlockNum		uint64
+	BlockId			string
+	// Reason describes the inconsistency.
+	Reason			string
+	// Err is the underlying error (such as a *types.VerificationError), if any.
+	Err				error
+}
+
+// Error implements the error interface for ChainVerificationError.
+func (self *ChainVerificationError) Error() string {
+	if self.Err != nil {
+		return fmt.Sprintf("Chain verification failed at block %d (%s): %s: %s", self.BlockNum, self.BlockId, self.Reason, self.Err)
+	}
+	return fmt.Sprintf("Chain verification failed at block %d (%s): %s", self.BlockNum, self.BlockId, self.Reason)
+}
+
+// Unwrap returns the underlying error.
+func (self *ChainVerificationError) Unwrap() error {
+	return self.Err
+}
+
+// ChainVerifier verifies blocks one at a time, in chain order (see VerifyChain). It can be used to
+// verify blocks read from sources other than a transport.
+type ChainVerifier struct {
+	previous		*types.Block
+	transactions	map[string]uint64
+	result			ChainVerificationResult
+}
+
+// NewChainVerifier returns a ChainVerifier which expects to start at the genesis block, unless
+// AddCheckpoint is called first.
+func NewChainVerifier() *ChainVerifier {
+	return &ChainVerifier{transactions: make(map[string]uint64)}
+}
+
+// AddCheckpoint verifies the signatures in a trusted block, from which verification continues.
+// It must be called before any other block is added.
+func (self *ChainVerifier) AddCheckpoint(block *types.Block) error {
+	if self.previous != nil {
+		return fmt.Errorf("A checkpoint must be the first block verified")
+	}
+
+	err := self.verifyBlock(block, false)
+	if err != nil {
+		return err
+	}
+
+	self.previous = block
+	return nil
+}
+
+// AddBlock verifies a block, which must follow the previous block added. The first inconsistency
+// is returned as a *ChainVerificationError.
+func (self *ChainVerifier) AddBlock(block *types.Block) error {
+	err := verifyChainLink(self.previous, block)
+	if err != nil {
+		return err
+	}
+
+	err = self.verifyBlock(block, true)
+	if err != nil {
+		return err
+	}
+
+	self.previous = block
+	return nil
+}
+
+// Last returns the last block verified, or nil if there is none.
+func (self *ChainVerifier) Last() *types.Block {
+	return self.previous
+}
+
+// Result returns a summary of the blocks verified so far.
+func (self *ChainVerifier) Result() *ChainVerificationResult {
+	result := self.result
+	return &result
+}
+
+// verifyBlock verifies the signatures in a block and adds it to the result. If checkDuplicates is
+// true, it also checks for transactions which appeared in an earlier block.
+func (self *ChainVerifier) verifyBlock(block *types.Block, checkDuplicates bool) error {
+	err := block.Verify()
+	if err != nil {
+		return &ChainVerificationError{
+			BlockNum: block.Header.BlockNum,
+			BlockId: block.HeaderSignature,
+			Reason: "Invalid block",
+			Err: err,
+		}
+	}
+
+	for _, batch := range block.Batches {
+		if checkDuplicates {
+			for _, transaction := range batch.Transactions {
+				blockNum, ok := self.transactions[transaction.HeaderSignature]
+				if ok {
+					return &ChainVerificationError{
+						BlockNum: block.Header.BlockNum,
+						BlockId: block.HeaderSignature,
+						Reason: fmt.Sprintf("Transaction %s was already committed in block %d", transaction.HeaderSignature, blockNum),
+					}
+				}
+				self.transactions[transaction.HeaderSignature] = block.Header.BlockNum
+			}
+		}
+
+		self.result.Transactions += len(batch.Transactions)
+	}
+
+	if self.result.Blocks == 0 {
+		self.result.FirstBlockNum = block.Header.BlockNum
+	}
+	self.result.Blocks++
+	self.result.Batches += len(block.Batches)
+	self.result.LastBlockNum = block.Header.BlockNum
+	self.result.Head = block.HeaderSignature
+
+	return nil
+}
+
+// VerifyChain walks the chain from the genesis block (or a checkpoint) up to the head, checking
+// that each block links to its predecessor, that block numbers increase by one, that all
+// signatures are valid (see types.Block.Verify) and that no transaction appears twice. All blocks
+// are read from the same fork, even if the chain head moves while the chain is being verified.
+//
+// The first inconsistency is returned as a *ChainVerificationError; any other error comes from
+// the transport. The result describes the blocks verified so far, even if an error is returned.
+// When starting from a checkpoint, duplicate transactions are only detected after it.
+func VerifyChain(transport SawtoothClientTransport, options *VerifyChainOptions) (*ChainVerificationResult, error) {
+	var verifyOptions VerifyChainOptions
+	if options != nil {
+		verifyOptions = *options
+	}
+
+	verifier := NewChainVerifier()
+
+	// Find the head to verify up to
+	headId := verifyOptions.Head
+	if headId == "" {
+		var err error
+		headId, err = CurrentHead(transport)
+		if err != nil {
+			return verifier.Result(), err
+		}
+	}
+	head, err := transport.GetBlock(headId)
+	if err != nil {
+		return verifier.Result(), err
+	}
+
+	// Start with the checkpoint (which is trusted to be linked correctly), or the genesis block
+	firstBlockNum := uint64(0)
+	if verifyOptions.Checkpoint != "" {
+		checkpoint, err := transport.GetBlock(verifyOptions.Checkpoint)
+		if err != nil {
+			return verifier.Result(), err
+		}
+		if checkpoint.Header.BlockNum > head.Header.BlockNum {
+			return verifier.Result(), fmt.Errorf("Checkpoint %d is beyond the head at block %d", checkpoint.Header.BlockNum, head.Header.BlockNum)
+		}
+
+		err = verifier.AddCheckpoint(checkpoint)
+		if err != nil {
+			return verifier.Result(), err
+		}
+		firstBlockNum = checkpoint.Header.BlockNum + 1
+	}
+
+	if firstBlockNum <= head.Header.BlockNum {
+		cursor, err := types.NewBlockRangeCursor(firstBlockNum, head.Header.BlockNum, verifyOptions.Fetch, true)
+		if err != nil {
+			return verifier.Result(), err
+		}
+		cursor.Head = headId
+
+		iterator := transport.GetBlockIteratorFromCursor(cursor)
+		defer iterator.Close()
+
+		for iterator.Next() {
+			block, err := iterator.Current()
+			if err != nil {
+				return verifier.Result(), err
+			}
+
+			err = verifier.AddBlock(block)
+			if err != nil {
+				return verifier.Result(), err
+			}
+
+			if verifyOptions.Progress != nil {
+				verifyOptions.Progress(block)
+			}
+		}
+
+		err = iterator.Error()
+		if err != nil {
+			return verifier.Result(), err
+		}
+	}
+
+	// The walk must end at the head
+	last := verifier.Last()
+	if last == nil || last.HeaderSignature != headId {
+		blockNum := uint64(0)
+		if last != nil {
+			blockNum = last.Header.BlockNum
+		}
+		return verifier.Result(), &ChainVerificationError{
+			BlockNum: blockNum,
+			BlockId: head.HeaderSignature,
+			Reason: fmt.Sprintf("Chain ends at block %d, expected head %s at block %d", blockNum, headId, head.Header.BlockNum),
+		}
+	}
+
+	return verifier.Result(), nil
+}
+
+// verifyChainLink checks that block follows previous (which is nil for the genesis block).
+func verifyChainLink(previous *types.Block, block *types.Block) error {
+	fail := func(format string, args ...interface{}) error {
+		return &ChainVerificationError{
+			BlockNum: block.Header.BlockNum,
+			BlockId: block.HeaderSignature,
+			Reason: fmt.Sprintf(format, args...),
+		}
+	}
+
+	if previous == nil {
+		if block.Header.BlockNum != 0 {
+			return fail("Expected the genesis block, got block %d", block.Header.BlockNum)
+		}
+		if block.Header.PreviousBlockId != GENESIS_PREVIOUS_BLOCK_ID {
+			return fail("Genesis block has previous block %s", block.Header.PreviousBlockId)
+		}
+		return nil
+	}
+
+	if block.Header.BlockNum != previous.Header.BlockNum + 1 {
+		return fail("Block number follows block %d", previous.Header.BlockNum)
+	}
+	if block.Header.PreviousBlockId != previous.HeaderSignature {
+		return fail("Previous block is %s, expected %s (block %d)", block.Header.PreviousBlockId, previous.HeaderSignature, previous.Header.BlockNum)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package transport_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/block_pb2"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/conformance"
+)
+
+func TestVerifyChain(t *testing.T) {
+	transports := map[string]func(testing.TB, *conformance.Validator) transport.SawtoothClientTransport{
+		"rest": conformance.NewRestTransport,
+		"zmq": conformance.NewZmqTransport,
+	}
+
+	for name, newTransport := range transports {
+		t.Run(name, func(t *testing.T) {
+			fixture := conformance.NewFixture()
+			clientTransport := newTransport(t, conformance.NewValidator(fixture))
+			head := conformance.FIXTURE_BLOCKS - 1
+
+			check := func(description string, options *transport.VerifyChainOptions, first int, last int) {
+				t.Helper()
+
+				result, err := transport.VerifyChain(clientTransport, options)
+				if err != nil {
+					t.Fatalf("%s: %s", description, err)
+				}
+
+				transactions := len(fixture.TransactionsAt(last))
+				if first > 0 {
+					transactions -= len(fixture.TransactionsAt(first - 1))
+				}
+				if result.FirstBlockNum != uint64(first) || result.LastBlockNum != uint64(last) ||
+					result.Blocks != last - first + 1 || result.Transactions != transactions ||
+					result.Head != fixture.Blocks[last].HeaderSignature {
+					t.Fatalf("%s: unexpected result %+v", description, result)
+				}
+			}
+
+			check("VerifyChain()", nil, 0, head)
+			check("VerifyChain(checkpoint)", &transport.VerifyChainOptions{Checkpoint: fixture.Blocks[5].HeaderSignature, Fetch: 3}, 5, head)
+			check("VerifyChain(head)", &transport.VerifyChainOptions{Head: fixture.Blocks[8].HeaderSignature, Fetch: 1}, 0, 8)
+			check("VerifyChain(checkpoint at head)", &transport.VerifyChainOptions{Checkpoint: fixture.Head().HeaderSignature}, head, head)
+
+			// Serve a correctly signed block which skips its predecessor
+			original := fixture.Blocks[9]
+			header := proto.Clone(fixture.Headers[9]).(*block_pb2.BlockHeader)
+			header.PreviousBlockId = fixture.Blocks[7].HeaderSignature
+			headerBytes, err := proto.Marshal(header)
+			if err != nil {
+				t.Fatalf("Error marshaling header: %s", err)
+			}
+			fixture.Blocks[9] = &block_pb2.Block{Header: headerBytes, HeaderSignature: hex.EncodeToString(fixture.Signer.Sign(headerBytes)), Batches: original.Batches}
+
+			_, err = transport.VerifyChain(clientTransport, nil)
+			verifyErr, ok := err.(*transport.ChainVerificationError)
+			if !ok || verifyErr.BlockNum != 9 {
+				t.Fatalf("VerifyChain(unlinked): expected a ChainVerificationError at block 9, got: %v", err)
+			}
+			fixture.Blocks[9] = original
+
+			// Serve a block holding the batches of its predecessor
+			tampered := proto.Clone(fixture.Blocks[7]).(*block_pb2.Block)
+			tampered.Batches = fixture.Blocks[6].Batches
+			fixture.Blocks[7] = tampered
+
+			_, err = transport.VerifyChain(clientTransport, &transport.VerifyChainOptions{Fetch: 4})
+			verifyErr, ok = err.(*transport.ChainVerificationError)
+			if !ok || verifyErr.BlockNum != 7 || verifyErr.BlockId != tampered.HeaderSignature {
+				t.Fatalf("VerifyChain(tampered): expected a ChainVerificationError at block 7, got: %v", err)
+			}
+		})
+	}
+}
@@ -48,9 +48,9 @@ func (self *batchZmqIterator) Current() (*types.Batch, error) {
 	return data, nil
 }
 
-func (self *batchZmqIterator) BuildRequest(pagingControl *client_list_control_pb2.ClientPagingControls, sortControl []*client_list_control_pb2.ClientSortControls) (validator_pb2.Message_MessageType, proto.Message, proto.Message) {
+func (self *batchZmqIterator) BuildRequest(head string, pagingControl *client_list_control_pb2.ClientPagingControls, sortControl []*client_list_control_pb2.ClientSortControls) (validator_pb2.Message_MessageType, proto.Message, proto.Message) {
 	t := validator_pb2.Message_CLIENT_BATCH_LIST_REQUEST
-	request := client_batch_pb2.ClientBatchListRequest{Paging: pagingControl, Sorting: sortControl}
+	request := client_batch_pb2.ClientBatchListRequest{HeadId: head, Paging: pagingControl, Sorting: sortControl}
 	response := client_batch_pb2.ClientBatchListResponse{}
 	return t, &request, &response
 }
@@ -72,19 +72,13 @@ func (self *batchZmqIterator) ParseProto(message proto.Message) ([]interface{},
 
 // GetBatchIterator returns a types.BatchIterator that can iterate over all batches.
 func (self *SawtoothClientTransportZmq) GetBatchIterator(fetch int, reverse bool) types.BatchIterator {
-	pagingControl := &client_list_control_pb2.ClientPagingControls{
-		Limit: int32(fetch),
-	}
-
-	sortControl := []*client_list_control_pb2.ClientSortControls{
-		{
-			Keys: []string{"default"},
-			Reverse: reverse,
-		},
-	}
+	return self.GetBatchIteratorFromCursor(types.NewCursor(types.CURSOR_BATCHES, fetch, reverse))
+}
 
+// GetBatchIteratorFromCursor returns a types.BatchIterator that resumes the iteration recorded by cursor.
+func (self *SawtoothClientTransportZmq) GetBatchIteratorFromCursor(cursor *types.Cursor) types.BatchIterator {
 	iterator := &batchZmqIterator{}
-	iterator.commonZmqIterator = *NewCommonZmqIterator(self, pagingControl, sortControl, iterator)
+	iterator.commonZmqIterator = *newCursorZmqIterator(self, "default", types.CURSOR_BATCHES, cursor, iterator)
 
 	return iterator
 }
@@ -37,6 +37,25 @@ type blockZmqIterator struct {
 	commonZmqIterator
 }
 
+// Next returns true if a next value is available, ending the iteration at the cursor's stop block.
+func (self *blockZmqIterator) Next() bool {
+	if !self.commonZmqIterator.Next() {
+		return false
+	}
+
+	past, err := self.cursor.PastStopBlock(self.getCurrent().(*types.Block))
+	if err != nil {
+		self.err = err
+		return false
+	}
+	if past {
+		self.Close()
+		return false
+	}
+
+	return true
+}
+
 func (self *blockZmqIterator) Current() (*types.Block, error) {
 	err := self.checkCurrent()
 	if err != nil {
@@ -47,9 +66,9 @@ func (self *blockZmqIterator) Current() (*types.Block, error) {
 	return data, nil
 }
 
-func (self *blockZmqIterator) BuildRequest(pagingControl *client_list_control_pb2.ClientPagingControls, sortControl []*client_list_control_pb2.ClientSortControls) (validator_pb2.Message_MessageType, proto.Message, proto.Message) {
+func (self *blockZmqIterator) BuildRequest(head string, pagingControl *client_list_control_pb2.ClientPagingControls, sortControl []*client_list_control_pb2.ClientSortControls) (validator_pb2.Message_MessageType, proto.Message, proto.Message) {
 	t := validator_pb2.Message_CLIENT_BLOCK_LIST_REQUEST
-	request := client_block_pb2.ClientBlockListRequest{Paging: pagingControl, Sorting: sortControl}
+	request := client_block_pb2.ClientBlockListRequest{HeadId: head, Paging: pagingControl, Sorting: sortControl}
 	response := client_block_pb2.ClientBlockListResponse{}
 	return t, &request, &response
 }
@@ -71,19 +90,57 @@ func (self *blockZmqIterator) ParseProto(message proto.Message) ([]interface{},
 
 // GetBlockIterator returns a types.BlockIterator that can iterate over all blocks.
 func (self *SawtoothClientTransportZmq) GetBlockIterator(fetch int, reverse bool) types.BlockIterator {
-	pagingControl := &client_list_control_pb2.ClientPagingControls{
-		Limit: int32(fetch),
+	return self.GetBlockIteratorFromCursor(types.NewCursor(types.CURSOR_BLOCKS, fetch, reverse))
+}
+
+// GetBlockIteratorFromCursor returns a types.BlockIterator that resumes the iteration recorded by cursor.
+func (self *SawtoothClientTransportZmq) GetBlockIteratorFromCursor(cursor *types.Cursor) types.BlockIterator {
+	clamped, err := types.ClampBlockCursor(cursor, func() types.BlockIterator {
+		return self.GetBlockIterator(1, false)
+	})
+	if err != nil {
+		return self.newBlockIteratorWithError(err)
 	}
 
-	sortControl := []*client_list_control_pb2.ClientSortControls{
-		{
-			Keys: []string{"block_num"},
-			Reverse: reverse,
-		},
+	iterator := &blockZmqIterator{}
+	if clamped == nil {
+		// Nothing to list yet, but the cursor can still be resumed once blocks are added
+		iterator.commonZmqIterator = *newCursorZmqIterator(self, "block_num", types.CURSOR_BLOCKS, cursor, iterator)
+		iterator.nextPagingControl = nil
+		return iterator
 	}
+	iterator.commonZmqIterator = *newCursorZmqIterator(self, "block_num", types.CURSOR_BLOCKS, clamped, iterator)
+
+	return iterator
+}
+
+// GetBlockRangeIterator returns a types.BlockIterator over the blocks numbered first to last
+// (inclusive), newest first unless reverse is true.
+func (self *SawtoothClientTransportZmq) GetBlockRangeIterator(first uint64, last uint64, fetch int, reverse bool) types.BlockIterator {
+	cursor, err := types.NewBlockRangeCursor(first, last, fetch, reverse)
+	if err != nil {
+		return self.newBlockIteratorWithError(err)
+	}
+
+	return self.GetBlockIteratorFromCursor(cursor)
+}
+
+// GetBlockIteratorSince returns a types.BlockIterator over the blocks following blockId, oldest
+// first, up to the chain head.
+func (self *SawtoothClientTransportZmq) GetBlockIteratorSince(blockId string, fetch int) types.BlockIterator {
+	block, err := self.GetBlock(blockId)
+	if err != nil {
+		return self.newBlockIteratorWithError(err)
+	}
+
+	return self.GetBlockIteratorFromCursor(types.NewBlockSinceCursor(block.Header.BlockNum, fetch))
+}
 
+// newBlockIteratorWithError returns a block iterator that yields no values and reports err (if any).
+func (self *SawtoothClientTransportZmq) newBlockIteratorWithError(err error) types.BlockIterator {
 	iterator := &blockZmqIterator{}
-	iterator.commonZmqIterator = *NewCommonZmqIterator(self, pagingControl, sortControl, iterator)
+	iterator.commonZmqIterator = *NewCommonZmqIterator(self, nil, nil, iterator)
+	iterator.err = err
 
 	return iterator
 }
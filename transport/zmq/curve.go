@@ -0,0 +1,288 @@
+package zmq
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/hyperledger/sawtooth-sdk-go/messaging"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/validator_pb2"
+	"github.com/pebbe/zmq4"
+)
+
+// CURVE_KEY_LENGTH is the length of a Z85-encoded CurveZMQ key.
+const CURVE_KEY_LENGTH = 40
+
+// CurveKeyPair represents a CurveZMQ keypair. Keys are Z85-encoded, which is the format used
+// by the validator's network_public_key and network_private_key settings.
+type CurveKeyPair struct {
+	// PublicKey is the Z85-encoded public key.
+	PublicKey	string
+	// SecretKey is the Z85-encoded secret key.
+	SecretKey	string
+}
+
+// CurveOptions configures CurveZMQ encryption and authentication for the ZMQ transport.
+type CurveOptions struct {
+	// ServerPublicKey is the Z85-encoded public key of the validator (its network_public_key).
+	ServerPublicKey	string
+	// ClientKeys is the keypair used by the client. If nil, a new keypair is generated for
+	// the transport (this encrypts the connection but does not authenticate the client).
+	ClientKeys		*CurveKeyPair
+}
+
+// NewCurveKeyPair generates a new CurveZMQ keypair.
+func NewCurveKeyPair() (*CurveKeyPair, error) {
+	if !zmq4.HasCurve() {
+		return nil, fmt.Errorf("libzmq was built without CurveZMQ support")
+	}
+
+	publicKey, secretKey, err := zmq4.NewCurveKeypair()
+	if err != nil {
+		return nil, fmt.Errorf("Error generating CurveZMQ keypair: %s", err)
+	}
+
+	return &CurveKeyPair{PublicKey: publicKey, SecretKey: secretKey}, nil
+}
+
+// LoadCurveKeyPair reads a keypair from a ZMQ certificate file (as written by Save or by
+// zcert/czmq) containing "public-key" and "secret-key" entries.
+func LoadCurveKeyPair(path string) (*CurveKeyPair, error) {
+	entries, err := readCurveCertificate(path)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPair := &CurveKeyPair{PublicKey: entries["public-key"], SecretKey: entries["secret-key"]}
+	err = keyPair.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid CurveZMQ keypair in file (%s): %s", path, err)
+	}
+
+	return keyPair, nil
+}
+
+// LoadCurvePublicKey reads a public key from a file. The file may either be a ZMQ certificate
+// file containing a "public-key" entry, or contain only the Z85-encoded key.
+func LoadCurvePublicKey(path string) (string, error) {
+	entries, err := readCurveCertificate(path)
+	if err != nil {
+		return "", err
+	}
+
+	publicKey, ok := entries["public-key"]
+	if !ok {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("Could not read CurveZMQ public key from file (%s) with error: %s", path, err)
+		}
+		publicKey = strings.TrimSpace(string(data))
+	}
+
+	err = validateCurveKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("Invalid CurveZMQ public key in file (%s): %s", path, err)
+	}
+
+	return publicKey, nil
+}
+
+// Save writes the keypair to a ZMQ certificate file that is only readable by the current user.
+func (self *CurveKeyPair) Save(path string) error {
+	err := self.Validate()
+	if err != nil {
+		return err
+	}
+
+	data := fmt.Sprintf("curve\n    public-key = \"%s\"\n    secret-key = \"%s\"\n", self.PublicKey, self.SecretKey)
+	err = ioutil.WriteFile(path, []byte(data), 0600)
+	if err != nil {
+		return fmt.Errorf("Could not write CurveZMQ keypair to file (%s) with error: %s", path, err)
+	}
+
+	return nil
+}
+
+// Validate checks that both keys are well-formed Z85-encoded CurveZMQ keys.
+func (self *CurveKeyPair) Validate() error {
+	err := validateCurveKey(self.PublicKey)
+	if err != nil {
+		return fmt.Errorf("public key: %s", err)
+	}
+
+	err = validateCurveKey(self.SecretKey)
+	if err != nil {
+		return fmt.Errorf("secret key: %s", err)
+	}
+
+	return nil
+}
+
+// z85Alphabet holds the characters of the Z85 encoding, in the order of their values.
+const z85Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.-:+=^!/*?&<>()[]{}@%$#"
+
+// validateCurveKey checks that a key is a Z85-encoded 32 byte key. This is checked here, as
+// zmq4.Z85decode does not report invalid input.
+func validateCurveKey(key string) error {
+	if len(key) != CURVE_KEY_LENGTH {
+		return fmt.Errorf("expected %d Z85 characters, got %d", CURVE_KEY_LENGTH, len(key))
+	}
+
+	// Each group of 5 characters encodes 4 bytes
+	for i := 0; i < len(key); i += 5 {
+		var value uint64
+		for _, c := range key[i:i + 5] {
+			digit := strings.IndexRune(z85Alphabet, c)
+			if digit < 0 {
+				return fmt.Errorf("key is not valid Z85 (invalid character %q)", c)
+			}
+			value = value * 85 + uint64(digit)
+		}
+		if value > math.MaxUint32 {
+			return fmt.Errorf("key is not valid Z85 (%s is out of range)", key[i:i + 5])
+		}
+	}
+
+	return nil
+}
+
+// readCurveCertificate parses the "key = value" entries of a ZMQ certificate file.
+func readCurveCertificate(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Could not open CurveZMQ key file (%s) with error: %s", path, err)
+	}
+	defer file.Close()
+
+	entries := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		key := strings.TrimSpace(parts[0])
+		value := strings.Trim(strings.TrimSpace(parts[1]), "\"")
+		entries[key] = value
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Could not read CurveZMQ key file (%s) with error: %s", path, err)
+	}
+
+	return entries, nil
+}
+
+// curveZmqConnection is a DEALER connection to the validator secured with CurveZMQ.
+// messaging.NewConnection connects as soon as the socket is created, which leaves no room to
+// configure the Curve options, so this provides the parts of messaging.Connection we use.
+type curveZmqConnection struct {
+	identity	string
+	socket		*zmq4.Socket
+	incoming	map[string]*validator_pb2.Message
+}
+
+// newCurveZmqConnection creates a DEALER socket, configures it as a CurveZMQ client and connects it.
+func newCurveZmqConnection(context *zmq4.Context, uri string, options *CurveOptions) (*curveZmqConnection, error) {
+	socket, err := context.NewSocket(zmq4.DEALER)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create ZMQ socket: %s", err)
+	}
+
+	identity := messaging.GenerateId()
+	err = socket.SetIdentity(identity)
+	if err == nil {
+		err = socket.SetCurveServerkey(options.ServerPublicKey)
+	}
+	if err == nil {
+		err = socket.SetCurvePublickey(options.ClientKeys.PublicKey)
+	}
+	if err == nil {
+		err = socket.SetCurveSecretkey(options.ClientKeys.SecretKey)
+	}
+	if err != nil {
+		socket.Close()
+		return nil, fmt.Errorf("Failed to configure CurveZMQ: %s", err)
+	}
+
+	err = socket.Connect(uri)
+	if err != nil {
+		socket.Close()
+		return nil, fmt.Errorf("Failed to establish connection to %s: %s", uri, err)
+	}
+
+	conn := &curveZmqConnection{
+		identity: identity,
+		socket: socket,
+		incoming: make(map[string]*validator_pb2.Message),
+	}
+
+	return conn, nil
+}
+
+// SendNewMsg sends a new validator message and returns its correlation id.
+func (self *curveZmqConnection) SendNewMsg(t validator_pb2.Message_MessageType, c []byte) (string, error) {
+	corrId := messaging.GenerateId()
+
+	data, err := messaging.DumpMsg(t, c, corrId)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = self.socket.SendMessage([][]byte{data})
+	if err != nil {
+		return "", err
+	}
+
+	return corrId, nil
+}
+
+// RecvMsgWithId receives validator messages until the one with the given correlation id arrives.
+// Any other messages received are stored for subsequent calls.
+func (self *curveZmqConnection) RecvMsgWithId(corrId string) (string, *validator_pb2.Message, error) {
+	if msg, exists := self.incoming[corrId]; exists {
+		delete(self.incoming, corrId)
+		return "", msg, nil
+	}
+
+	for {
+		parts, err := self.socket.RecvMessageBytes(0)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(parts) != 1 {
+			return "", nil, fmt.Errorf("Receive message with unexpected length: %d", len(parts))
+		}
+
+		msg, err := messaging.LoadMsg(parts[0])
+		if err != nil {
+			return "", nil, err
+		}
+
+		if msg.GetCorrelationId() == corrId {
+			return "", msg, nil
+		}
+
+		self.incoming[msg.GetCorrelationId()] = msg
+	}
+}
+
+// Close closes the underlying socket.
+func (self *curveZmqConnection) Close() {
+	self.socket.Close()
+}
+
+// Identity returns the identity assigned to the socket.
+func (self *curveZmqConnection) Identity() string {
+	return self.identity
+}
@@ -0,0 +1,233 @@
+package zmq_test
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/sawtooth-sdk-go/messaging"
+	"github.com/pebbe/zmq4"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/conformance"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/zmq"
+)
+
+// newCurveKeyPair returns a new keypair, skipping the test if libzmq has no CurveZMQ support.
+func newCurveKeyPair(t *testing.T) *zmq.CurveKeyPair {
+	t.Helper()
+
+	if !zmq4.HasCurve() {
+		t.Skip("libzmq was built without CurveZMQ support")
+	}
+
+	keyPair, err := zmq.NewCurveKeyPair()
+	if err != nil {
+		t.Fatalf("NewCurveKeyPair: %s", err)
+	}
+
+	return keyPair
+}
+
+// serveCurve answers CurveZMQ connections from the validator until the test completes, and returns
+// the URL to connect to.
+func serveCurve(t *testing.T, validator *conformance.Validator, serverKeys *zmq.CurveKeyPair) *url.URL {
+	t.Helper()
+
+	context, err := zmq4.NewContext()
+	if err != nil {
+		t.Fatalf("Error creating ZMQ context: %s", err)
+	}
+	socket, err := context.NewSocket(zmq4.ROUTER)
+	if err == nil {
+		err = socket.SetCurveServer(1)
+	}
+	if err == nil {
+		err = socket.SetCurveSecretkey(serverKeys.SecretKey)
+	}
+	if err == nil {
+		err = socket.Bind("tcp://127.0.0.1:*")
+	}
+	if err != nil {
+		t.Fatalf("Error creating server socket: %s", err)
+	}
+	endpoint, _ := socket.GetLastEndpoint()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer socket.Close()
+
+		poller := zmq4.NewPoller()
+		poller.Add(socket, zmq4.POLLIN)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			polled, err := poller.Poll(10 * time.Millisecond)
+			if err != nil {
+				return
+			}
+			if len(polled) == 0 {
+				continue
+			}
+
+			parts, err := socket.RecvMessageBytes(0)
+			if err != nil || len(parts) != 2 {
+				continue
+			}
+			request, err := messaging.LoadMsg(parts[1])
+			if err != nil {
+				continue
+			}
+			responseType, content, err := validator.Handle(request.MessageType, request.Content)
+			if err != nil {
+				continue
+			}
+			data, _ := messaging.DumpMsg(responseType, content, request.CorrelationId)
+			socket.SendMessage(parts[0], data)
+		}
+	}()
+	t.Cleanup(func() {
+		close(stop)
+		<-done
+	})
+
+	serverUrl, _ := url.Parse(endpoint)
+	return serverUrl
+}
+
+func TestCurveKeyPair(t *testing.T) {
+	// The client keypair of the libzmq CurveZMQ test vectors
+	keyPair := &zmq.CurveKeyPair{PublicKey: "Yne@$w-vo<fVvi]a<NY6T1ed:M$fCG*[IaLV{hID", SecretKey: "D:)Q[IlAW!ahhC2ac:9*A}h:p?([4%wOTJ%JR%cs"}
+	err := keyPair.Validate()
+	if err != nil {
+		t.Fatalf("Validate: %s", err)
+	}
+
+	// A saved keypair is only readable by its owner, and loads back unchanged
+	dir := t.TempDir()
+	path := filepath.Join(dir, "client.key_secret")
+	err = keyPair.Save(path)
+	if err != nil {
+		t.Fatalf("Save: %s", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("Save: unexpected file mode (%v)", err)
+	}
+
+	loaded, err := zmq.LoadCurveKeyPair(path)
+	if err != nil {
+		t.Fatalf("LoadCurveKeyPair: %s", err)
+	}
+	if *loaded != *keyPair {
+		t.Fatalf("LoadCurveKeyPair: got %+v, expected %+v", loaded, keyPair)
+	}
+
+	// A public key is read from a certificate, or from a file holding only the key
+	publicKey, err := zmq.LoadCurvePublicKey(path)
+	if err != nil || publicKey != keyPair.PublicKey {
+		t.Fatalf("LoadCurvePublicKey(certificate): got %s (%v), expected %s", publicKey, err, keyPair.PublicKey)
+	}
+	publicPath := filepath.Join(dir, "server.key")
+	os.WriteFile(publicPath, []byte(keyPair.PublicKey + "\n"), 0644)
+	publicKey, err = zmq.LoadCurvePublicKey(publicPath)
+	if err != nil || publicKey != keyPair.PublicKey {
+		t.Fatalf("LoadCurvePublicKey(key): got %s (%v), expected %s", publicKey, err, keyPair.PublicKey)
+	}
+
+	// Keys must be 40 Z85 characters, each group of 5 encoding 4 bytes
+	invalid := map[string]string{
+		"short": keyPair.PublicKey[:zmq.CURVE_KEY_LENGTH - 1],
+		"long": keyPair.PublicKey + "0",
+		"not z85": "~" + keyPair.PublicKey[1:],
+		"out of range": "#####" + keyPair.PublicKey[5:],
+		"empty": "",
+	}
+	for name, key := range invalid {
+		err = (&zmq.CurveKeyPair{PublicKey: key, SecretKey: keyPair.SecretKey}).Validate()
+		if err == nil {
+			t.Fatalf("Validate(%s public key): expected an error", name)
+		}
+		err = (&zmq.CurveKeyPair{PublicKey: keyPair.PublicKey, SecretKey: key}).Save(filepath.Join(dir, "invalid"))
+		if err == nil {
+			t.Fatalf("Save(%s secret key): expected an error", name)
+		}
+
+		os.WriteFile(publicPath, []byte(key), 0644)
+		_, err = zmq.LoadCurvePublicKey(publicPath)
+		if err == nil {
+			t.Fatalf("LoadCurvePublicKey(%s): expected an error", name)
+		}
+
+		certificate := "curve\n    public-key = \"" + keyPair.PublicKey + "\"\n    secret-key = \"" + key + "\"\n"
+		os.WriteFile(path, []byte(certificate), 0600)
+		_, err = zmq.LoadCurveKeyPair(path)
+		if err == nil || !strings.Contains(err.Error(), "secret key") {
+			t.Fatalf("LoadCurveKeyPair(%s secret key): expected an error, got: %v", name, err)
+		}
+	}
+
+	_, err = zmq.LoadCurveKeyPair(filepath.Join(dir, "missing"))
+	if err == nil {
+		t.Fatalf("LoadCurveKeyPair(missing): expected an error")
+	}
+}
+
+func TestCurveTransport(t *testing.T) {
+	serverKeys := newCurveKeyPair(t)
+	clientKeys := newCurveKeyPair(t)
+	fixture := conformance.NewFixture()
+	serverUrl := serveCurve(t, conformance.NewValidator(fixture), serverKeys)
+
+	if len(clientKeys.PublicKey) != zmq.CURVE_KEY_LENGTH || clientKeys.Validate() != nil {
+		t.Fatalf("NewCurveKeyPair: invalid keypair %+v", clientKeys)
+	}
+
+	// Without client keys, each transport generates its own
+	var generated []string
+	for i := 0; i < 2; i++ {
+		client, err := zmq.NewSawtoothClientTransportZmqWithOptions(serverUrl, &zmq.SawtoothClientTransportZmqOptions{Curve: &zmq.CurveOptions{ServerPublicKey: serverKeys.PublicKey}})
+		if err != nil {
+			t.Fatalf("NewSawtoothClientTransportZmqWithOptions(ephemeral): %s", err)
+		}
+		if client.Curve.ClientKeys == nil || client.Curve.ClientKeys.Validate() != nil {
+			t.Fatalf("NewSawtoothClientTransportZmqWithOptions(ephemeral): invalid client keys %+v", client.Curve.ClientKeys)
+		}
+		generated = append(generated, client.Curve.ClientKeys.PublicKey)
+
+		block, err := client.GetBlock(fixture.Head().HeaderSignature)
+		if err != nil || block.HeaderSignature != fixture.Head().HeaderSignature {
+			t.Fatalf("GetBlock(ephemeral): got %v (%v)", block, err)
+		}
+	}
+	if generated[0] == generated[1] {
+		t.Fatalf("NewSawtoothClientTransportZmqWithOptions(ephemeral): client keys were reused")
+	}
+
+	options := &zmq.SawtoothClientTransportZmqOptions{Curve: &zmq.CurveOptions{ServerPublicKey: serverKeys.PublicKey, ClientKeys: clientKeys}}
+	client, err := zmq.NewSawtoothClientTransportZmqWithOptions(serverUrl, options)
+	if err != nil {
+		t.Fatalf("NewSawtoothClientTransportZmqWithOptions: %s", err)
+	}
+	if *client.Curve.ClientKeys != *clientKeys {
+		t.Fatalf("NewSawtoothClientTransportZmqWithOptions: client keys were replaced")
+	}
+
+	// Malformed keys are rejected before connecting
+	for _, curve := range []*zmq.CurveOptions{
+		{ServerPublicKey: serverKeys.PublicKey[1:]},
+		{ServerPublicKey: serverKeys.PublicKey, ClientKeys: &zmq.CurveKeyPair{PublicKey: clientKeys.PublicKey, SecretKey: "~" + clientKeys.SecretKey[1:]}},
+	} {
+		_, err = zmq.NewSawtoothClientTransportZmqWithOptions(serverUrl, &zmq.SawtoothClientTransportZmqOptions{Curve: curve})
+		if err == nil {
+			t.Fatalf("NewSawtoothClientTransportZmqWithOptions(%+v): expected an error", curve)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_batch_submit_pb2"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_block_pb2"
 	client_peer "github.com/hyperledger/sawtooth-sdk-go/protobuf/client_peers_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_receipt_pb2"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_state_pb2"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_transaction_pb2"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/validator_pb2"
@@ -198,6 +199,18 @@ func checkForError(response proto.Message) errors.SawtoothTransportErrorCode {
 		case client_state_pb2.ClientStateListResponse_NO_RESOURCE:
 			return errors.STATE_NOT_FOUND
 		}
+	case *client_receipt_pb2.ClientReceiptGetResponse:
+		switch r.Status {
+		case client_receipt_pb2.ClientReceiptGetResponse_OK:
+			return errors.NO_ERROR
+		case client_receipt_pb2.ClientReceiptGetResponse_INTERNAL_ERROR:
+			return errors.VALIDATOR_UNKNOWN_ERROR
+		case client_receipt_pb2.ClientReceiptGetResponse_INVALID_ID:
+			return errors.INVALID_RESOURCE_ID
+		case client_receipt_pb2.ClientReceiptGetResponse_NO_RESOURCE:
+			return errors.TRANSACTION_RECEIPT_NOT_FOUND
+		}
+
 	case *client_peer.ClientPeersGetResponse:
 		switch r.Status {
 		case client_peer.ClientPeersGetResponse_OK:
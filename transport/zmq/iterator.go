@@ -5,20 +5,35 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_list_control_pb2"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/validator_pb2"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
 )
 
 // zmqIteratorImpl must be implemented by all ZMQ iterators.
+// BuildRequest is passed the head to read from ("" for the current chain head).
 type zmqIteratorImpl interface {
-	BuildRequest(*client_list_control_pb2.ClientPagingControls, []*client_list_control_pb2.ClientSortControls) (validator_pb2.Message_MessageType, proto.Message, proto.Message)
+	BuildRequest(string, *client_list_control_pb2.ClientPagingControls, []*client_list_control_pb2.ClientSortControls) (validator_pb2.Message_MessageType, proto.Message, proto.Message)
 	ParseProto(message proto.Message) ([]interface{}, error)
 }
 
+// zmqHeadResponseGetter is a special interface used to extract the head a list response was read from.
+type zmqHeadResponseGetter interface {
+	GetHeadId() string
+}
+
 // zmqPagingResponseGetter is a special interface used to extract the paging info
 // from different proto buffer objects.
 type zmqPagingResponseGetter interface {
 	GetPaging() *client_list_control_pb2.ClientPagingResponse
 }
 
+// zmqIteratorPage holds a single page of results.
+type zmqIteratorPage struct {
+	data	[]interface{}
+	start	string
+	head	string
+	err		error
+}
+
 // commonZmqIterator implements an iterator for the validator ZMQ interface that can be extended to be used
 // across multiple object types.
 type commonZmqIterator struct {
@@ -30,6 +45,20 @@ type commonZmqIterator struct {
 	current		interface{}
 	err			error
 
+	// prefetch is the number of pages to read ahead in the background (0 disables prefetching).
+	prefetch	int
+	pages		chan zmqIteratorPage
+	stop		chan struct{}
+
+	// cursor holds the query parameters of the iteration; head, pageStart and consumed track
+	// the position of the caller, and skip is the number of values still to be dropped when
+	// resuming from a cursor.
+	cursor		types.Cursor
+	head		string
+	pageStart	string
+	consumed	int
+	skip		int
+
 	impl		zmqIteratorImpl
 }
 
@@ -38,7 +67,38 @@ func NewCommonZmqIterator(transport *SawtoothClientTransportZmq,
 							pagingControl *client_list_control_pb2.ClientPagingControls,
 							sortControl []*client_list_control_pb2.ClientSortControls,
 							impl zmqIteratorImpl) *commonZmqIterator {
-	return &commonZmqIterator{transport: transport, nextPagingControl: pagingControl, sortControl: sortControl, impl: impl}
+	return &commonZmqIterator{transport: transport, nextPagingControl: pagingControl, sortControl: sortControl, impl: impl, prefetch: transport.Prefetch}
+}
+
+// newCursorZmqIterator returns a new commonZmqIterator sorted by sortKey, positioned according to
+// cursor. If the cursor is not valid for kind, the iterator reports the error.
+func newCursorZmqIterator(transport *SawtoothClientTransportZmq, sortKey string, kind types.CursorKind, cursor *types.Cursor, impl zmqIteratorImpl) *commonZmqIterator {
+	err := cursor.Check(kind)
+	if err != nil {
+		iterator := NewCommonZmqIterator(transport, nil, nil, impl)
+		iterator.err = err
+		return iterator
+	}
+
+	pagingControl := &client_list_control_pb2.ClientPagingControls{
+		Start: cursor.Start,
+		Limit: int32(cursor.Limit),
+	}
+
+	sortControl := []*client_list_control_pb2.ClientSortControls{
+		{
+			Keys: []string{sortKey},
+			Reverse: cursor.Reverse,
+		},
+	}
+
+	iterator := NewCommonZmqIterator(transport, pagingControl, sortControl, impl)
+	iterator.cursor = *cursor
+	iterator.head = cursor.Head
+	iterator.pageStart = cursor.Start
+	iterator.skip = cursor.Skip
+
+	return iterator
 }
 
 // Next returns true if a next value is available.
@@ -55,6 +115,7 @@ func (self *commonZmqIterator) Next() bool {
 
 	// Pop and shift
 	self.current, self.data = self.data[0], self.data[1:]
+	self.consumed++
 
 	return true
 }
@@ -64,45 +125,183 @@ func (self *commonZmqIterator) Error() error {
 	return self.err
 }
 
+// SetPrefetch sets the number of pages to fetch in the background ahead of the caller.
+// It must be called before the first call to Next().
+func (self *commonZmqIterator) SetPrefetch(depth int) {
+	self.prefetch = depth
+}
+
+// Close stops any background prefetching. The iterator returns no further values.
+func (self *commonZmqIterator) Close() {
+	if self.stop != nil {
+		close(self.stop)
+		self.stop = nil
+	}
+	self.nextPagingControl = nil
+	self.pages = nil
+	self.data = nil
+}
+
+// Cursor returns a cursor recording the position of the iterator.
+func (self *commonZmqIterator) Cursor() (*types.Cursor, error) {
+	if self.err != nil {
+		return nil, self.err
+	}
+
+	cursor := self.cursor
+	if !cursor.Since {
+		cursor.Head = self.head
+	}
+	cursor.Start = self.pageStart
+	cursor.Skip = self.consumed + self.skip
+
+	return &cursor, nil
+}
+
 // fetchNext get the next value or batch of values from the validator.
 func (self *commonZmqIterator) fetchNext() error {
 	if len(self.data) > 0 {
 		return nil
 	}
 
-	if self.nextPagingControl == nil {
+	if self.err != nil {
 		return nil
 	}
 
-	if self.err != nil {
-		return nil
+	if self.prefetch > 0 || self.pages != nil {
+		return self.fetchNextPrefetched()
+	}
+
+	for len(self.data) == 0 && self.nextPagingControl != nil {
+		page, nextPagingControl := self.fetchPage(self.head, self.nextPagingControl)
+		if page.err != nil {
+			return page.err
+		}
+		self.nextPagingControl = nextPagingControl
+		self.setPage(page)
+	}
+
+	return nil
+}
+
+// fetchNextPrefetched takes the next page from the background fetcher, starting it if required.
+func (self *commonZmqIterator) fetchNextPrefetched() error {
+	if self.pages == nil {
+		if self.nextPagingControl == nil {
+			return nil
+		}
+		self.startPrefetch()
+	}
+
+	for len(self.data) == 0 {
+		page, ok := <-self.pages
+		if !ok {
+			self.pages = nil
+			return nil
+		}
+
+		if page.err != nil {
+			return page.err
+		}
+		self.setPage(page)
 	}
 
+	return nil
+}
+
+// setPage makes page the current page, dropping any values that were already consumed before
+// the iterator was resumed from a cursor.
+func (self *commonZmqIterator) setPage(page zmqIteratorPage) {
+	if self.head == "" {
+		self.head = page.head
+	}
+	self.pageStart = page.start
+	self.consumed = 0
+	self.data = page.data
+
+	if self.skip > 0 {
+		skip := self.skip
+		if skip > len(self.data) {
+			skip = len(self.data)
+		}
+		self.data = self.data[skip:]
+		self.consumed = skip
+		self.skip -= skip
+	}
+}
+
+// startPrefetch starts a goroutine that fetches pages ahead of the caller, keeping up to
+// self.prefetch pages buffered.
+func (self *commonZmqIterator) startPrefetch() {
+	pages := make(chan zmqIteratorPage, self.prefetch)
+	stop := make(chan struct{})
+	nextPagingControl := self.nextPagingControl
+	head := self.head
+
+	self.pages = pages
+	self.stop = stop
+	self.nextPagingControl = nil
+
+	go func() {
+		defer close(pages)
+
+		for nextPagingControl != nil {
+			var page zmqIteratorPage
+			page, nextPagingControl = self.fetchPage(head, nextPagingControl)
+			if head == "" {
+				head = page.head
+			}
+
+			select {
+			case pages <- page:
+			case <-stop:
+				return
+			}
+
+			if page.err != nil {
+				return
+			}
+		}
+	}()
+}
+
+// fetchPage fetches a single page from the validator at the given head, returning it along with
+// the paging controls for the next page (nil if there are no more pages).
+func (self *commonZmqIterator) fetchPage(head string, pagingControl *client_list_control_pb2.ClientPagingControls) (zmqIteratorPage, *client_list_control_pb2.ClientPagingControls) {
+	page := zmqIteratorPage{start: pagingControl.Start, head: head}
+
 	// Do the ZMQ request
-	t, requestMsg, responseMsg := self.impl.BuildRequest(self.nextPagingControl, self.sortControl)
+	t, requestMsg, responseMsg := self.impl.BuildRequest(head, pagingControl, self.sortControl)
 	err := self.transport.doZmqRequest(t, requestMsg, responseMsg)
 	if err != nil {
-		return nil
+		page.err = err
+		return page, nil
 	}
 
 	// Parse out the actual data
-	self.data, err = self.impl.ParseProto(responseMsg)
+	page.data, err = self.impl.ParseProto(responseMsg)
 	if err != nil {
-		return err
+		page.err = err
+		return page, nil
+	}
+
+	// Record the head the page was read from, so the following pages use the same one
+	if headGetter, ok := responseMsg.(zmqHeadResponseGetter); ok && page.head == "" {
+		page.head = headGetter.GetHeadId()
 	}
 
 	// Get the next paging info
 	pagingResponse := responseMsg.(zmqPagingResponseGetter).GetPaging()
-	if pagingResponse.Next == "" {
-		self.nextPagingControl = nil
-	} else {
-		self.nextPagingControl = &client_list_control_pb2.ClientPagingControls{
-			Start: pagingResponse.Next,
-			Limit: self.nextPagingControl.Limit,
-		}
+	if pagingResponse.GetNext() == "" {
+		return page, nil
 	}
 
-	return nil
+	nextPagingControl := &client_list_control_pb2.ClientPagingControls{
+		Start: pagingResponse.Next,
+		Limit: pagingControl.Limit,
+	}
+
+	return page, nextPagingControl
 }
 
 // checkCurrent checks to make sure there is a current value in the iterator. If no current
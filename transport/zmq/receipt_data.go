@@ -0,0 +1,42 @@
+package zmq
+
+import (
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_receipt_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/validator_pb2"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+// GetTransactionReceipt returns the receipt of the committed transaction represented by transactionId.
+func (self *SawtoothClientTransportZmq) GetTransactionReceipt(transactionId string) (*types.TransactionReceipt, error) {
+	receipts, err := self.GetTransactionReceipts([]string{transactionId})
+	if err != nil {
+		return nil, err
+	}
+
+	return receipts[transactionId], nil
+}
+
+// GetTransactionReceipts returns the receipts of a list of committed transactions. If any of the
+// transactions has no receipt, an error with code TRANSACTION_RECEIPT_NOT_FOUND is returned.
+func (self *SawtoothClientTransportZmq) GetTransactionReceipts(transactionIds []string) (map[string]*types.TransactionReceipt, error) {
+	// Set up the request
+	t := validator_pb2.Message_CLIENT_RECEIPT_GET_REQUEST
+	request := client_receipt_pb2.ClientReceiptGetRequest{
+		TransactionIds: transactionIds,
+	}
+
+	// Send the request and get the response
+	var response client_receipt_pb2.ClientReceiptGetResponse
+	err := self.doZmqRequest(t, &request, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the result map from the returned data
+	resultMap := make(map[string]*types.TransactionReceipt, len(response.Receipts))
+	for _, receipt := range response.Receipts {
+		resultMap[receipt.TransactionId] = types.TransactionReceiptFromProto(receipt)
+	}
+
+	return resultMap, nil
+}
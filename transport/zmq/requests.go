@@ -23,6 +23,11 @@ func (self *SawtoothClientTransportZmq) doZmqRequest(t validator_pb2.Message_Mes
 	}
 
 	_, responseMsg, err := connection.RecvMsgWithId(corrId)
+	if err != nil {
+		connection.Close()
+		return errors.NewSawtoothClientTransportRequestError(err)
+	}
+
 	err = proto.Unmarshal(responseMsg.GetContent(), response)
 	if err != nil {
 		return errors.NewSawtoothClientTransportRequestError(err)
@@ -33,14 +38,73 @@ func (self *SawtoothClientTransportZmq) doZmqRequest(t validator_pb2.Message_Mes
 		return errors.NewSawtoothClientTransportRequestError(err)
 	}
 
+	return checkResponse(t, request, response)
+}
+
+// doZmqPipelinedRequests sends several requests of the same type over a single connection without
+// waiting for each reply, keeping up to PIPELINE_DEPTH requests in flight. Responses are unmarshaled
+// into the corresponding element of responses. The returned slice holds the error (if any) reported
+// by the validator for each request; the second return value is set if the exchange itself failed.
+func (self *SawtoothClientTransportZmq) doZmqPipelinedRequests(t validator_pb2.Message_MessageType, requests []proto.Message, responses []proto.Message) ([]error, error) {
+	connection, err := self.getConnection()
+	if err != nil {
+		return nil, errors.NewSawtoothClientTransportRequestError(err)
+	}
+
+	corrIds := make([]string, len(requests))
+	results := make([]error, len(requests))
+
+	sent := 0
+	for received := 0; received < len(requests); received++ {
+		// Fill the pipeline
+		for sent < len(requests) && sent - received < PIPELINE_DEPTH {
+			requestMsg, err := proto.Marshal(requests[sent])
+			if err != nil {
+				connection.Close()
+				return nil, errors.NewSawtoothClientTransportRequestError(err)
+			}
+
+			corrIds[sent], err = connection.SendNewMsg(t, requestMsg)
+			if err != nil {
+				connection.Close()
+				return nil, errors.NewSawtoothClientTransportRequestError(err)
+			}
+			sent++
+		}
+
+		// Wait for the oldest outstanding reply
+		_, responseMsg, err := connection.RecvMsgWithId(corrIds[received])
+		if err != nil {
+			connection.Close()
+			return nil, errors.NewSawtoothClientTransportRequestError(err)
+		}
+
+		err = proto.Unmarshal(responseMsg.GetContent(), responses[received])
+		if err != nil {
+			connection.Close()
+			return nil, errors.NewSawtoothClientTransportRequestError(err)
+		}
+
+		results[received] = checkResponse(t, requests[received], responses[received])
+	}
+
+	err = self.putConnection(connection)
+	if err != nil {
+		return nil, errors.NewSawtoothClientTransportRequestError(err)
+	}
+
+	return results, nil
+}
+
+// checkResponse returns a SawtoothClientTransportError if the response reports an error.
+func checkResponse(t validator_pb2.Message_MessageType, request proto.Message, response proto.Message) error {
 	errorCode := checkForError(response)
 	if errorCode != errors.NO_ERROR {
 		transportError := NewSawtoothClientTransportZmqError(t, request, response, errorCode)
-		err = &errors.SawtoothClientTransportError{
+		return &errors.SawtoothClientTransportError{
 			ErrorCode: transportError.ErrorCode,
 			ErrorObject: transportError,
 		}
-		return err
 	}
 
 	return nil
@@ -1,17 +1,27 @@
 package zmq
 
 import (
+	"fmt"
+
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/block_pb2"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_block_pb2"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_list_control_pb2"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_state_pb2"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/validator_pb2"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/errors"
 	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
 )
 
-// headToStateRoot returns state root for the block specified.
+// headToStateRoot returns state root for the block specified. Results are cached, since the
+// state root of a block never changes.
 func (self *SawtoothClientTransportZmq) headToStateRoot(blockId string) (string, error) {
+	self.stateRootMutex.Lock()
+	stateRoot, ok := self.stateRoots[blockId]
+	self.stateRootMutex.Unlock()
+	if ok {
+		return stateRoot, nil
+	}
 
 	// Set up the request
 	t := validator_pb2.Message_CLIENT_BLOCK_GET_BY_ID_REQUEST
@@ -22,7 +32,13 @@ func (self *SawtoothClientTransportZmq) headToStateRoot(blockId string) (string,
 	// Send the request and get the response
 	var response client_block_pb2.ClientBlockGetResponse
 	err := self.doZmqRequest(t, &request, &response)
-	if err != nil {
+	if errors.HasErrorCode(err, errors.BLOCK_NOT_FOUND) || errors.HasErrorCode(err, errors.INVALID_RESOURCE_ID) {
+		// Report an unknown head in the same way as the REST API
+		return "", &errors.SawtoothClientTransportError{
+			ErrorCode: errors.INVALID_HEAD,
+			ErrorObject: fmt.Errorf("Head not found: %s", blockId),
+		}
+	} else if err != nil {
 		return "", err
 	}
 
@@ -31,6 +47,13 @@ func (self *SawtoothClientTransportZmq) headToStateRoot(blockId string) (string,
 		return "", err
 	}
 
+	self.stateRootMutex.Lock()
+	if self.stateRoots == nil || len(self.stateRoots) >= STATE_ROOT_CACHE_SIZE {
+		self.stateRoots = make(map[string]string)
+	}
+	self.stateRoots[blockId] = header.StateRootHash
+	self.stateRootMutex.Unlock()
+
 	return header.StateRootHash, nil
 }
 
@@ -91,7 +114,13 @@ func (self *SawtoothClientTransportZmq) GetStateAtHead(address string, head stri
 	return self.getStateAtRoot(address, head, stateRoot)
 }
 
-// getStateAtRoot is used to implement both GetState() and GetStateAtHead()
+// GetStateAtRoot returns the state at the given address, at the given state root.
+// As the state root does not identify a single block, Head is left empty in the result.
+func (self *SawtoothClientTransportZmq) GetStateAtRoot(address string, stateRoot string) (*types.State, error) {
+	return self.getStateAtRoot(address, "", stateRoot)
+}
+
+// getStateAtRoot is used to implement GetState(), GetStateAtHead() and GetStateAtRoot()
 func (self *SawtoothClientTransportZmq) getStateAtRoot(address string, head string, stateRoot string) (*types.State, error) {
 	// Set up the request
 	t := validator_pb2.Message_CLIENT_STATE_GET_REQUEST
@@ -117,9 +146,78 @@ func (self *SawtoothClientTransportZmq) getStateAtRoot(address string, head stri
 	return &state, nil
 }
 
+// GetStates returns the state at each of the given addresses, all read at the current chain head.
+// Addresses that have no state are present in the result with a nil value.
+func (self *SawtoothClientTransportZmq) GetStates(addresses []string) (map[string]*types.State, error) {
+	head, stateRoot, err := self.currentStateRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	return self.getStatesAtRoot(addresses, head, stateRoot)
+}
+
+// GetStatesAtHead returns the state at each of the given addresses, at the given head.
+// Addresses that have no state are present in the result with a nil value.
+func (self *SawtoothClientTransportZmq) GetStatesAtHead(addresses []string, head string) (map[string]*types.State, error) {
+	stateRoot, err := self.headToStateRoot(head)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.getStatesAtRoot(addresses, head, stateRoot)
+}
+
+// getStatesAtRoot is used to implement GetStates() and GetStatesAtHead(). The requests are pipelined
+// over a single connection.
+func (self *SawtoothClientTransportZmq) getStatesAtRoot(addresses []string, head string, stateRoot string) (map[string]*types.State, error) {
+	// Build one request per (deduplicated) address
+	seen := make(map[string]bool, len(addresses))
+	requests := make([]proto.Message, 0, len(addresses))
+	responses := make([]proto.Message, 0, len(addresses))
+	for _, address := range addresses {
+		if seen[address] {
+			continue
+		}
+		seen[address] = true
+
+		requests = append(requests, &client_state_pb2.ClientStateGetRequest{StateRoot: stateRoot, Address: address})
+		responses = append(responses, &client_state_pb2.ClientStateGetResponse{})
+	}
+
+	// Send the requests and get the responses
+	t := validator_pb2.Message_CLIENT_STATE_GET_REQUEST
+	results, err := self.doZmqPipelinedRequests(t, requests, responses)
+	if err != nil {
+		return nil, err
+	}
+
+	// Populate the result map
+	result := make(map[string]*types.State, len(requests))
+	for i, request := range requests {
+		address := request.(*client_state_pb2.ClientStateGetRequest).Address
+
+		err = results[i]
+		if errors.HasErrorCode(err, errors.STATE_NOT_FOUND) {
+			result[address] = nil
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		result[address] = &types.State{
+			Data: responses[i].(*client_state_pb2.ClientStateGetResponse).Value,
+			Address: address,
+			Head: head,
+		}
+	}
+
+	return result, nil
+}
+
 type stateZmqIterator struct {
 	commonZmqIterator
-	head		string
+	stateHead	string
 	stateRoot	string
 	address		string
 }
@@ -134,7 +232,7 @@ func (self *stateZmqIterator) Current() (*types.State, error) {
 	return data, nil
 }
 
-func (self *stateZmqIterator) BuildRequest(pagingControl *client_list_control_pb2.ClientPagingControls, sortControl []*client_list_control_pb2.ClientSortControls) (validator_pb2.Message_MessageType, proto.Message, proto.Message) {
+func (self *stateZmqIterator) BuildRequest(head string, pagingControl *client_list_control_pb2.ClientPagingControls, sortControl []*client_list_control_pb2.ClientSortControls) (validator_pb2.Message_MessageType, proto.Message, proto.Message) {
 	t := validator_pb2.Message_CLIENT_STATE_LIST_REQUEST
 	request := client_state_pb2.ClientStateListRequest{Address: self.address, StateRoot: self.stateRoot, Paging: pagingControl, Sorting: sortControl}
 	response := client_state_pb2.ClientStateListResponse{}
@@ -149,7 +247,7 @@ func (self *stateZmqIterator) ParseProto(message proto.Message) ([]interface{},
 		state := &types.State{
 			Data: item.Data,
 			Address: item.Address,
-			Head: self.head,
+			Head: self.stateHead,
 		}
 		result[i] = state
 	}
@@ -157,30 +255,74 @@ func (self *stateZmqIterator) ParseProto(message proto.Message) ([]interface{},
 	return result, nil
 }
 
-// GetStateData returns a types.StateIterator that can iterate over all state matching the given prefix.
+// GetStateIterator returns a types.StateIterator that can iterate over all state matching the given prefix.
 func (self *SawtoothClientTransportZmq) GetStateIterator(addressPrefix string, fetch int, reverse bool) types.StateIterator {
-	pagingControl := &client_list_control_pb2.ClientPagingControls{
-		Limit: int32(fetch),
+	head, stateRoot, err := self.currentStateRoot()
+	if err != nil {
+		return self.newStateIteratorWithError(err)
 	}
 
-	sortControl := []*client_list_control_pb2.ClientSortControls{
-		{
-			Keys: []string{"default"},
-			Reverse: reverse,
-		},
+	return self.getStateIteratorAtRoot(addressPrefix, head, stateRoot, fetch, reverse)
+}
+
+// GetStateIteratorAtHead returns a types.StateIterator that can iterate over all state matching the
+// given prefix, at the given head.
+func (self *SawtoothClientTransportZmq) GetStateIteratorAtHead(addressPrefix string, head string, fetch int, reverse bool) types.StateIterator {
+	stateRoot, err := self.headToStateRoot(head)
+	if err != nil {
+		return self.newStateIteratorWithError(err)
 	}
 
-	iterator := &stateZmqIterator{address: addressPrefix}
-	iterator.commonZmqIterator = *NewCommonZmqIterator(self, pagingControl, sortControl, iterator)
+	return self.getStateIteratorAtRoot(addressPrefix, head, stateRoot, fetch, reverse)
+}
 
-	head, stateRoot, err := self.currentStateRoot()
-	if err != nil {
-		iterator.err = err
-		return iterator
+// GetStateIteratorAtRoot returns a types.StateIterator that can iterate over all state matching the
+// given prefix, at the given state root. As the state root does not identify a single block, Head
+// is left empty in the results.
+func (self *SawtoothClientTransportZmq) GetStateIteratorAtRoot(addressPrefix string, stateRoot string, fetch int, reverse bool) types.StateIterator {
+	return self.getStateIteratorAtRoot(addressPrefix, "", stateRoot, fetch, reverse)
+}
+
+// GetStateIteratorFromCursor returns a types.StateIterator that resumes the iteration recorded by cursor.
+func (self *SawtoothClientTransportZmq) GetStateIteratorFromCursor(cursor *types.Cursor) types.StateIterator {
+	// Cursors created over REST only record the head
+	if cursor != nil && cursor.StateRoot == "" && cursor.Head != "" {
+		stateRoot, err := self.headToStateRoot(cursor.Head)
+		if err != nil {
+			return self.newStateIteratorWithError(err)
+		}
+
+		resolved := *cursor
+		resolved.StateRoot = stateRoot
+		cursor = &resolved
 	}
 
-	iterator.head = head
-	iterator.stateRoot = stateRoot
+	iterator := &stateZmqIterator{}
+	iterator.commonZmqIterator = *newCursorZmqIterator(self, "default", types.CURSOR_STATE, cursor, iterator)
+	if iterator.err == nil {
+		iterator.address = cursor.Address
+		iterator.stateHead = cursor.Head
+		iterator.stateRoot = cursor.StateRoot
+	}
+
+	return iterator
+}
+
+// getStateIteratorAtRoot is used to implement the state iterator methods.
+func (self *SawtoothClientTransportZmq) getStateIteratorAtRoot(addressPrefix string, head string, stateRoot string, fetch int, reverse bool) types.StateIterator {
+	cursor := types.NewCursor(types.CURSOR_STATE, fetch, reverse)
+	cursor.Address = addressPrefix
+	cursor.Head = head
+	cursor.StateRoot = stateRoot
+
+	return self.GetStateIteratorFromCursor(cursor)
+}
+
+// newStateIteratorWithError returns a state iterator that yields no values and reports err.
+func (self *SawtoothClientTransportZmq) newStateIteratorWithError(err error) types.StateIterator {
+	iterator := &stateZmqIterator{}
+	iterator.commonZmqIterator = *NewCommonZmqIterator(self, nil, nil, iterator)
+	iterator.err = err
 
 	return iterator
 }
@@ -47,9 +47,9 @@ func (self *transactionZmqIterator) Current() (*types.Transaction, error) {
 	return data, nil
 }
 
-func (self *transactionZmqIterator) BuildRequest(pagingControl *client_list_control_pb2.ClientPagingControls, sortControl []*client_list_control_pb2.ClientSortControls) (validator_pb2.Message_MessageType, proto.Message, proto.Message) {
+func (self *transactionZmqIterator) BuildRequest(head string, pagingControl *client_list_control_pb2.ClientPagingControls, sortControl []*client_list_control_pb2.ClientSortControls) (validator_pb2.Message_MessageType, proto.Message, proto.Message) {
 	t := validator_pb2.Message_CLIENT_TRANSACTION_LIST_REQUEST
-	request := client_transaction_pb2.ClientTransactionListRequest{Paging: pagingControl, Sorting: sortControl}
+	request := client_transaction_pb2.ClientTransactionListRequest{HeadId: head, Paging: pagingControl, Sorting: sortControl}
 	response := client_transaction_pb2.ClientTransactionListResponse{}
 	return t, &request, &response
 }
@@ -71,19 +71,13 @@ func (self *transactionZmqIterator) ParseProto(message proto.Message) ([]interfa
 
 // GetTransactionIterator returns a types.TransactionIterator that can iterate over all transactions.
 func (self *SawtoothClientTransportZmq) GetTransactionIterator(fetch int, reverse bool) types.TransactionIterator {
-	pagingControl := &client_list_control_pb2.ClientPagingControls{
-		Limit: int32(fetch),
-	}
-
-	sortControl := []*client_list_control_pb2.ClientSortControls{
-		{
-			Keys: []string{"default"},
-			Reverse: reverse,
-		},
-	}
+	return self.GetTransactionIteratorFromCursor(types.NewCursor(types.CURSOR_TRANSACTIONS, fetch, reverse))
+}
 
+// GetTransactionIteratorFromCursor returns a types.TransactionIterator that resumes the iteration recorded by cursor.
+func (self *SawtoothClientTransportZmq) GetTransactionIteratorFromCursor(cursor *types.Cursor) types.TransactionIterator {
 	iterator := &transactionZmqIterator{}
-	iterator.commonZmqIterator = *NewCommonZmqIterator(self, pagingControl, sortControl, iterator)
+	iterator.commonZmqIterator = *newCursorZmqIterator(self, "default", types.CURSOR_TRANSACTIONS, cursor, iterator)
 
 	return iterator
 }
@@ -4,6 +4,7 @@ package zmq
 import (
 	"fmt"
 	"net/url"
+	"sync"
 	"github.com/pebbe/zmq4"
 	"github.com/hyperledger/sawtooth-sdk-go/messaging"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_peers_pb2"
@@ -13,12 +14,26 @@ import (
 
 const MAX_CONNECTIONS = 8
 
+// PIPELINE_DEPTH is the maximum number of requests in flight on a connection during bulk operations.
+const PIPELINE_DEPTH = 64
+
+// STATE_ROOT_CACHE_SIZE is the number of block id to state root mappings kept in memory.
+const STATE_ROOT_CACHE_SIZE = 1024
+
+// ZmqConnection is the subset of messaging.Connection used by the transport.
+type ZmqConnection interface {
+	SendNewMsg(t validator_pb2.Message_MessageType, c []byte) (string, error)
+	RecvMsgWithId(corrId string) (string, *validator_pb2.Message, error)
+	Close()
+	Identity() string
+}
+
 type sawtoothZmqConnection struct {
-	messaging.Connection
+	ZmqConnection
 }
 
 func (self *sawtoothZmqConnection) String() string {
-	return fmt.Sprintf("sawtoothZmqConnection(identity=%s)", self.Connection.Identity())
+	return fmt.Sprintf("sawtoothZmqConnection(identity=%s)", self.ZmqConnection.Identity())
 }
 
 // SawtoothClientTransportZmq represents a connection to the validator via ZMQ.
@@ -32,19 +47,75 @@ type SawtoothClientTransportZmq struct {
 	// ConnectionChannel is a channel to hold ZMQ connections
 	ConnectionChannel		chan *sawtoothZmqConnection
 
+	// Curve holds the CurveZMQ settings (nil if connections are not encrypted)
+	Curve		*CurveOptions
+
+	// Prefetch is the default number of pages iterators read ahead in the background (0 disables)
+	Prefetch	int
+
+	// dial, if set, replaces connecting to the validator
+	dial			func() (ZmqConnection, error)
+
+	// stateRoots caches the state roots of blocks looked up by headToStateRoot
+	stateRoots		map[string]string
+	stateRootMutex	sync.Mutex
+
 	// Context for logging
 	logContext	*log.Entry
 }
 
+// SawtoothClientTransportZmqOptions holds optional settings for SawtoothClientTransportZmq.
+type SawtoothClientTransportZmqOptions struct {
+	// Curve enables CurveZMQ encryption and authentication of connections to the validator.
+	Curve		*CurveOptions
+	// Prefetch is the default number of pages iterators read ahead in the background (0 disables).
+	Prefetch	int
+}
+
 // NewSawtoothClientTransportZmq returns a new SawtoothClientTransportZmq for the given URL.
 // Returns an error if a test request to the validator does not succeed.
 func NewSawtoothClientTransportZmq(url *url.URL) (*SawtoothClientTransportZmq, error) {
+	return NewSawtoothClientTransportZmqWithOptions(url, nil)
+}
+
+// NewSawtoothClientTransportZmqWithOptions returns a new SawtoothClientTransportZmq for the given URL,
+// configured with the given options (which may be nil).
+// Returns an error if a test request to the validator does not succeed.
+func NewSawtoothClientTransportZmqWithOptions(url *url.URL, options *SawtoothClientTransportZmqOptions) (*SawtoothClientTransportZmq, error) {
 	// Create a new transport object
 	client := &SawtoothClientTransportZmq{
 		URL: url,
 		logContext: log.WithField("object", "SawtoothClientTransportZmq"),
 	}
 
+	if options != nil {
+		client.Prefetch = options.Prefetch
+	}
+
+	// Set up CurveZMQ, generating an ephemeral client keypair if none was given
+	if options != nil && options.Curve != nil {
+		curve := *options.Curve
+
+		err := validateCurveKey(curve.ServerPublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid CurveZMQ server public key: %s", err)
+		}
+
+		if curve.ClientKeys == nil {
+			curve.ClientKeys, err = NewCurveKeyPair()
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			err = curve.ClientKeys.Validate()
+			if err != nil {
+				return nil, fmt.Errorf("Invalid CurveZMQ client keypair: %s", err)
+			}
+		}
+
+		client.Curve = &curve
+	}
+
 	// Create a ZMQ context
 	context, err := zmq4.NewContext()
 	if err != nil {
@@ -64,18 +135,45 @@ func NewSawtoothClientTransportZmq(url *url.URL) (*SawtoothClientTransportZmq, e
 	return client, nil
 }
 
+// NewSawtoothClientTransportZmqWithDialer returns a new SawtoothClientTransportZmq whose connections
+// are created by dial instead of connecting to a validator, such as connections to the validator of
+// the conformance package. Returns an error if a test request does not succeed.
+func NewSawtoothClientTransportZmqWithDialer(dial func() (ZmqConnection, error)) (*SawtoothClientTransportZmq, error) {
+	client := &SawtoothClientTransportZmq{
+		URL: &url.URL{Scheme: "tcp", Host: "validator:4004"},
+		ConnectionChannel: make(chan *sawtoothZmqConnection, MAX_CONNECTIONS),
+		dial: dial,
+		logContext: log.WithField("object", "SawtoothClientTransportZmq"),
+	}
+
+	err := client.testConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
 // Create a new ZMQ connection
 func (self *SawtoothClientTransportZmq) newConnection() (*sawtoothZmqConnection, error) {
 	logContext := self.logContext.WithField("method", "newConnection")
 	logContext.Trace()
 
-	rawConn, err := messaging.NewConnection(self.Context, zmq4.DEALER, self.URL.String(), false)
+	var rawConn ZmqConnection
+	var err error
+	if self.dial != nil {
+		rawConn, err = self.dial()
+	} else if self.Curve != nil {
+		rawConn, err = newCurveZmqConnection(self.Context, self.URL.String(), self.Curve)
+	} else {
+		rawConn, err = messaging.NewConnection(self.Context, zmq4.DEALER, self.URL.String(), false)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	conn := &sawtoothZmqConnection{
-		Connection: rawConn,
+		ZmqConnection: rawConn,
 	}
 
 	return conn, nil
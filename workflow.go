@@ -0,0 +1,431 @@
+package sawtooth_client_sdk_go
+
+import (
+	"context"
+	"fmt"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WorkflowOptions holds optional settings for a Workflow.
+type WorkflowOptions struct {
+	// InferDependencies makes each step depend on the steps preceding it whose addresses conflict
+	// with its own: a step writing an address read or written by the step, or reading an address
+	// the step writes. Addresses conflict if one is a prefix of the other. Steps are taken in the
+	// order the workflow would be built from the named dependencies alone, so an inferred
+	// dependency never contradicts a named one.
+	InferDependencies	bool
+}
+
+// Workflow builds the transactions of a multi-transaction workflow, in which payloads (steps)
+// depend on each other by name. When the workflow is built, the transactions are created in an
+// order where each step follows the steps it depends on, and their ids are added to its
+// dependencies. Steps are grouped into batches with NextBatch; a step may depend on steps in
+// other batches, and the batches are ordered accordingly.
+type Workflow struct {
+	client		*SawtoothClient
+	options		WorkflowOptions
+
+	steps		[]*workflowStep
+	stepIndex	map[string]*workflowStep
+	batchCount	int
+
+	// batches holds the built batches, in submission order (nil until built)
+	batches		[]*batch_pb2.Batch
+}
+
+// workflowStep is a payload added to a workflow.
+type workflowStep struct {
+	name			string
+	payload			SawtoothPayload
+	batch			int
+	order			int
+	dependsOn		[]string
+	// dependencies holds the resolved dependencies (explicit and inferred), by add order
+	dependencies	[]*workflowStep
+	transaction		*transaction_pb2.Transaction
+}
+
+// NewWorkflow returns an empty Workflow creating its transactions with this client.
+// options may be nil.
+func (self *SawtoothClient) NewWorkflow(options *WorkflowOptions) *Workflow {
+	workflow := &Workflow{client: self, stepIndex: make(map[string]*workflowStep), batchCount: 1}
+	if options != nil {
+		workflow.options = *options
+	}
+
+	return workflow
+}
+
+// Add adds a payload to the current batch as the step name, depending on the steps named in
+// dependsOn. These may be in any batch, and may be added later, before the workflow is built.
+// Dependencies declared by the payload itself (which must be transaction ids) are kept.
+func (self *Workflow) Add(name string, payload SawtoothPayload, dependsOn ...string) error {
+	if self.batches != nil {
+		return fmt.Errorf("Workflow has already been built")
+	}
+	if name == "" {
+		return fmt.Errorf("Workflow step name must not be empty")
+	}
+	if _, exists := self.stepIndex[name]; exists {
+		return fmt.Errorf("Workflow step %s already exists", name)
+	}
+
+	step := &workflowStep{
+		name: name,
+		payload: payload,
+		batch: self.batchCount - 1,
+		order: len(self.steps),
+		dependsOn: dependsOn,
+	}
+	self.steps = append(self.steps, step)
+	self.stepIndex[name] = step
+
+	return nil
+}
+
+// NextBatch starts a new batch: the steps added after it are submitted in a separate batch. It
+// has no effect if no step has been added to the current batch.
+func (self *Workflow) NextBatch() {
+	if len(self.steps) > 0 && self.steps[len(self.steps) - 1].batch == self.batchCount - 1 {
+		self.batchCount++
+	}
+}
+
+// Build creates and signs the transactions and batches of the workflow, and returns the batches
+// in the order they must be submitted. The workflow cannot be changed once built, and later calls
+// return the same batches.
+func (self *Workflow) Build() ([]*batch_pb2.Batch, error) {
+	return self.BuildContext(context.Background())
+}
+
+// BuildContext is Build, passing ctx to the signers.
+func (self *Workflow) BuildContext(ctx context.Context) ([]*batch_pb2.Batch, error) {
+	if self.batches != nil {
+		return self.batches, nil
+	}
+	if len(self.steps) == 0 {
+		return nil, fmt.Errorf("Workflow has no steps")
+	}
+
+	err := self.resolveDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	batchOrder, err := self.orderBatches()
+	if err != nil {
+		return nil, err
+	}
+
+	batcherPublicKey := self.client.BatcherPublicKey()
+	batches := make([]*batch_pb2.Batch, 0, len(batchOrder))
+	for _, batchIndex := range batchOrder {
+		steps, err := self.orderSteps(batchIndex)
+		if err != nil {
+			return nil, err
+		}
+
+		// Steps follow their dependencies, so the ids of the dependencies are known
+		transactions := make([]*transaction_pb2.Transaction, len(steps))
+		for i, step := range steps {
+			step.transaction, err = self.client.createTransaction(ctx, step.payload, batcherPublicKey, step.dependencyIds())
+			if err != nil {
+				return nil, fmt.Errorf("Error while creating transaction for step %s: %s", step.name, err)
+			}
+			transactions[i] = step.transaction
+		}
+
+		batch, err := self.client.CreateBatchWithSignerContext(ctx, transactions, self.client.batchSigner())
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+
+	self.batches = batches
+	return batches, nil
+}
+
+// TransactionId returns the id of the transaction of a step, once the workflow has been built.
+func (self *Workflow) TransactionId(name string) (string, error) {
+	step, ok := self.stepIndex[name]
+	if !ok {
+		return "", fmt.Errorf("Workflow step %s does not exist", name)
+	}
+	if step.transaction == nil {
+		return "", fmt.Errorf("Workflow has not been built")
+	}
+
+	return step.transaction.HeaderSignature, nil
+}
+
+// Submit builds the workflow if needed, submits its batches (in a single batch list) and returns
+// a submission result for each batch, in submission order.
+func (self *Workflow) Submit() ([]*SubmissionResult, error) {
+	batches, err := self.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*SubmissionResult, len(batches))
+	for i, batch := range batches {
+		results[i], err = newSubmissionResult(batch, batch.Transactions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	batchList, err := self.client.CreateBatchList(batches)
+	if err != nil {
+		return nil, err
+	}
+
+	err = self.client.Transport.SubmitBatchList(batchList)
+	if err != nil {
+		return nil, err
+	}
+
+	submittedAt := time.Now()
+	for _, result := range results {
+		result.SubmittedAt = submittedAt
+	}
+
+	return results, nil
+}
+
+// SubmitSync submits the workflow as Submit does, and waits for each batch to be committed (see
+// WaitCommit). If the batches were submitted but an error occurs while waiting, the results are
+// returned along with the error.
+func (self *Workflow) SubmitSync(timeout int, pollInterval int) ([]*SubmissionResult, error) {
+	results, err := self.Submit()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		err = self.client.WaitCommit(result, timeout, pollInterval)
+		if err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+// resolveDependencies resolves the named dependencies of each step, and infers dependencies from
+// addresses if enabled.
+func (self *Workflow) resolveDependencies() error {
+	dependencies := make(map[*workflowStep]map[*workflowStep]bool, len(self.steps))
+	for _, step := range self.steps {
+		dependencies[step] = make(map[*workflowStep]bool)
+
+		for _, name := range step.dependsOn {
+			dependency, ok := self.stepIndex[name]
+			if !ok {
+				return fmt.Errorf("Workflow step %s depends on unknown step %s", step.name, name)
+			}
+			if dependency == step {
+				return fmt.Errorf("Workflow step %s depends on itself", step.name)
+			}
+			dependencies[step][dependency] = true
+		}
+		step.setDependencies(dependencies[step])
+	}
+
+	if !self.options.InferDependencies {
+		return nil
+	}
+
+	// Inferred dependencies follow the build order of the named dependencies, so they cannot
+	// introduce a cycle
+	var order []*workflowStep
+	batchOrder, err := self.orderBatches()
+	if err != nil {
+		return err
+	}
+	for _, batchIndex := range batchOrder {
+		steps, err := self.orderSteps(batchIndex)
+		if err != nil {
+			return err
+		}
+		order = append(order, steps...)
+	}
+
+	for i, step := range order {
+		for _, earlier := range order[:i] {
+			if stepsConflict(earlier, step) {
+				dependencies[step][earlier] = true
+			}
+		}
+		step.setDependencies(dependencies[step])
+	}
+
+	return nil
+}
+
+// orderBatches returns the batch indexes in an order where each batch follows the batches its
+// steps depend on. Batches are otherwise kept in the order they were started.
+func (self *Workflow) orderBatches() ([]int, error) {
+	after := make([]map[int]bool, self.batchCount)
+	for i := range after {
+		after[i] = make(map[int]bool)
+	}
+	for _, step := range self.steps {
+		for _, dependency := range step.dependencies {
+			if dependency.batch != step.batch {
+				after[step.batch][dependency.batch] = true
+			}
+		}
+	}
+
+	indexes := make([]int, self.batchCount)
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	order, ok := topologicalOrder(indexes, func(batch int) []int {
+		var batches []int
+		for dependency := range after[batch] {
+			batches = append(batches, dependency)
+		}
+		sort.Ints(batches)
+		return batches
+	})
+	if !ok {
+		return nil, fmt.Errorf("Workflow batches have circular dependencies")
+	}
+
+	return order, nil
+}
+
+// orderSteps returns the steps of a batch in an order where each step follows the steps it
+// depends on. Steps are otherwise kept in the order they were added.
+func (self *Workflow) orderSteps(batch int) ([]*workflowStep, error) {
+	var steps []*workflowStep
+	for _, step := range self.steps {
+		if step.batch == batch {
+			steps = append(steps, step)
+		}
+	}
+
+	order, ok := topologicalOrder(steps, func(step *workflowStep) []*workflowStep {
+		var dependencies []*workflowStep
+		for _, dependency := range step.dependencies {
+			if dependency.batch == batch {
+				dependencies = append(dependencies, dependency)
+			}
+		}
+		return dependencies
+	})
+	if !ok {
+		var names []string
+		for _, step := range steps {
+			names = append(names, step.name)
+		}
+		return nil, fmt.Errorf("Workflow steps have circular dependencies: %s", strings.Join(names, ", "))
+	}
+
+	return order, nil
+}
+
+// setDependencies sets the resolved dependencies of the step, by add order.
+func (self *workflowStep) setDependencies(dependencies map[*workflowStep]bool) {
+	self.dependencies = self.dependencies[:0]
+	for dependency := range dependencies {
+		self.dependencies = append(self.dependencies, dependency)
+	}
+	sort.Slice(self.dependencies, func(i int, j int) bool {
+		return self.dependencies[i].order < self.dependencies[j].order
+	})
+}
+
+// dependencyIds returns the transaction ids of the step's dependencies which are not already
+// declared by its payload.
+func (self *workflowStep) dependencyIds() []string {
+	declared := make(map[string]bool)
+	for _, transactionId := range self.payload.GetDependencies() {
+		declared[strings.ToLower(transactionId)] = true
+	}
+
+	var transactionIds []string
+	for _, dependency := range self.dependencies {
+		transactionId := dependency.transaction.HeaderSignature
+		if !declared[transactionId] {
+			transactionIds = append(transactionIds, transactionId)
+		}
+	}
+
+	return transactionIds
+}
+
+// topologicalOrder returns the items in an order where each item follows those returned by
+// dependencies (all of which must be in items), keeping the given order where possible. Returns
+// false if the dependencies are circular.
+func topologicalOrder[T comparable](items []T, dependencies func(T) []T) ([]T, bool) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[T]int, len(items))
+	order := make([]T, 0, len(items))
+
+	var visit func(item T) bool
+	visit = func(item T) bool {
+		switch state[item] {
+		case visiting:
+			return false
+		case visited:
+			return true
+		}
+
+		state[item] = visiting
+		for _, dependency := range dependencies(item) {
+			if !visit(dependency) {
+				return false
+			}
+		}
+		state[item] = visited
+		order = append(order, item)
+
+		return true
+	}
+
+	for _, item := range items {
+		if !visit(item) {
+			return nil, false
+		}
+	}
+
+	return order, true
+}
+
+// stepsConflict returns true if a later step must follow an earlier one, because one writes an
+// address the other reads or writes.
+func stepsConflict(earlier *workflowStep, later *workflowStep) bool {
+	earlierOutputs := earlier.payload.GetOutputAddresses()
+	laterOutputs := later.payload.GetOutputAddresses()
+
+	return addressesOverlap(earlierOutputs, later.payload.GetInputAddresses()) ||
+		addressesOverlap(earlierOutputs, laterOutputs) ||
+		addressesOverlap(earlier.payload.GetInputAddresses(), laterOutputs)
+}
+
+// addressesOverlap returns true if an address (or address prefix) in a is a prefix of one in b,
+// or the reverse.
+func addressesOverlap(a []string, b []string) bool {
+	for _, addressA := range a {
+		for _, addressB := range b {
+			if strings.HasPrefix(addressA, addressB) || strings.HasPrefix(addressB, addressA) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
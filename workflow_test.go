@@ -0,0 +1,202 @@
+package sawtooth_client_sdk_go_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	sawtooth_client_sdk_go "github.com/taekion-org/sawtooth-client-sdk-go"
+	"github.com/taekion-org/sawtooth-client-sdk-go/signer"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/conformance"
+	"github.com/taekion-org/sawtooth-client-sdk-go/transport/types"
+)
+
+func TestWorkflow(t *testing.T) {
+	fixture := conformance.NewFixture()
+	clientTransport := conformance.NewRestTransport(t, conformance.NewValidator(fixture))
+
+	client := &sawtooth_client_sdk_go.SawtoothClient{Signer: signer.NewMemorySignerFromSigner(fixture.Signer), Transport: clientTransport, ClientImpl: &fixtureImpl{}}
+	external := fixture.Head().Batches[0].Transactions[0].HeaderSignature
+
+	// checkBatches checks the steps of each batch (in order), and the dependencies of each step
+	checkBatches := func(description string, workflow *sawtooth_client_sdk_go.Workflow, batches []*batch_pb2.Batch, expected [][]string, dependencies map[string][]string) {
+		t.Helper()
+
+		transactionIds := make(map[string]string)
+		for _, steps := range expected {
+			for _, name := range steps {
+				transactionId, err := workflow.TransactionId(name)
+				if err != nil {
+					t.Fatalf("%s: TransactionId(%s): %s", description, name, err)
+				}
+				transactionIds[name] = transactionId
+			}
+		}
+
+		if len(batches) != len(expected) {
+			t.Fatalf("%s: got %d batches, expected %d", description, len(batches), len(expected))
+		}
+		for i, batch := range batches {
+			decoded, err := types.BatchFromProto(batch)
+			if err == nil {
+				err = decoded.Verify()
+			}
+			if err != nil {
+				t.Fatalf("%s: %s", description, err)
+			}
+			if len(decoded.Transactions) != len(expected[i]) {
+				t.Fatalf("%s: batch %d has %d transactions, expected %d", description, i, len(decoded.Transactions), len(expected[i]))
+			}
+
+			for j, transaction := range decoded.Transactions {
+				name := expected[i][j]
+				if transaction.HeaderSignature != transactionIds[name] {
+					t.Fatalf("%s: transaction %d of batch %d is not step %s", description, j, i, name)
+				}
+
+				var want []string
+				for _, dependency := range dependencies[name] {
+					if transactionId, ok := transactionIds[dependency]; ok {
+						want = append(want, transactionId)
+					} else {
+						want = append(want, dependency)
+					}
+				}
+				if fmt.Sprint(transaction.Header.Dependencies) != fmt.Sprint(want) {
+					t.Fatalf("%s: step %s has dependencies %v, expected %v", description, name, transaction.Header.Dependencies, want)
+				}
+			}
+		}
+	}
+
+	// Steps follow their dependencies, including those added later and those in other batches
+	workflow := client.NewWorkflow(nil)
+	workflow.Add("transfer", &fixturePayload{Value: "transfer", Dependencies: []string{external}}, "create", "approve")
+	workflow.Add("create", &fixturePayload{Value: "create"})
+	workflow.NextBatch()
+	workflow.NextBatch()
+	workflow.Add("approve", &fixturePayload{Value: "approve"})
+	workflow.Add("audit", &fixturePayload{Value: "audit"}, "approve")
+
+	err := workflow.Add("create", &fixturePayload{Value: "duplicate"})
+	if err == nil {
+		t.Fatalf("Add(duplicate): expected an error")
+	}
+	_, err = workflow.TransactionId("create")
+	if err == nil {
+		t.Fatalf("TransactionId(not built): expected an error")
+	}
+
+	batches, err := workflow.Build()
+	if err != nil {
+		t.Fatalf("Build: %s", err)
+	}
+	checkBatches("Build", workflow, batches, [][]string{{"approve", "audit"}, {"create", "transfer"}}, map[string][]string{
+		"transfer": {external, "create", "approve"},
+		"audit": {"approve"},
+	})
+	rebuilt, err := workflow.Build()
+	if err != nil || rebuilt[0].HeaderSignature != batches[0].HeaderSignature {
+		t.Fatalf("Build(again): got different batches (%v)", err)
+	}
+	err = workflow.Add("late", &fixturePayload{Value: "late"})
+	if err == nil {
+		t.Fatalf("Add(built): expected an error")
+	}
+
+	// Dependencies can be inferred from conflicting addresses
+	address := conformance.FixtureAddress(0)
+	inferred := client.NewWorkflow(&sawtooth_client_sdk_go.WorkflowOptions{InferDependencies: true})
+	inferred.Add("write", &fixturePayload{Value: "write", Outputs: []string{address}})
+	inferred.Add("read", &fixturePayload{Value: "read", Inputs: []string{address}})
+	inferred.Add("unrelated", &fixturePayload{Value: "unrelated", Inputs: []string{conformance.FixtureAddress(1)}, Outputs: []string{conformance.FixtureAddress(1)}})
+	inferred.NextBatch()
+	inferred.Add("overwrite", &fixturePayload{Value: "overwrite", Outputs: []string{conformance.FIXTURE_NAMESPACE}}, "read")
+
+	batches, err = inferred.Build()
+	if err != nil {
+		t.Fatalf("Build(inferred): %s", err)
+	}
+	checkBatches("Build(inferred)", inferred, batches, [][]string{{"write", "read", "unrelated"}, {"overwrite"}}, map[string][]string{
+		"read": {"write"},
+		"overwrite": {"write", "read", "unrelated"},
+	})
+
+	// Inferred dependencies follow the named ones, even on a step added later
+	named := client.NewWorkflow(&sawtooth_client_sdk_go.WorkflowOptions{InferDependencies: true})
+	named.Add("apply", &fixturePayload{Value: "apply", Outputs: []string{address}}, "prepare")
+	named.Add("prepare", &fixturePayload{Value: "prepare", Outputs: []string{address}})
+
+	namedBatches, err := named.Build()
+	if err != nil {
+		t.Fatalf("Build(named): %s", err)
+	}
+	checkBatches("Build(named)", named, namedBatches, [][]string{{"prepare", "apply"}}, map[string][]string{
+		"apply": {"prepare"},
+	})
+
+	// Batches depending on several others are always ordered the same way
+	for i := 0; i < 10; i++ {
+		ordered := client.NewWorkflow(nil)
+		ordered.Add("final", &fixturePayload{Value: "final"}, "third", "second")
+		ordered.NextBatch()
+		ordered.Add("second", &fixturePayload{Value: "second"})
+		ordered.NextBatch()
+		ordered.Add("third", &fixturePayload{Value: "third"})
+
+		orderedBatches, err := ordered.Build()
+		if err != nil {
+			t.Fatalf("Build(ordered): %s", err)
+		}
+		checkBatches("Build(ordered)", ordered, orderedBatches, [][]string{{"second"}, {"third"}, {"final"}}, map[string][]string{
+			"final": {"second", "third"},
+		})
+	}
+
+	// Unknown and circular dependencies are rejected
+	invalid := []func(*sawtooth_client_sdk_go.Workflow){
+		func(workflow *sawtooth_client_sdk_go.Workflow) {
+			workflow.Add("a", &fixturePayload{Value: "a"}, "missing")
+		},
+		func(workflow *sawtooth_client_sdk_go.Workflow) {
+			workflow.Add("a", &fixturePayload{Value: "a"}, "a")
+		},
+		func(workflow *sawtooth_client_sdk_go.Workflow) {
+			workflow.Add("a", &fixturePayload{Value: "a"}, "b")
+			workflow.Add("b", &fixturePayload{Value: "b"}, "a")
+		},
+		func(workflow *sawtooth_client_sdk_go.Workflow) {
+			workflow.Add("a", &fixturePayload{Value: "a"}, "b")
+			workflow.NextBatch()
+			workflow.Add("b", &fixturePayload{Value: "b"}, "a")
+		},
+		func(workflow *sawtooth_client_sdk_go.Workflow) {},
+	}
+	for i, build := range invalid {
+		workflow := client.NewWorkflow(nil)
+		build(workflow)
+		_, err = workflow.Build()
+		if err == nil {
+			t.Fatalf("Build(invalid %d): expected an error", i)
+		}
+	}
+
+	// A submitted workflow is committed with its dependencies
+	fixture.AutoCommit = true
+	results, err := inferred.SubmitSync(5, 1)
+	if err != nil {
+		t.Fatalf("SubmitSync: %s", err)
+	}
+	if len(results) != 2 || !results[0].Committed() || !results[1].Committed() || results[1].Transactions[0].TransactionId != batches[1].Transactions[0].HeaderSignature {
+		t.Fatalf("SubmitSync: unexpected results %+v", results)
+	}
+	readId, _ := inferred.TransactionId("read")
+	overwriteId, _ := inferred.TransactionId("overwrite")
+	committed, err := clientTransport.GetTransaction(overwriteId)
+	if err != nil {
+		t.Fatalf("GetTransaction: %s", err)
+	}
+	if len(committed.Header.Dependencies) != 3 || committed.Header.Dependencies[1] != readId {
+		t.Fatalf("GetTransaction: unexpected dependencies %v", committed.Header.Dependencies)
+	}
+}